@@ -5,101 +5,819 @@ import (
 	"flag"
 	"fmt"
 	"log"
-	"math/rand"
+	"os"
+	"os/signal"
+	"path/filepath"
 	"runtime"
+	"strings"
+	"syscall"
 	"time"
 
+	"auction-simulator/internal/bidder"
+	"auction-simulator/internal/grpcstream"
+	"auction-simulator/internal/kafka"
 	"auction-simulator/internal/manager"
-	"auction-simulator/internal/resource"
+	"auction-simulator/internal/metrics"
+	"auction-simulator/internal/otlp"
+	"auction-simulator/internal/server"
+	"auction-simulator/internal/webhook"
 	"auction-simulator/pkg/models"
+	"auction-simulator/pkg/simulator"
 )
 
 func main() {
 	// Parse command-line flags
 	maxCPUs := flag.Int("cpus", runtime.NumCPU(), "Maximum number of CPUs to use")
+	maxMemory := flag.Int64("max-memory", 0, "Soft memory limit in MB: while allocated memory exceeds it, no new auction is launched (0 = no limit)")
+	cpuQuota := flag.Float64("cpu-quota", 0, "Hold bidder work to this many cores, which may be fractional (e.g. 1.5), by pausing it once the quota is used (0 = off)")
 	outputDir := flag.String("output", "output", "Output directory for results")
+	numAuctions := flag.Int("auctions", manager.NumAuctions, "Number of auctions to run")
+	numBidders := flag.Int("bidders", manager.NumBidders, "Number of generated bidders")
 	seed := flag.Int64("seed", time.Now().UnixNano(), "Random seed for reproducibility")
+	serial := flag.Bool("serial", false, "Run auctions one at a time on a single goroutine with simulated time, for deterministic results")
+	submissionOrder := flag.String("submission-order", string(models.SubmitByDelay), "Serial mode: the order bids reach the collector: delay, bidder-id, shuffle or schedule:FILE (a JSON object of bidder ID to delay)")
+	serveAddr := flag.String("serve", "", "Address for the HTTP control server (e.g. :8080); disabled if empty")
+	replayDir := flag.String("replay", "", "Re-decide the auction results recorded in this directory under the current pricing and coalescing rules")
+	revealWindow := flag.Duration("reveal-window", 0, "Run commit-reveal sealed-bid auctions: bidders commit hashed bids until the timeout, then reveal them within this window (0 = off)")
+	resultName := flag.String("result-name", manager.DefaultResultNamePattern, "Filename template for auction results, e.g. auction_{id:04d}.json")
+	deadlineAware := flag.Bool("deadline-aware", false, "Generated bidders shorten their processing delay to bid before the auction deadline")
+	drainTimeout := flag.Duration("drain-timeout", 500*time.Millisecond, "How long each auction, once it stops taking bids, waits for in-flight bidder goroutines; bids they stamped in time are counted")
+	coalesceWindow := flag.Duration("coalesce-window", 0, "Merge bids from the same bidder arriving within this window before choosing the winner (0 = off)")
+	coalesceKeep := flag.String("coalesce-keep", string(models.CoalesceLatest), "Which bid of a merged burst survives: latest or highest")
+	rngName := flag.String("rng", string(models.GeneratorMathRand), "Random number generator behind every stream: math-rand or pcg")
+	attributeMode := flag.String("attribute-mode", string(models.AttributesRandom), "How auction attributes are generated: random, identical (one shared vector) or templates:N (N vectors reused round-robin)")
+	attributeRegression := flag.Bool("attribute-regression", false, "Regress winning prices on the 20 auction attributes in the summary")
+	fingerprint := flag.Bool("fingerprint", false, "Add a run fingerprint, a digest of every auction's outcome, to the summary and print it")
+	expectFingerprint := flag.String("expect-fingerprint", "", "Exit non-zero unless the run fingerprint matches this hex digest, or the fingerprint in this execution_summary.json")
+	bidCorrelation := flag.Bool("bid-correlation", false, "Analyze how consistently bidders bid high or low across auctions in the summary")
+	bidderStats := flag.Bool("bidder-stats", false, "Add each bidder's auctions, bids, wins and spending to the summary and write bidder_stats.json")
+	bidderTimeout := flag.Duration("bidder-timeout", 0, "Abandon a bid if the bidder takes longer than this to compute it (0 = no limit)")
+	biddersFile := flag.String("bidders-file", "", "JSON or CSV file defining the bidder population in place of -bidders generated bidders")
+	cents := flag.Bool("cents", false, "Represent bid amounts as integer cents for exact comparisons and sums")
+	retainFiles := flag.Int("retain-files", 0, "Keep at most this many result files in the output directory (0 = unlimited)")
+	retainAge := flag.Duration("retain-age", 0, "Delete result files older than this (0 = never)")
+	feedback := flag.Float64("feedback", 0, "Run auctions in sequence with competition feeding into later attributes at this strength (0 = off)")
+	maxDuration := flag.Duration("max-duration", 0, "Stop the run after this long, keeping the bids collected so far (0 = no limit)")
+	maxTotalBids := flag.Int("max-total-bids", 0, "Stop the run once auctions have admitted this many bids between them, keeping those collected (0 = no limit)")
+	minDuration := flag.Duration("min-duration", 0, "Keep each auction open, and its bidders bidding, at least this long even if it is cancelled sooner (0 = off)")
+	minBidAmount := flag.Float64("min-bid-amount", 0, "Raise calculated bids below this amount to it (0 = no floor)")
+	maxBidAmount := flag.Float64("max-bid-amount", 0, "Lower calculated bids above this amount to it (0 = no ceiling)")
+	maxBidGoroutines := flag.Int("max-bid-goroutines", 0, "Maximum concurrent bid goroutines across all auctions (0 = unlimited)")
+	maxConcurrent := flag.Int("max-concurrent-auctions", 0, "Run at most this many auctions at once, queuing the rest in ID order (0 = all at once)")
+	pricing := flag.String("pricing", string(models.PricingFirstPrice), "Pricing mode: first-price, second-price (Vickrey: the winner pays the runner-up's bid), all-pay (every bidder pays their highest bid) or uniform (multi-unit winners all pay the clearing price)")
+	metricsAddr := flag.String("metrics-addr", "", "Address for the HTTP server exposing Prometheus metrics at /metrics (e.g. :9100); disabled if empty")
+	grpcAddr := flag.String("grpc-addr", "", "Address for the gRPC server streaming auction results as they complete (e.g. :9090); disabled if empty")
+	format := flag.String("format", "json", "Output format: json, csv for auctions.csv and bids.csv in place of per-auction JSON files, both, or md to also write a Markdown report (report.md)")
+	resultBuffer := flag.Int("result-buffer", 0, "Capacity of the finished-auction queue (0 = one slot per auction)")
+	accept := flag.String("accept", string(models.AcceptAll), "Bid acceptance policy: accept-all, higher-than-current, first-per-bidder or best-per-bidder")
+	attributeImportance := flag.Bool("attribute-importance", false, "Give each auction random attribute importances that scale every bidder's weights")
+	backpressure := flag.String("backpressure", string(models.BackpressureBlock), "When the result queue is full: block, drop-oldest or error")
+	groups := flag.Int("groups", 0, "Number of affiliation groups whose members share valuation signals (0 = independent bidders)")
+	hammerGrace := flag.Duration("hammer-grace", 0, "A bid in this final window before the deadline extends the auction by the same amount (0 = off)")
+	hammerMax := flag.Int("hammer-max-extensions", simulator.DefaultHammerMaxExtensions, "Maximum grace extensions per auction")
+	kafkaBrokers := flag.String("kafka-brokers", "", "Comma-separated Kafka bootstrap brokers to publish each auction result to (e.g. localhost:9092); disabled if empty")
+	kafkaTopic := flag.String("kafka-topic", "auction-results", "Kafka topic for auction results")
+	injectFaults := flag.Float64("inject-faults", 0, "Fault injection rate for resilience testing (testing only)")
+	otlpEndpoint := flag.String("otlp-endpoint", "", "OTLP/HTTP collector URL to export auction spans and metrics to (e.g. http://localhost:4318); disabled if empty")
+	trace := flag.Bool("trace", false, "Write trace.json in Chrome Trace Event format")
+	seedSearch := flag.String("search-seed", "", "Find the seed optimizing a metric: metric:max|min[:count], e.g. total_revenue:max:50")
+	scaling := flag.Bool("scaling", false, "Run once per CPU count from 1 to -cpus and write scaling.csv with throughput per core count")
+	seedSweep := flag.String("seed-sweep", "", "Run once per seed in start:end:step and write sweep_results.csv")
+	consolidated := flag.Bool("consolidated", false, "Write every auction result to one all_auctions.json array instead of per-auction result files")
+	singleFile := flag.Bool("single-file", false, "Write the whole run to one simulation.json instead of per-auction result files and a summary")
+	writeBidders := flag.Bool("write-bidders", false, "Write bidders.json with each bidder's seed, participation rate, weights and budget")
+	writeSamples := flag.Bool("write-samples", false, "Write resource_samples.csv, memory and goroutine samples over time with the number of auctions closed by each")
+	durationUnit := flag.String("duration-unit", string(models.UnitMilliseconds), "Unit for durations in the printed summary and report: ns, us, ms or s")
+	recordSequence := flag.Bool("record-sequence", false, "Write bid_sequence.json with every bid in the order and at the time its auction received it")
+	replaySequence := flag.String("replay-sequence", "", "Feed auctions the bids recorded in this bid_sequence.json, in order and on time, instead of running bidders")
+	orderedResults := flag.Bool("ordered-results", false, "Deliver finished auctions to the output writers and hooks in ascending ID order, holding back any that finish early")
+	shuffleBidders := flag.Bool("shuffle-bidders", false, "Notify bidders in a seeded random order per auction instead of bidder ID order")
+	arrivals := flag.Bool("arrivals", false, "Bidders arrive at random times during each auction instead of all at the start")
+	departureRate := flag.Float64("departure-rate", 0, "With -arrivals, the probability an arrived bidder leaves before the auction closes")
+	burstSize := flag.Int("burst", 0, "Load testing: every bidder sends bursts of this many bids back to back instead of one bid (0 = off)")
+	burstCount := flag.Int("burst-count", 1, "With -burst, how many bursts each bidder sends per auction, each after its own processing delay")
+	aggressiveness := flag.String("aggressiveness", "", "Multiply every bid by a per-bidder aggressiveness drawn uniformly from min:max, or a single shared value (empty = 1)")
+	commonValue := flag.Float64("common-value", 0, "Run common-value auctions: each has a hidden true value and bidders bid from estimates off by up to this fraction either way (0 = private values)")
+	strategyMix := flag.String("strategy-mix", "", "Split generated bidders between strategies as strategy:share pairs, e.g. default:2,aggressive:1; strategies are default, aggressive, conservative and value-based (empty = all default)")
+	visibleAttributes := flag.String("visible-attributes", "", "Each bidder observes a random subset of this many attributes, a count drawn from min:max or a single count, guessing the rest (empty = all 20)")
+	maxStoredBids := flag.Int("max-stored-bids", 0, "Store only each auction's highest N bids, counting and summarizing the rest (0 stores every bid)")
+	bundles := flag.Int("bundles", 0, fmt.Sprintf("Split each auction's attributes into this many items (2-%d); bidders bid on bundles of items and the auction clears to the most valuable non-overlapping bundles (0 = off)", models.MaxBundleItems))
+	maxActive := flag.Int("max-active-auctions", 0, "Each bidder takes part in at most this many auctions at once, declining others while saturated (0 = unlimited)")
+	winCooldown := flag.Duration("win-cooldown", 0, "After a win, the bidder declines auctions opening within this long of that auction's close (0 = no cooldown)")
+	timeout := flag.String("timeout", manager.AuctionTimeout.String(), "How long each auction collects bids, or min:max to draw each auction's timeout uniformly from a range (e.g. 2s:8s)")
+	minIncrement := flag.Float64("min-increment", 0, "Reject bids that do not beat their auction's current highest bid by at least this much (0 = any bid)")
+	dutch := flag.String("dutch", "", "Run Dutch auctions whose price falls from start to floor over the timeout, as start:floor; the first bidder to accept wins at that price (empty = ascending auctions)")
+	dutchTicks := flag.Int("dutch-ticks", simulator.DefaultDutchTicks, "Number of prices a Dutch clock announces, evenly spaced over the timeout")
+	proxyRate := flag.Float64("proxy-rate", 0, "Chance each generated bid is a proxy bid: a maximum the auction bids up to in -min-increment steps, only as far as needed to lead (0 = none)")
+	reserve := flag.String("reserve", "", "Reserve price below which an auction does not sell, drawn per auction uniformly from min:max, or a single shared value (empty = none)")
+	units := flag.Int("units", 1, "Identical units sold per auction; above 1, bidders submit demand schedules and the market clears at the highest marginal bids")
+	watchdogMargin := flag.Duration("watchdog-margin", manager.DefaultWatchdogMargin, "Report auctions with no result this long after they should have closed")
+	watchdogCancel := flag.Bool("watchdog-cancel", false, "Also cancel auctions the watchdog reports as stuck")
+	webhookURL := flag.String("webhook-url", "", "POST each auction result to this URL as it completes; disabled if empty")
+	webhookConcurrency := flag.Int("webhook-concurrency", webhook.DefaultConcurrency, "Maximum concurrent webhook requests")
+	feePercent := flag.Float64("fee-percent", 0, "Auction house commission as a percentage of each payment")
+	feeFlat := flag.Float64("fee-flat", 0, "Flat auction house fee added to each payment's commission")
+	feeMin := flag.Float64("fee-min", 0, "Minimum auction house fee per payment")
+	force := flag.Bool("force", false, "Write to the output directory even if another run's lock file is present")
+	validateDir := flag.String("validate", "", "Check the results and summary in this directory for consistency, then exit non-zero if any problem is found")
+	showVersion := flag.Bool("version", false, "Print version and build information, then exit")
+	flag.Usage = usage
 	flag.Parse()
 
-	// Set random seed for reproducibility
-	rand.Seed(*seed)
+	if *showVersion {
+		printVersion()
+		return
+	}
+
+	// Validate the output naming pattern before doing any work
+	outputGen := manager.NewOutputGenerator(*outputDir)
+	if err := outputGen.SetResultNamePattern(*resultName); err != nil {
+		fatalf("Invalid -result-name: %v", err)
+	}
+	if err := outputGen.SetRetentionPolicy(manager.RetentionPolicy{
+		MaxFiles: *retainFiles,
+		MaxAge:   *retainAge,
+	}); err != nil {
+		fatalf("Invalid retention policy: %v", err)
+	}
 
-	// Configure resource constraints
-	runtime.GOMAXPROCS(*maxCPUs)
+	if *validateDir != "" {
+		runValidate(outputGen, *validateDir)
+		return
+	}
+
+	outputGen.SetBidCorrelation(*bidCorrelation)
+	outputGen.SetBidderStats(*bidderStats)
+	outputGen.SetAttributeRegression(*attributeRegression)
+	outputGen.SetFingerprint(*fingerprint || *expectFingerprint != "")
+
+	switch *format {
+	case "json", "csv", "both", "md":
+	default:
+		fatalf("Invalid -format %q: want json, csv, both or md", *format)
+	}
+	if *format == "csv" && *singleFile {
+		fatalf("Invalid -format csv: -single-file writes JSON only; use -format both")
+	}
+	if *consolidated && (*singleFile || *format == "csv") {
+		fatalf("Invalid -consolidated: it replaces per-auction JSON files, which -single-file and -format csv do not write")
+	}
+	writeJSON := *format != "csv"
+	writeCSV := *format == "csv" || *format == "both"
 
-	config := models.ResourceConfig{
+	// Claim the output directory so concurrent runs cannot interleave files
+	if err := outputGen.Lock(*force); err != nil {
+		fatalf("Cannot use output directory: %v", err)
+	}
+	atExit = append(atExit, outputGen.Unlock)
+	defer outputGen.Unlock()
+
+	config := simulator.DefaultConfig()
+	config.Seed = *seed
+	if *numAuctions <= 0 || *numBidders <= 0 {
+		fatalf("-auctions and -bidders must be positive, got %d and %d", *numAuctions, *numBidders)
+	}
+	config.NumAuctions = *numAuctions
+	config.NumBidders = *numBidders
+	if *biddersFile != "" {
+		profiles, err := bidder.LoadProfiles(*biddersFile)
+		if err != nil {
+			fatalf("Invalid -bidders-file: %v", err)
+		}
+		if flagSet("bidders") && *numBidders != len(profiles) {
+			fatalf("-bidders %d does not match the %d bidders in -bidders-file", *numBidders, len(profiles))
+		}
+		config.Bidders = profiles
+		config.NumBidders = len(profiles)
+	}
+	config.Resources = models.ResourceConfig{
 		MaxCPUs:     *maxCPUs,
-		MaxMemoryMB: 0, // No hard limit, just monitoring
+		MaxMemoryMB: *maxMemory,
+		CPUQuota:    *cpuQuota,
+	}
+	if *scaling && *cpuQuota > 0 {
+		fatalf("-scaling varies the CPU count, so it cannot be combined with -cpu-quota")
+	}
+	config.DrainTimeout = *drainTimeout
+	config.MaxBidGoroutines = *maxBidGoroutines
+	config.MaxConcurrentAuctions = *maxConcurrent
+	config.Serial = *serial
+	config.ExternalBids = *serveAddr != ""
+	config.SubmissionOrder = models.SubmissionOrder(*submissionOrder)
+	if path, ok := strings.CutPrefix(*submissionOrder, string(models.SubmitBySchedule)+":"); ok {
+		schedule, err := manager.ReadSubmissionSchedule(path)
+		if err != nil {
+			fatalf("Invalid -submission-order: %v", err)
+		}
+		config.SubmissionOrder = models.SubmitBySchedule
+		config.SubmissionSchedule = schedule
+	}
+	config.ResultBuffer = *resultBuffer
+	config.WatchdogMargin = *watchdogMargin
+	config.WatchdogCancel = *watchdogCancel
+	config.Backpressure = models.Backpressure(*backpressure)
+	config.MaxDuration = *maxDuration
+	config.MaxTotalBids = *maxTotalBids
+	config.MinDuration = *minDuration
+	config.HammerGrace = *hammerGrace
+	config.HammerMaxExtensions = *hammerMax
+	config.IntegerAmounts = *cents
+	config.DeadlineAware = *deadlineAware
+	config.Arrivals = *arrivals
+	config.ShuffleBidders = *shuffleBidders
+	config.OrderedResults = *orderedResults
+	config.RecordSequence = *recordSequence
+	if *replaySequence != "" {
+		seq, err := manager.ReadBidSequence(*replaySequence)
+		if err != nil {
+			fatalf("Invalid -replay-sequence: %v", err)
+		}
+		// Replay the recorded run's auctions under its seed
+		if flagSet("auctions") && *numAuctions != seq.NumAuctions {
+			fatalf("-auctions %d does not match the %d auctions in -replay-sequence", *numAuctions, seq.NumAuctions)
+		}
+		config.ReplaySequence = seq
+		config.Seed = seq.Seed
+		config.NumAuctions = seq.NumAuctions
+	}
+	config.DepartureRate = *departureRate
+	config.BidderTimeout = *bidderTimeout
+	config.RevealWindow = *revealWindow
+	config.MinBidAmount = *minBidAmount
+	config.MinIncrement = *minIncrement
+	config.ProxyRate = *proxyRate
+	config.CommonValueNoise = *commonValue
+	config.MaxBidAmount = *maxBidAmount
+	config.CoalesceWindow = *coalesceWindow
+	config.CoalesceKeep = models.CoalesceKeep(*coalesceKeep)
+	config.Pricing = models.PricingMode(*pricing)
+	config.Units = *units
+	config.BundleItems = *bundles
+	config.MaxActiveAuctions = *maxActive
+	config.WinCooldown = *winCooldown
+	config.MaxStoredBids = *maxStoredBids
+	config.BurstSize = *burstSize
+	if *burstSize > 0 {
+		config.BurstCount = *burstCount
+	}
+	if *aggressiveness != "" {
+		lo, hi, err := simulator.ParseAggressiveness(*aggressiveness)
+		if err != nil {
+			fatalf("Invalid -aggressiveness: %v", err)
+		}
+		config.AggressivenessMin, config.AggressivenessMax = lo, hi
+	}
+	if *strategyMix != "" {
+		mix, err := simulator.ParseStrategyMix(*strategyMix)
+		if err != nil {
+			fatalf("Invalid -strategy-mix: %v", err)
+		}
+		config.StrategyMix = mix
 	}
+	minTimeout, maxTimeout, err := simulator.ParseTimeout(*timeout)
+	if err != nil {
+		fatalf("Invalid -timeout: %v", err)
+	}
+	if minTimeout <= 0 {
+		fatalf("Invalid -timeout: must be positive, got %v", minTimeout)
+	}
+	config.AuctionTimeout, config.AuctionTimeoutMax = minTimeout, maxTimeout
+	if *reserve != "" {
+		lo, hi, err := simulator.ParseReserve(*reserve)
+		if err != nil {
+			fatalf("Invalid -reserve: %v", err)
+		}
+		config.ReserveMin, config.ReserveMax = lo, hi
+	}
+	if *dutch != "" {
+		start, floor, err := simulator.ParseDutch(*dutch)
+		if err != nil {
+			fatalf("Invalid -dutch: %v", err)
+		}
+		if start <= 0 {
+			fatalf("Invalid -dutch: start price must be positive, got %v", start)
+		}
+		config.DutchStart, config.DutchFloor, config.DutchTicks = start, floor, *dutchTicks
+	}
+	if *visibleAttributes != "" {
+		lo, hi, err := simulator.ParseVisibleAttributes(*visibleAttributes)
+		if err != nil {
+			fatalf("Invalid -visible-attributes: %v", err)
+		}
+		config.VisibleMin, config.VisibleMax = lo, hi
+	}
+	config.DurationUnit = models.DurationUnit(*durationUnit)
+	config.AttributeMode = models.AttributeMode(*attributeMode)
+	config.RNG = models.Generator(*rngName)
+	config.AttributeImportance = *attributeImportance
+	config.Acceptance = models.AcceptanceMode(*accept)
+	if *feePercent != 0 || *feeFlat != 0 || *feeMin != 0 {
+		config.Fee = &models.FeeSchedule{Percent: *feePercent, Flat: *feeFlat, Min: *feeMin}
+	}
+	config.NumGroups = *groups
+	config.FeedbackStrength = *feedback
+	config.FaultRate = *injectFaults
 
 	fmt.Println("===================================================")
 	fmt.Println("        AUCTION SIMULATOR - STARTING")
 	fmt.Println("===================================================")
 	fmt.Printf("Configuration:\n")
-	fmt.Printf("  Max CPUs:        %d\n", config.MaxCPUs)
+	fmt.Printf("  Max CPUs:        %d\n", config.Resources.MaxCPUs)
+	if *cpuQuota > 0 {
+		fmt.Printf("  CPU Quota:       %.2f cores\n", *cpuQuota)
+	}
 	fmt.Printf("  Output Dir:      %s\n", *outputDir)
-	fmt.Printf("  Random Seed:     %d\n", *seed)
-	fmt.Printf("  Auctions:        %d\n", manager.NumAuctions)
-	fmt.Printf("  Bidders:         %d\n", manager.NumBidders)
-	fmt.Println("===================================================\n")
+	switch {
+	case *replayDir != "":
+		fmt.Printf("  Replaying:       %s\n", *replayDir)
+	case *replaySequence != "":
+		fmt.Printf("  Bid Sequence:    %s (seed %d)\n", *replaySequence, config.Seed)
+	case *seedSweep != "":
+		fmt.Printf("  Seed Sweep:      %s\n", *seedSweep)
+	case *scaling:
+		fmt.Printf("  Scaling Sweep:   1-%d CPUs (seed %d)\n", scalingCPUs(config), config.Seed)
+	case *seedSearch != "":
+		fmt.Printf("  Seed Search:     %s\n", *seedSearch)
+	default:
+		fmt.Printf("  Random Seed:     %d\n", config.Seed)
+	}
+	fmt.Printf("  Auctions:        %d\n", config.NumAuctions)
+	fmt.Printf("  Bidders:         %d\n", config.NumBidders)
+	if config.MaxConcurrentAuctions > 0 {
+		fmt.Printf("  Concurrency:     at most %d auctions at once\n", config.MaxConcurrentAuctions)
+	}
+	if config.AuctionTimeoutMax > config.AuctionTimeout {
+		fmt.Printf("  Timeout:         %v-%v\n", config.AuctionTimeout, config.AuctionTimeoutMax)
+	} else {
+		fmt.Printf("  Timeout:         %v\n", config.AuctionTimeout)
+	}
+	if config.CommonValueNoise > 0 {
+		fmt.Printf("  Common Value:    estimates within ±%.0f%%\n", config.CommonValueNoise*100)
+	}
+	if len(config.StrategyMix) > 0 {
+		fmt.Printf("  Strategy Mix:    %s\n", *strategyMix)
+	}
+	if config.DutchStart > 0 {
+		fmt.Printf("  Dutch Clock:     %.2f down to %.2f in %d ticks\n", config.DutchStart, config.DutchFloor, config.DutchTicks)
+	}
+	fmt.Println("===================================================")
+	fmt.Println()
 
-	// Create resource monitor
-	monitor := resource.NewMonitor()
-	monitor.Start(100 * time.Millisecond) // Sample every 100ms
+	// Ctrl-C or SIGTERM stops the run: auctions close early, keeping the bids
+	// they collected, and the partial results are written with the run marked
+	// interrupted. A second signal exits at once.
+	ctx, interrupt := context.WithCancelCause(context.Background())
+	defer interrupt(nil)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		signal.Stop(signals) // A second signal gets the default handling
+		fmt.Println("\nInterrupted: writing partial results (interrupt again to quit now)")
+		interrupt(fmt.Errorf("received %v", sig))
+	}()
 
-	// Create auction manager
-	mgr := manager.NewManager(config)
+	if *seedSweep != "" {
+		runSeedSweep(ctx, config, *seedSweep, outputGen, *outputDir)
+		return
+	}
+	if *scaling {
+		runScalingSweep(ctx, config, outputGen, *outputDir)
+		return
+	}
+	if *seedSearch != "" {
+		runSeedSearch(ctx, config, *seedSearch, outputGen, *outputDir)
+		return
+	}
+	if *replayDir != "" {
+		runReplay(config, *replayDir, outputGen, *outputDir)
+		return
+	}
+
+	sim, err := simulator.New(config)
+	if err != nil {
+		fatalf("Invalid configuration: %v", err)
+	}
+
+	outputGen.SetFaultInjector(sim.Faults())
+
+	// Start the control server if requested
+	var srv *server.Server
+	if *serveAddr != "" {
+		srv = server.NewServer(*serveAddr, sim)
+		srv.Start()
+		fmt.Printf("Control server listening on %s\n", *serveAddr)
+	}
+
+	// Expose live metrics to Prometheus if requested
+	var metricsSrv *metrics.Server
+	if *metricsAddr != "" {
+		m := metrics.New()
+		sim.OnAuctionComplete(m.ObserveAuction)
+		sim.OnResourceSample(m.ObserveSample)
+		metricsSrv = metrics.NewServer(*metricsAddr, m)
+		metricsSrv.Start()
+		fmt.Printf("Metrics server listening on %s%s\n", *metricsAddr, metrics.MetricsPath)
+	}
+
+	// Stream results to gRPC consumers as auctions finish
+	var stream *grpcstream.Server
+	if *grpcAddr != "" {
+		stream = grpcstream.NewServer(*grpcAddr)
+		stream.Start()
+		sim.OnAuctionComplete(stream.Publish)
+		fmt.Printf("gRPC stream server listening on %s\n", *grpcAddr)
+	}
+
+	// Deliver results to the webhook as auctions finish
+	var sender *webhook.Sender
+	if *webhookURL != "" {
+		sender = webhook.NewSender(*webhookURL, *webhookConcurrency)
+		sim.AddSink(sender)
+	}
+
+	// Publish results to Kafka as auctions finish
+	var producer *kafka.Producer
+	if *kafkaBrokers != "" {
+		producer = kafka.NewProducer(*kafkaBrokers, *kafkaTopic)
+		sim.AddSink(producer)
+	}
 
 	// Run auctions
-	ctx := context.Background()
 	fmt.Println("Running auctions...")
 
-	auctions, firstStart, lastEnd, err := mgr.Run(ctx)
+	result, err := sim.Run(ctx)
 	if err != nil {
-		log.Fatalf("Error running auctions: %v", err)
+		fatalf("Error running auctions: %v", err)
 	}
 
-	// Stop monitoring
-	monitor.Stop()
-
-	// Get resource statistics
-	maxCPUsUsed := monitor.GetMaxCPUs()
-	peakMemoryMB := monitor.GetPeakMemoryMB()
-	avgGoroutines := monitor.GetAvgGoroutines()
+	if sender != nil {
+		report := sender.Close()
+		result.Webhook = &report
+	}
+	if producer != nil {
+		report := producer.Close()
+		result.Kafka = &report
+	}
+	if stream != nil {
+		stream.Finish()
+		stream.Stop()
+	}
 
 	fmt.Println("\nAll auctions completed!")
 	fmt.Println("Generating output files...")
 
 	// Generate output files
-	outputGen := manager.NewOutputGenerator(*outputDir)
+	if *singleFile {
+		if err := outputGen.WriteSingleFile(result); err != nil {
+			fatalf("Error writing simulation file: %v", err)
+		}
+	} else {
+		if *consolidated {
+			if err := outputGen.WriteConsolidatedResults(result.Auctions); err != nil {
+				fatalf("Error writing auction results: %v", err)
+			}
+		} else if writeJSON {
+			if err := outputGen.WriteAuctionResults(result.Auctions); err != nil {
+				fatalf("Error writing auction results: %v", err)
+			}
+		}
+
+		if err := outputGen.WriteSummary(result); err != nil {
+			fatalf("Error writing summary: %v", err)
+		}
+	}
+
+	if writeCSV {
+		if err := outputGen.WriteAuctionResultsCSV(result.Auctions); err != nil {
+			fatalf("Error writing auction results: %v", err)
+		}
+		if err := outputGen.WriteBidsCSV(result.Auctions); err != nil {
+			fatalf("Error writing bids: %v", err)
+		}
+	}
 
-	if err := outputGen.WriteAuctionResults(auctions); err != nil {
-		log.Fatalf("Error writing auction results: %v", err)
+	if *writeBidders {
+		if err := outputGen.WriteBidders(result); err != nil {
+			fatalf("Error writing bidders: %v", err)
+		}
 	}
 
-	if err := outputGen.WriteSummary(
-		auctions,
-		firstStart,
-		lastEnd,
-		maxCPUsUsed,
-		peakMemoryMB,
-		avgGoroutines,
-	); err != nil {
-		log.Fatalf("Error writing summary: %v", err)
+	if *bidderStats {
+		if err := outputGen.WriteBidderStats(result); err != nil {
+			fatalf("Error writing bidder stats: %v", err)
+		}
+	}
+
+	if *writeSamples {
+		if err := outputGen.WriteSamplesCSV(result); err != nil {
+			fatalf("Error writing resource samples: %v", err)
+		}
+	}
+
+	if *recordSequence {
+		if err := outputGen.WriteBidSequence(result); err != nil {
+			fatalf("Error writing bid sequence: %v", err)
+		}
+	}
+
+	if *trace {
+		if err := outputGen.WriteTrace(result); err != nil {
+			fatalf("Error writing trace: %v", err)
+		}
+	}
+
+	if *format == "md" {
+		if err := outputGen.WriteMarkdownReport(result); err != nil {
+			fatalf("Error writing report: %v", err)
+		}
+	}
+
+	// Export to the collector; an unreachable collector must not fail the run
+	if *otlpEndpoint != "" {
+		if err := otlp.NewExporter(*otlpEndpoint).Export(ctx, result); err != nil {
+			log.Printf("Warning: OTLP export to %s failed: %v", *otlpEndpoint, err)
+		} else {
+			fmt.Printf("Exported %d auctions to OTLP collector at %s\n", len(result.Auctions), *otlpEndpoint)
+		}
 	}
 
 	// Print summary to console
-	outputGen.PrintSummary(
-		auctions,
-		firstStart,
-		lastEnd,
-		maxCPUsUsed,
-		peakMemoryMB,
-		avgGoroutines,
-	)
+	outputGen.PrintSummary(result)
 
 	fmt.Printf("\nOutput files written to: %s\n", *outputDir)
-	fmt.Println("  - 40 individual auction result files (auction_N_result.json)")
-	fmt.Println("  - 1 execution summary file (execution_summary.json)")
+	if *singleFile {
+		fmt.Printf("  - 1 self-contained simulation file (%s)\n", manager.SingleFileName)
+	} else {
+		if *consolidated {
+			fmt.Printf("  - 1 consolidated results file with %d auctions (%s)\n", len(result.Auctions), manager.ConsolidatedFileName)
+		} else if writeJSON {
+			fmt.Printf("  - %d individual auction result files (%s)\n", len(result.Auctions), *resultName)
+		}
+		fmt.Println("  - 1 execution summary file (execution_summary.json)")
+	}
+	if writeCSV {
+		fmt.Printf("  - 1 auction results file (%s)\n", manager.AuctionsCSVFileName)
+		fmt.Printf("  - 1 bids file (%s)\n", manager.BidsCSVFileName)
+	}
+	if *writeBidders {
+		fmt.Println("  - 1 bidder population file (bidders.json)")
+	}
+	if *bidderStats {
+		fmt.Printf("  - 1 bidder statistics file (%s)\n", manager.BidderStatsFileName)
+	}
+	if *writeSamples {
+		fmt.Printf("  - 1 resource samples file (%s)\n", manager.SamplesCSVFileName)
+	}
+	if *recordSequence {
+		fmt.Printf("  - 1 bid sequence file (%s)\n", manager.SequenceFileName)
+	}
+	if *trace {
+		fmt.Println("  - 1 timeline trace file (trace.json)")
+	}
+	if *format == "md" {
+		fmt.Println("  - 1 Markdown report (report.md)")
+	}
+
+	if srv != nil {
+		srv.Stop()
+	}
+	if metricsSrv != nil {
+		metricsSrv.Stop()
+	}
+
+	if *expectFingerprint != "" {
+		checkFingerprint(*expectFingerprint, manager.Fingerprint(result.Auctions))
+	}
+
+	code := exitCode(result.Status.Outcome)
+	if code != 0 {
+		fmt.Printf("\nSimulation ended early: %s\n", result.Status.Outcome)
+		exit(code)
+	}
 	fmt.Println("\nSimulation completed successfully!")
 }
+
+// atExit holds cleanup that must run even when the process exits directly,
+// since os.Exit skips deferred calls
+var atExit []func()
+
+// exit runs the atExit cleanup and exits with code
+func exit(code int) {
+	for _, fn := range atExit {
+		fn()
+	}
+	os.Exit(code)
+}
+
+// fatalf logs like log.Fatalf, running the atExit cleanup first
+func fatalf(format string, args ...any) {
+	log.Printf(format, args...)
+	exit(1)
+}
+
+// flagSet reports whether the named flag was given on the command line
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		set = set || f.Name == name
+	})
+	return set
+}
+
+// checkFingerprint exits non-zero if got does not match expected, either a
+// hex root digest or the path of a summary holding a fingerprint. Only a
+// summary, with its per-auction hashes, can name the first differing auction.
+func checkFingerprint(expected string, got *models.RunFingerprint) {
+	if _, err := os.Stat(expected); err != nil {
+		if !strings.EqualFold(expected, got.Root) {
+			fatalf("Fingerprint mismatch: expected %s, got %s", expected, got.Root)
+		}
+		fmt.Println("\nFingerprint matches")
+		return
+	}
+
+	want, err := manager.ReadFingerprint(expected)
+	if err != nil {
+		fatalf("Invalid -expect-fingerprint: %v", err)
+	}
+	if id, differ := manager.FirstDifference(want, got); differ {
+		fatalf("Fingerprint mismatch: expected %s, got %s; first differing auction: %d", want.Root, got.Root, id)
+	}
+	fmt.Println("\nFingerprint matches")
+}
+
+// exitIfInterrupted exits with the interrupted status once a sweep stopped
+// early on a signal has written the rows it finished
+func exitIfInterrupted(ctx context.Context) {
+	if ctx.Err() != nil {
+		fmt.Println("\nSweep interrupted")
+		exit(exitCode(models.RunInterrupted))
+	}
+}
+
+// hiddenFlags are accepted but left out of -h, being for testing only
+var hiddenFlags = map[string]bool{"inject-faults": true}
+
+// usage prints the help for every flag except the hidden ones
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage of %s:\n", os.Args[0])
+
+	visible := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	visible.SetOutput(out)
+	flag.VisitAll(func(f *flag.Flag) {
+		if hiddenFlags[f.Name] {
+			return
+		}
+		visible.Var(f.Value, f.Name, f.Usage)
+		// Show the default, not a value already parsed before the error
+		visible.Lookup(f.Name).DefValue = f.DefValue
+	})
+	visible.PrintDefaults()
+}
+
+// exitCode maps how a run ended to the process exit status
+func exitCode(outcome models.RunOutcome) int {
+	switch outcome {
+	case models.RunCompleted:
+		return 0
+	case models.RunInterrupted:
+		return 130 // Conventional status for termination by SIGINT
+	case models.RunTimedOut:
+		return 124 // Matches timeout(1)
+	case models.RunBidLimit:
+		return 5
+	}
+	return 1
+}
+
+// runSeedSweep runs the configuration once per seed and writes the comparison CSV
+func runSeedSweep(ctx context.Context, config models.SimConfig, spec string, outputGen *manager.OutputGenerator, outputDir string) {
+	seeds, err := simulator.ParseSeedRange(spec)
+	if err != nil {
+		fatalf("Invalid -seed-sweep: %v", err)
+	}
+
+	fmt.Printf("Running seed sweep over %d seeds...\n", len(seeds))
+
+	rows, err := simulator.SweepSeeds(ctx, config, seeds)
+	if err != nil {
+		fatalf("Error running seed sweep: %v", err)
+	}
+
+	if err := outputGen.WriteSweepResults(rows); err != nil {
+		fatalf("Error writing sweep results: %v", err)
+	}
+
+	outputGen.PrintSweepSummary(rows)
+
+	fmt.Printf("\nSweep results written to: %s\n", outputDir)
+	fmt.Println("  - 1 sweep results file (sweep_results.csv)")
+	exitIfInterrupted(ctx)
+}
+
+// scalingCPUs is the highest CPU count a scaling sweep tries: the -cpus
+// limit, but no more than the machine has
+func scalingCPUs(config models.SimConfig) int {
+	return max(min(config.Resources.MaxCPUs, runtime.NumCPU()), 1)
+}
+
+// runScalingSweep runs the configuration at every CPU count up to -cpus and
+// reports where throughput stops improving
+func runScalingSweep(ctx context.Context, config models.SimConfig, outputGen *manager.OutputGenerator, outputDir string) {
+	maxCPUs := scalingCPUs(config)
+	fmt.Printf("Running scaling sweep over 1-%d CPUs...\n", maxCPUs)
+
+	rows, err := simulator.SweepCPUs(ctx, config, maxCPUs)
+	if err != nil {
+		fatalf("Error running scaling sweep: %v", err)
+	}
+
+	if err := outputGen.WriteScalingResults(rows); err != nil {
+		fatalf("Error writing scaling results: %v", err)
+	}
+
+	outputGen.PrintScalingSummary(rows, simulator.ScalingKnee(rows))
+
+	fmt.Printf("\nScaling results written to: %s\n", outputDir)
+	fmt.Printf("  - 1 scaling results file (%s)\n", manager.ScalingFileName)
+	exitIfInterrupted(ctx)
+}
+
+// runSeedSearch runs a fixed list of seeds and reports the one that best
+// achieves the requested metric target
+func runSeedSearch(ctx context.Context, config models.SimConfig, spec string, outputGen *manager.OutputGenerator, outputDir string) {
+	search, err := simulator.ParseSeedSearch(spec)
+	if err != nil {
+		fatalf("Invalid -search-seed: %v", err)
+	}
+
+	target := "lowest"
+	if search.Maximize {
+		target = "highest"
+	}
+	fmt.Printf("Searching seeds 1-%d for the %s %s...\n", search.Count, target, search.Metric)
+
+	rows, err := simulator.SearchSeeds(ctx, config, search)
+	if err != nil {
+		fatalf("Error running seed search: %v", err)
+	}
+
+	if err := outputGen.WriteSearchResults(rows); err != nil {
+		fatalf("Error writing search results: %v", err)
+	}
+
+	value := simulator.SearchMetrics[search.Metric]
+	fmt.Printf("\nBest seed: %d (%s = %.4f)\n", rows[0].Seed, search.Metric, value(rows[0]))
+	fmt.Printf("Worst seed: %d (%s = %.4f)\n", rows[len(rows)-1].Seed, search.Metric, value(rows[len(rows)-1]))
+
+	fmt.Printf("\nSearch results written to: %s\n", outputDir)
+	fmt.Println("  - 1 ranked search results file (search_results.csv)")
+	exitIfInterrupted(ctx)
+}
+
+// runValidate checks an output directory and exits non-zero listing every
+// problem found
+func runValidate(outputGen *manager.OutputGenerator, dir string) {
+	checked, problems := outputGen.Validate(dir)
+	if len(problems) > 0 {
+		fmt.Printf("FAIL: %s (%d results checked, %d problems)\n", dir, checked, len(problems))
+		for _, p := range problems {
+			fmt.Printf("  - %s\n", p)
+		}
+		exit(1)
+	}
+	fmt.Printf("PASS: %s (%d results checked)\n", dir, checked)
+}
+
+// runReplay re-decides recorded auctions under the configured rules and writes
+// the new results and summary
+func runReplay(config models.SimConfig, replayDir string, outputGen *manager.OutputGenerator, outputDir string) {
+	from, _ := filepath.Abs(replayDir)
+	to, _ := filepath.Abs(outputDir)
+	if from == to {
+		fatalf("-replay directory must differ from -output, or the recorded results would be overwritten")
+	}
+
+	recorded, err := outputGen.ReadAuctionResults(replayDir)
+	if err != nil {
+		fatalf("Error reading recorded results: %v", err)
+	}
+	fmt.Printf("Replaying %d recorded auctions with %s pricing...\n", len(recorded), config.Pricing)
+
+	result, err := simulator.Replay(recorded, config)
+	if err != nil {
+		fatalf("Invalid configuration: %v", err)
+	}
+
+	if err := outputGen.WriteAuctionResults(result.Auctions); err != nil {
+		fatalf("Error writing auction results: %v", err)
+	}
+	if err := outputGen.WriteSummary(result); err != nil {
+		fatalf("Error writing summary: %v", err)
+	}
+	outputGen.PrintSummary(result)
+
+	fmt.Printf("\nReplayed results written to: %s\n", outputDir)
+}
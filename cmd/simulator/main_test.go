@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+
+	"auction-simulator/pkg/models"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		outcome models.RunOutcome
+		want    int
+	}{
+		{models.RunCompleted, 0},
+		{models.RunInterrupted, 130},
+		{models.RunTimedOut, 124},
+		{models.RunBidLimit, 5},
+		{"unknown", 1},
+	}
+	for _, tt := range tests {
+		if got := exitCode(tt.outcome); got != tt.want {
+			t.Errorf("exitCode(%q) = %d, want %d", tt.outcome, got, tt.want)
+		}
+	}
+}
+
+// TestUsageHidesFlags checks that -h lists ordinary flags but not the hidden
+// testing-only ones
+func TestUsageHidesFlags(t *testing.T) {
+	flag.Float64("inject-faults", 0, "Fault injection rate for resilience testing (testing only)")
+	flag.Int("auctions", 40, "Number of auctions to run")
+	var out bytes.Buffer
+	flag.CommandLine.SetOutput(&out)
+	defer flag.CommandLine.SetOutput(nil)
+
+	usage()
+	if strings.Contains(out.String(), "inject-faults") {
+		t.Errorf("usage lists the hidden -inject-faults flag:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "-auctions int") || !strings.Contains(out.String(), "(default 40)") {
+		t.Errorf("usage lacks -auctions and its default:\n%s", out.String())
+	}
+}
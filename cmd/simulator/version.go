@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// version is the simulator release, overridable at build time with
+// -ldflags "-X main.version=..."
+var version = "dev"
+
+// features lists the optional capabilities compiled into this build
+var features = []string{
+	"control-server",
+	"seed-sweep",
+	"seed-search",
+	"feedback-mode",
+	"affiliation-groups",
+	"integer-amounts",
+	"result-retention",
+	"fault-injection",
+	"otlp-export",
+	"all-pay-pricing",
+	"webhook",
+	"bid-coalescing",
+	"bidders-file",
+	"grpc-stream",
+	"bid-replay",
+	"hammer-grace",
+	"bidder-seeds",
+	"result-backpressure",
+	"bid-correlation",
+	"auction-fees",
+	"watchdog",
+	"attribute-importance",
+	"serial-mode",
+	"bid-acceptance",
+	"kafka",
+	"multi-unit-auctions",
+	"bidder-timeout",
+	"commit-reveal",
+	"duration-units",
+	"bidder-arrivals",
+	"attribute-regression",
+	"max-stored-bids",
+	"shuffle-bidders",
+	"bid-sequence-replay",
+	"cpu-scaling-sweep",
+	"strategy-stats",
+	"bid-bursts",
+	"cpu-quota",
+	"run-fingerprint",
+	"attribute-mode",
+	"bidder-aggressiveness",
+	"bid-limit",
+	"pluggable-rng",
+	"welfare",
+	"ordered-results",
+	"visible-attributes",
+	"validate",
+	"submission-order",
+	"combinatorial-bundles",
+	"attention-limits",
+	"second-price",
+	"configurable-size",
+	"reserve-price",
+	"csv-results",
+	"min-increment",
+	"auction-timeouts",
+	"resource-samples",
+	"cpu-utilization",
+	"dutch-auction",
+	"proxy-bidding",
+	"bidder-stats",
+	"strategy-mix",
+	"consolidated-results",
+	"common-value",
+	"memory-limit",
+	"auction-pool",
+	"prometheus-metrics",
+}
+
+// outputFormats lists the output files this build can produce
+var outputFormats = []string{
+	"json (per-auction results, execution summary, bidders, single-file simulation, bid sequence)",
+	"csv (auction results, bids, resource samples, seed sweep, seed search, cpu scaling)",
+	"markdown (run report)",
+	"chrome-trace (timeline)",
+}
+
+// printVersion prints the simulator version and build information
+func printVersion() {
+	fmt.Printf("auction-simulator %s\n", version)
+	fmt.Printf("  Go version:  %s (%s/%s)\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+
+	commit, modified, buildTime := "unknown", false, ""
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				commit = setting.Value
+			case "vcs.modified":
+				modified = setting.Value == "true"
+			case "vcs.time":
+				buildTime = setting.Value
+			}
+		}
+	}
+	if modified {
+		commit += " (modified)"
+	}
+	fmt.Printf("  Commit:      %s\n", commit)
+	if buildTime != "" {
+		fmt.Printf("  Commit time: %s\n", buildTime)
+	}
+
+	fmt.Println("  Features:")
+	for _, f := range features {
+		fmt.Printf("    - %s\n", f)
+	}
+	fmt.Println("  Output formats:")
+	for _, f := range outputFormats {
+		fmt.Printf("    - %s\n", f)
+	}
+}
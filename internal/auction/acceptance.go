@@ -0,0 +1,56 @@
+package auction
+
+import (
+	"fmt"
+
+	"auction-simulator/pkg/models"
+)
+
+// BidAcceptancePolicy decides whether a received bid is added to an auction.
+// The collector calls Accept once per bid, from a single goroutine, before the
+// bid is added; rejected bids are counted on the auction and discarded.
+type BidAcceptancePolicy interface {
+	Accept(auction *models.Auction, bid models.Bid) bool
+}
+
+// AcceptanceFunc adapts a function to BidAcceptancePolicy
+type AcceptanceFunc func(auction *models.Auction, bid models.Bid) bool
+
+// Accept calls f
+func (f AcceptanceFunc) Accept(auction *models.Auction, bid models.Bid) bool {
+	return f(auction, bid)
+}
+
+// Built-in acceptance policies
+var (
+	acceptAll = AcceptanceFunc(func(*models.Auction, models.Bid) bool { return true })
+
+	acceptHigher = AcceptanceFunc(func(auction *models.Auction, bid models.Bid) bool {
+		return auction.Outbids(bid, auction.CurrentLeader())
+	})
+
+	acceptFirstPerBidder = AcceptanceFunc(func(auction *models.Auction, bid models.Bid) bool {
+		return auction.BidderBest(bid.BidderID) == nil
+	})
+
+	acceptBestPerBidder = AcceptanceFunc(func(auction *models.Auction, bid models.Bid) bool {
+		return auction.Outbids(bid, auction.BidderBest(bid.BidderID))
+	})
+)
+
+// AcceptancePolicy returns the built-in policy for mode; an empty mode accepts
+// every bid
+func AcceptancePolicy(mode models.AcceptanceMode) (BidAcceptancePolicy, error) {
+	switch mode {
+	case "", models.AcceptAll:
+		return acceptAll, nil
+	case models.AcceptHigher:
+		return acceptHigher, nil
+	case models.AcceptFirstPerBidder:
+		return acceptFirstPerBidder, nil
+	case models.AcceptBestPerBidder:
+		return acceptBestPerBidder, nil
+	}
+	return nil, fmt.Errorf("unknown acceptance policy %q (want %q, %q, %q or %q)", mode,
+		models.AcceptAll, models.AcceptHigher, models.AcceptFirstPerBidder, models.AcceptBestPerBidder)
+}
@@ -2,59 +2,357 @@ package auction
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"math/rand"
 	"time"
 
+	"auction-simulator/internal/faults"
+	"auction-simulator/internal/rng"
 	"auction-simulator/pkg/models"
 )
 
-// Run executes a single auction with the given timeout and bidder notifier
-func Run(ctx context.Context, auctionID int, timeout time.Duration, notifyBidders func(*models.Auction, chan<- models.Bid), results chan<- *models.Auction) {
-	auction := models.NewAuction(auctionID, timeout)
+// Options controls how a single auction is run
+type Options struct {
+	// Timeout is how long the auction collects bids
+	Timeout time.Duration
+	// IntegerAmounts makes the auction compare and sum bids in whole cents
+	IntegerAmounts bool
+	// Pricing is the payment rule used to compute the auction's revenue
+	Pricing models.PricingMode
+	// Units is how many identical units the auction sells; above one, the
+	// market clears across bidders' demand schedules
+	Units int
+	// BundleItems is how many items the auction splits its attributes into;
+	// above one, it clears bidders' bundle bids combinatorially
+	BundleItems int
+	// ReservePrice is the lowest bid, per unit, at which the auction sells;
+	// zero sets no reserve
+	ReservePrice float64
+	// MinIncrement is how much a bid must beat the current highest bid by;
+	// zero accepts any bid
+	MinIncrement float64
+	// Fee is the auction house's commission on each payment; nil charges none
+	Fee *models.FeeSchedule
+	// AttributeImportance draws a per-auction importance for each attribute
+	AttributeImportance bool
+	// Rand is the auction's own stream for its attributes and importances;
+	// nil draws them from a math/rand stream seeded by the clock
+	Rand *rand.Rand
+	// Acceptance decides which received bids are kept; nil keeps them all
+	Acceptance BidAcceptancePolicy
+	// Faults injects failures for resilience testing; nil disables injection
+	Faults *faults.Injector
+	// AttributeBias shifts every generated attribute, clamped to [0, 1]
+	AttributeBias float64
+	// AttributeTemplates are shared attribute vectors assigned round-robin by
+	// auction ID in place of generated attributes; nil generates them
+	AttributeTemplates [][20]float64
+	// HammerGrace extends the deadline by this much whenever a bid arrives in
+	// the final HammerGrace before it; zero disables the grace period
+	HammerGrace time.Duration
+	// HammerMaxExtensions caps how many times the grace period can extend
+	// a single auction
+	HammerMaxExtensions int
+	// MinDuration keeps the auction collecting bids, and its bidders bidding,
+	// at least this long after it opens, even if it is cancelled earlier; zero
+	// disables the floor
+	MinDuration time.Duration
+	// Drain, if set, is called once the auction stops taking new bids and
+	// blocks while its bidders finish bids already in flight; those stamped
+	// before the auction stopped are still collected
+	Drain func()
+	// CoalesceWindow merges bursts of bids from the same bidder before the
+	// winner is determined; zero disables coalescing
+	CoalesceWindow time.Duration
+	// CoalesceKeep selects which bid of a burst survives
+	CoalesceKeep models.CoalesceKeep
+	// MaxStoredBids keeps only the highest MaxStoredBids bids, summarizing
+	// the rest; zero stores every bid
+	MaxStoredBids int
+	// ExpectedBids pre-sizes the auction's bid slice, normally the number of
+	// bidders since each bids at most once
+	ExpectedBids int
+	// BidBuffer is the minimum capacity of the bid channel, normally the number
+	// of bidders; DefaultBidBuffer is used when it is smaller
+	BidBuffer int
+	// RateWindow measures the auction's peak bid arrival rate over sliding
+	// windows of this length; zero leaves it unmeasured
+	RateWindow time.Duration
+	// BidLimit caps the bids admitted across all auctions sharing it; nil
+	// leaves them uncapped
+	BidLimit *BidLimit
+	// Recorder logs every bid the collector receives; nil records nothing
+	Recorder *SequenceRecorder
+	// Sealed runs the auction as a two-phase commit-reveal auction instead of
+	// collecting open bids; nil runs a single phase
+	Sealed *Sealed
+	// Dutch runs the auction as a descending-price clock instead of collecting
+	// open bids; nil runs an ascending auction
+	Dutch *Dutch
+	// CommonValue gives the auction a true value derived from its attributes,
+	// which bidders estimate instead of valuing the item privately
+	CommonValue bool
+}
 
-	// Generate random attributes for this auction (values between 0 and 1)
-	for i := 0; i < 20; i++ {
-		auction.Attributes[i] = rand.Float64()
+// errDeadline is the cancellation cause when an auction reaches its deadline
+var errDeadline = errors.New("auction deadline reached")
+
+// DefaultBidBuffer is the minimum capacity of an auction's bid channel
+const DefaultBidBuffer = 200
+
+// Notifier tells bidders about an open auction and where to send their bids.
+// ctx ends when the auction stops collecting bids, which the MinDuration floor
+// can hold off after the run itself is cancelled.
+type Notifier func(ctx context.Context, auction *models.Auction, bidChan chan<- models.Bid)
+
+// Run executes a single auction with the given options and bidder notifier.
+// On success the finished auction is sent on results; on error nothing is sent.
+func Run(ctx context.Context, auctionID int, opts Options, notifyBidders Notifier, results chan<- *models.Auction) error {
+	if err := opts.Faults.Fail(faults.SiteAuctionRun, faults.Key(auctionID)); err != nil {
+		return fmt.Errorf("auction %d: %w", auctionID, err)
 	}
 
+	auction := newAuction(auctionID, opts)
 	auction.StartTime = time.Now()
+	if opts.Sealed != nil {
+		runSealed(ctx, auction, opts, results)
+		return nil
+	}
+	if opts.Dutch != nil {
+		runDutch(ctx, auction, opts, results)
+		return nil
+	}
 
-	// Create a channel to receive bids (buffered to handle concurrent submissions)
-	bidChan := make(chan models.Bid, 200)
+	// Create a channel to receive bids (buffered to handle concurrent submissions).
+	// Bidders never block on a full buffer, so it must hold at least one bid per
+	// bidder or bids are silently dropped when the collector is slow to run.
+	bidChan := make(chan models.Bid, max(opts.BidBuffer, DefaultBidBuffer))
 
-	// Create context with timeout
-	auctionCtx, cancel := context.WithTimeout(ctx, timeout)
-	defer cancel()
+	// The auction closes when its deadline timer fires. A timer rather than a
+	// context deadline lets a hammer-grace bid push the deadline back.
+	auctionCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	deadlineTimer := time.AfterFunc(opts.Timeout, func() { cancel(errDeadline) })
+	defer deadlineTimer.Stop()
+
+	// Bidders keep bidding until the auction may close: when its context ends,
+	// but no sooner than MinDuration after it opened
+	bidCtx, endBidding := context.WithCancel(context.WithoutCancel(auctionCtx))
+	defer endBidding()
+	floorApplied := false
+	context.AfterFunc(auctionCtx, func() {
+		if remaining := time.Until(auction.StartTime.Add(opts.MinDuration)); remaining > 0 {
+			floorApplied = true
+			time.Sleep(remaining)
+		}
+		endBidding()
+	})
 
 	// Notify all bidders about this auction
-	notifyBidders(auction, bidChan)
+	phaseStart := time.Now()
+	notifyBidders(bidCtx, auction, bidChan)
+	auction.Phases.BidderNotificationMs = elapsedMs(phaseStart)
 
 	// Collect bids until timeout
+	phaseStart = time.Now()
+
+	// stop fires once the bidders are done. From cutoff, the auction takes
+	// only bids stamped earlier, while Drain waits for those still in flight.
+	stop := make(chan struct{})
+	var cutoff time.Time
+	go func() {
+		defer close(stop)
+		<-bidCtx.Done()
+		if opts.Drain != nil {
+			cutoff = time.Now()
+			auction.StopAt(cutoff)
+			opts.Drain()
+		}
+	}()
+
 	done := make(chan struct{})
+	meter := newRateMeter(opts.RateWindow)
+	collect := func(bid models.Bid, open bool) {
+		meter.observe(time.Now())
+		opts.Recorder.record(auction, bid)
+		if opts.Faults.Fail(faults.SiteBidSubmit, faults.Key(auction.ID, bid.BidderID)) != nil {
+			return // Injected fault: the bid is lost in transit
+		}
+		if !admit(auction, bid, opts) || !opts.BidLimit.take() {
+			return
+		}
+		if !auction.AddBid(bid) {
+			return
+		}
+
+		if open && extends(auction, bid, opts) {
+			if deadlineTimer.Reset(time.Until(auction.Deadline().Add(opts.HammerGrace))) {
+				auction.Extend(opts.HammerGrace)
+			}
+		}
+	}
 	go func() {
+		defer close(done)
 		for {
 			select {
 			case bid := <-bidChan:
-				auction.AddBid(bid)
-			case <-auctionCtx.Done():
-				close(done)
-				return
+				collect(bid, true)
+			case <-stop:
+				// select picks randomly among ready cases, so when the collector
+				// is starved (e.g. a single CPU) bids submitted before the deadline
+				// may still be buffered. Closing the auction first stops further
+				// sends through Auction.Send, so draining afterwards takes every
+				// bid sent while it was open, all stamped before closedAt.
+				auction.Close()
+				closedAt := time.Now()
+				for {
+					select {
+					case bid := <-bidChan:
+						if !bid.Timestamp.After(closedAt) {
+							collect(bid, false)
+						}
+					default:
+						return
+					}
+				}
 			}
 		}
 	}()
 
-	// Wait for timeout
-	<-auctionCtx.Done()
+	// Wait for timeout. The bid channel is deliberately left open: late senders
+	// (bidders or external submitters) must never panic on a closed channel, and
+	// the closed flag tells them the auction no longer accepts bids.
+	<-stop
 	<-done
-	close(bidChan)
+	auction.MinDurationApplied = floorApplied
+	auction.PeakBidRate = meter.rate()
 
+	// A drained auction ended when it stopped taking new bids
 	auction.EndTime = time.Now()
+	if !cutoff.IsZero() {
+		auction.EndTime = cutoff
+	}
+	auction.Phases.BidCollectionMs = elapsedMs(phaseStart)
 
-	// Determine winner
-	auction.DetermineWinner()
+	// Reaching its own deadline means the auction ran its full course; anything
+	// else, including the run being stopped, is a cancellation
+	auction.Status = models.StatusCompleted
+	if context.Cause(auctionCtx) != errDeadline {
+		auction.Status = models.StatusCancelled
+	}
+
+	determineWinner(auction, opts)
 
 	// Send result
 	results <- auction
+	return nil
+}
+
+// newAuction creates an auction with freshly generated attributes
+func newAuction(auctionID int, opts Options) *models.Auction {
+	expected := opts.ExpectedBids
+	if opts.MaxStoredBids > 0 {
+		expected = min(expected, opts.MaxStoredBids)
+	}
+	auction := models.NewAuction(auctionID, opts.Timeout, expected)
+	auction.MaxStoredBids = opts.MaxStoredBids
+	auction.IntegerAmounts = opts.IntegerAmounts
+	auction.Pricing = opts.Pricing
+	auction.Units = opts.Units
+	auction.BundleItems = opts.BundleItems
+	if opts.ReservePrice > 0 {
+		auction.ReservePriceCents = models.ToCents(opts.ReservePrice)
+		auction.ReservePrice = opts.ReservePrice
+		if opts.IntegerAmounts {
+			auction.ReservePrice = models.FromCents(auction.ReservePriceCents)
+		}
+	}
+	if opts.MinIncrement > 0 {
+		auction.MinIncrementCents = models.ToCents(opts.MinIncrement)
+		auction.MinIncrement = opts.MinIncrement
+		if opts.IntegerAmounts {
+			auction.MinIncrement = models.FromCents(auction.MinIncrementCents)
+		}
+	}
+	auction.Fee = opts.Fee
+
+	// Generate random attributes for this auction (values between 0 and 1)
+	phaseStart := time.Now()
+	stream := opts.Rand
+	if stream == nil {
+		stream = rng.Factory(nil).New(phaseStart.UnixNano())
+	}
+	auction.AttributeBias = opts.AttributeBias
+	if n := len(opts.AttributeTemplates); n > 0 {
+		template := (auctionID - 1) % n
+		auction.AttributeTemplate = template + 1
+		auction.Attributes = opts.AttributeTemplates[template]
+	} else {
+		for i := 0; i < 20; i++ {
+			auction.Attributes[i] = min(max(stream.Float64()+opts.AttributeBias, 0), 1)
+		}
+	}
+	if opts.AttributeImportance {
+		auction.AttributeImportance = generateImportance(stream)
+	}
+	if opts.CommonValue {
+		auction.TrueValue = models.CommonValue(auction.Attributes, auction.AttributeImportance)
+	}
+	auction.Phases.AttributeGenerationMs = elapsedMs(phaseStart)
+	return auction
+}
+
+// extends reports whether bid lands in the final grace window of the auction
+// while it can still be extended. Going once, going twice: such a bid extends
+// the auction by the grace period, up to the announced cap.
+func extends(auction *models.Auction, bid models.Bid, opts Options) bool {
+	if opts.HammerGrace <= 0 || auction.GraceExtensions >= opts.HammerMaxExtensions {
+		return false
+	}
+	deadline := auction.Deadline()
+	return !bid.Timestamp.Before(deadline.Add(-opts.HammerGrace)) && !bid.Timestamp.After(deadline)
+}
+
+// admit applies the acceptance policy and the minimum increment to a received
+// bid, counting rejections. Checking the increment here, before the bid limit,
+// keeps rejected bids from using up the limit.
+func admit(auction *models.Auction, bid models.Bid, opts Options) bool {
+	if (opts.Acceptance == nil || opts.Acceptance.Accept(auction, bid)) && auction.MeetsIncrement(bid) {
+		return true
+	}
+	auction.Reject()
+	return false
+}
+
+// determineWinner decides a closed auction, first timing bids against the
+// first one and collapsing rapid revisions if configured
+func determineWinner(auction *models.Auction, opts Options) {
+	phaseStart := time.Now()
+	auction.MarkFirstBid()
+	auction.Coalesce(opts.CoalesceWindow, opts.CoalesceKeep)
+	auction.DetermineWinner()
+	auction.Phases.WinnerDeterminationMs = elapsedMs(phaseStart)
+}
+
+// generateImportance draws a random importance for each attribute from stream,
+// normalized to average 1 so valuations keep the same overall scale
+func generateImportance(stream *rand.Rand) *[20]float64 {
+	var importance [20]float64
+	total := 0.0
+	for i := range importance {
+		importance[i] = 1 - stream.Float64() // (0, 1], so no attribute is ignored entirely
+		total += importance[i]
+	}
+	for i := range importance {
+		importance[i] *= float64(len(importance)) / total
+	}
+	return &importance
+}
+
+// elapsedMs returns the time since start in fractional milliseconds
+func elapsedMs(start time.Time) float64 {
+	return float64(time.Since(start)) / float64(time.Millisecond)
 }
 
 // AuctionBroadcast contains auction information broadcasted to bidders
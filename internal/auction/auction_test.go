@@ -0,0 +1,342 @@
+package auction
+
+import (
+	"context"
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"auction-simulator/pkg/models"
+)
+
+// sendAt returns a notifier whose n bidders each send one bid once delay(i)
+// has passed since the auction opened, counting the bids the auction took
+func sendAt(n int, delay func(i int) time.Duration, sent *atomic.Int64) Notifier {
+	return func(ctx context.Context, auction *models.Auction, bidChan chan<- models.Bid) {
+		for i := range n {
+			go func() {
+				time.Sleep(time.Until(auction.StartTime.Add(delay(i))))
+				bid := models.Bid{BidderID: i + 1, Amount: float64(100 + i), Timestamp: time.Now()}
+				if _, ok := auction.Send(bidChan, bid); ok {
+					sent.Add(1)
+				}
+			}()
+		}
+	}
+}
+
+// run runs one auction and returns it, failing if it does not finish
+func run(t *testing.T, id int, opts Options, notify Notifier) *models.Auction {
+	t.Helper()
+	results := make(chan *models.Auction, 1)
+	if err := Run(context.Background(), id, opts, notify, results); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	return <-results
+}
+
+// TestNoBidsLostAtDeadline has hundreds of bidders send in the last few
+// milliseconds before the deadline; every bid the auction took before closing
+// must be counted
+func TestNoBidsLostAtDeadline(t *testing.T) {
+	const bidders = 500
+	timeout := 100 * time.Millisecond
+	for round := range 20 {
+		var sent atomic.Int64
+		jitter := rand.New(rand.NewSource(int64(round)))
+		delays := make([]time.Duration, bidders)
+		for i := range delays {
+			delays[i] = timeout - 3*time.Millisecond + time.Duration(jitter.Int63n(int64(6*time.Millisecond)))
+		}
+		opts := Options{
+			Timeout:      timeout,
+			Pricing:      models.PricingFirstPrice,
+			ExpectedBids: bidders,
+			BidBuffer:    bidders,
+		}
+		auction := run(t, round+1, opts, sendAt(bidders, func(i int) time.Duration { return delays[i] }, &sent))
+
+		if int64(auction.TotalBids) != sent.Load() {
+			t.Fatalf("round %d: TotalBids = %d, but %d bids were sent before the close", round, auction.TotalBids, sent.Load())
+		}
+	}
+}
+
+// TestSingleCPU runs many auctions at once on one CPU, where the collectors
+// compete with hundreds of bidder goroutines. Every auction must close about
+// on time, with every bid counted, and none may deadlock.
+func TestSingleCPU(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(1))
+
+	const auctions, bidders = 20, 200
+	timeout := 300 * time.Millisecond
+	opts := Options{
+		Timeout:      timeout,
+		Pricing:      models.PricingFirstPrice,
+		ExpectedBids: bidders,
+		BidBuffer:    bidders,
+	}
+
+	finished := make(chan *models.Auction, auctions)
+	var sent [auctions]atomic.Int64
+	var wg sync.WaitGroup
+	start := time.Now()
+	for id := 1; id <= auctions; id++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Bids arrive over the first 50ms, well before the deadline
+			delay := func(i int) time.Duration { return time.Duration(1+i%50) * time.Millisecond }
+			if err := Run(context.Background(), id, opts, sendAt(bidders, delay, &sent[id-1]), finished); err != nil {
+				t.Errorf("auction %d: %v", id, err)
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * timeout):
+		t.Fatal("auctions did not finish: deadlock on a single CPU")
+	}
+	close(finished)
+
+	if elapsed := time.Since(start); elapsed > 3*timeout {
+		t.Errorf("auctions took %v to finish, want about %v", elapsed, timeout)
+	}
+	for auction := range finished {
+		if auction.Status != models.StatusCompleted {
+			t.Errorf("auction %d: status %q, want %q", auction.ID, auction.Status, models.StatusCompleted)
+		}
+		if auction.TotalBids != bidders || sent[auction.ID-1].Load() != bidders {
+			t.Errorf("auction %d: %d bids counted of %d sent, want all %d", auction.ID, auction.TotalBids, sent[auction.ID-1].Load(), bidders)
+		}
+	}
+}
+
+// TestHammerGraceBoundary places bids at and just beyond the edges of the
+// final grace window. With a 1s timeout and 100ms grace, a bid counts from
+// exactly 900ms up to and including the deadline; each one pushes the
+// deadline back by the grace period, at most twice.
+func TestHammerGraceBoundary(t *testing.T) {
+	opts := Options{
+		Timeout:             time.Second,
+		Pricing:             models.PricingFirstPrice,
+		HammerGrace:         100 * time.Millisecond,
+		HammerMaxExtensions: 2,
+	}
+	tests := []struct {
+		name       string
+		at         []time.Duration
+		extensions int
+		late       int
+	}{
+		{"just before the window", []time.Duration{900*time.Millisecond - 1}, 0, 0},
+		{"window opens", []time.Duration{900 * time.Millisecond}, 1, 0},
+		{"at the deadline", []time.Duration{time.Second}, 1, 0},
+		{"just after the deadline", []time.Duration{time.Second + 1}, 0, 1},
+		{"at the extended deadline", []time.Duration{950 * time.Millisecond, 1100 * time.Millisecond}, 2, 0},
+		{"after the extended deadline", []time.Duration{950 * time.Millisecond, 1100*time.Millisecond + 1}, 1, 1},
+		{"cap reached", []time.Duration{900 * time.Millisecond, time.Second, 1100 * time.Millisecond}, 2, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auction, late, err := RunSerial(1, opts, func(auction *models.Auction) []models.Bid {
+				bids := make([]models.Bid, len(tt.at))
+				for i, at := range tt.at {
+					bids[i] = models.Bid{BidderID: i + 1, Amount: float64(100 + i), Timestamp: auction.StartTime.Add(at)}
+				}
+				return bids
+			})
+			if err != nil {
+				t.Fatalf("RunSerial: %v", err)
+			}
+			if auction.GraceExtensions != tt.extensions || late != tt.late {
+				t.Errorf("%d extensions and %d late bids, want %d and %d", auction.GraceExtensions, late, tt.extensions, tt.late)
+			}
+			want := opts.Timeout + time.Duration(tt.extensions)*opts.HammerGrace
+			if got := auction.EndTime.Sub(auction.StartTime); got != want {
+				t.Errorf("closed after %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// TestHammerGraceTimer checks that a grace bid resets the deadline timer of a
+// concurrent auction. Each bid is sent after the window opens but stamped at
+// or just before its opening, so only the timestamp decides.
+func TestHammerGraceTimer(t *testing.T) {
+	timeout, grace := 200*time.Millisecond, 100*time.Millisecond
+	opts := Options{
+		Timeout:             timeout,
+		Pricing:             models.PricingFirstPrice,
+		HammerGrace:         grace,
+		HammerMaxExtensions: 1,
+	}
+	tests := []struct {
+		name    string
+		stamp   time.Duration
+		extends bool
+	}{
+		{"window opens", timeout - grace, true},
+		{"just before the window", timeout - grace - 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notify := func(ctx context.Context, auction *models.Auction, bidChan chan<- models.Bid) {
+				go func() {
+					time.Sleep(time.Until(auction.StartTime.Add(timeout - grace/2)))
+					auction.Send(bidChan, models.Bid{BidderID: 1, Amount: 100, Timestamp: auction.StartTime.Add(tt.stamp)})
+				}()
+			}
+			auction := run(t, 1, opts, notify)
+
+			want, extensions := timeout, 0
+			if tt.extends {
+				want, extensions = timeout+grace, 1
+			}
+			if auction.GraceExtensions != extensions || auction.TotalBids != 1 {
+				t.Errorf("%d extensions with %d bids, want %d with 1", auction.GraceExtensions, auction.TotalBids, extensions)
+			}
+			if got := auction.EndTime.Sub(auction.StartTime); got < want || got > want+grace/2 {
+				t.Errorf("closed after %v, want about %v", got, want)
+			}
+		})
+	}
+}
+
+// TestLateFlurry runs two auctions side by side. A flurry of bids, each in
+// the final grace window of the deadline the one before it set, must extend
+// the first auction up to the cap; the second, whose bids all come early,
+// must close on time.
+func TestLateFlurry(t *testing.T) {
+	timeout, grace := 200*time.Millisecond, 50*time.Millisecond
+	opts := Options{
+		Timeout:             timeout,
+		Pricing:             models.PricingFirstPrice,
+		HammerGrace:         grace,
+		HammerMaxExtensions: 3,
+	}
+	// Deadlines go 200ms, 250ms, 300ms, then 350ms at the cap, so the last
+	// bid lands in the window but extends nothing
+	flurry := []time.Duration{170, 220, 270, 320}
+	early := []time.Duration{10, 20, 30, 40}
+
+	results := make(chan *models.Auction, 2)
+	var wg sync.WaitGroup
+	for id, at := range [][]time.Duration{flurry, early} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var sent atomic.Int64
+			delay := func(i int) time.Duration { return at[i] * time.Millisecond }
+			if err := Run(context.Background(), id+1, opts, sendAt(len(at), delay, &sent), results); err != nil {
+				t.Errorf("auction %d: %v", id+1, err)
+			}
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	want := map[int]struct {
+		extensions int
+		closes     time.Duration
+	}{
+		1: {3, timeout + 3*grace},
+		2: {0, timeout},
+	}
+	for auction := range results {
+		w := want[auction.ID]
+		if auction.GraceExtensions != w.extensions || auction.TotalBids != 4 {
+			t.Errorf("auction %d: %d extensions with %d bids, want %d with 4", auction.ID, auction.GraceExtensions, auction.TotalBids, w.extensions)
+		}
+		if got := auction.EndTime.Sub(auction.StartTime); got < w.closes || got > w.closes+grace/2 {
+			t.Errorf("auction %d: closed after %v, want about %v", auction.ID, got, w.closes)
+		}
+	}
+}
+
+// TestDrainInFlight has two bidders finish computing their bids only after
+// the deadline: one stamped its bid before the deadline, the other after. With
+// a drain, the auction must wait for both and count only the first; without
+// one, both are lost.
+func TestDrainInFlight(t *testing.T) {
+	timeout := 100 * time.Millisecond
+	for _, drain := range []bool{true, false} {
+		var inFlight sync.WaitGroup
+		notify := func(ctx context.Context, auction *models.Auction, bidChan chan<- models.Bid) {
+			for i, stamp := range []time.Duration{timeout - time.Millisecond, timeout + 10*time.Millisecond} {
+				inFlight.Add(1)
+				go func() {
+					defer inFlight.Done()
+					time.Sleep(time.Until(auction.StartTime.Add(timeout + 30*time.Millisecond)))
+					auction.Send(bidChan, models.Bid{BidderID: i + 1, Amount: 100, Timestamp: auction.StartTime.Add(stamp)})
+				}()
+			}
+		}
+		opts := Options{Timeout: timeout, Pricing: models.PricingFirstPrice}
+		if drain {
+			opts.Drain = inFlight.Wait
+		}
+		auction := run(t, 1, opts, notify)
+		inFlight.Wait()
+
+		want := 0
+		if drain {
+			want = 1
+		}
+		if auction.TotalBids != want {
+			t.Errorf("drain %v: %d bids counted, want %d", drain, auction.TotalBids, want)
+		}
+		if drain && auction.Winner.BidderID != 1 {
+			t.Errorf("drain %v: bidder %d won, want the bidder that bid in time", drain, auction.Winner.BidderID)
+		}
+		if got := auction.EndTime.Sub(auction.StartTime); got < timeout || got > timeout+20*time.Millisecond {
+			t.Errorf("drain %v: ended after %v, want about %v", drain, got, timeout)
+		}
+	}
+}
+
+// TestMinDurationBidders cancels an auction as it opens while a bidder is
+// still waiting to arrive. The floor must keep the bidder's context alive so
+// its bid, placed during the floor, is counted.
+func TestMinDurationBidders(t *testing.T) {
+	floor := 80 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ended := make(chan time.Duration, 1)
+	notify := func(ctx context.Context, auction *models.Auction, bidChan chan<- models.Bid) {
+		go func() {
+			select {
+			case <-time.After(time.Until(auction.StartTime.Add(floor / 2))):
+				auction.Send(bidChan, models.Bid{BidderID: 1, Amount: 100, Timestamp: time.Now()})
+			case <-ctx.Done():
+			}
+		}()
+		context.AfterFunc(ctx, func() { ended <- time.Since(auction.StartTime) })
+	}
+	opts := Options{Timeout: time.Second, MinDuration: floor, Pricing: models.PricingFirstPrice}
+	results := make(chan *models.Auction, 1)
+	if err := Run(ctx, 1, opts, notify, results); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	auction := <-results
+
+	if !auction.MinDurationApplied {
+		t.Errorf("floor not applied to an auction cancelled as it opened")
+	}
+	if auction.TotalBids != 1 {
+		t.Errorf("%d bids counted, want the one placed during the floor", auction.TotalBids)
+	}
+	if got := <-ended; got < floor {
+		t.Errorf("bidder context ended after %v, want no sooner than the %v floor", got, floor)
+	}
+}
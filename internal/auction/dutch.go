@@ -0,0 +1,125 @@
+package auction
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"auction-simulator/internal/faults"
+	"auction-simulator/pkg/models"
+)
+
+// PriceNotifier announces the current price of a Dutch auction's clock and
+// where to send acceptances. It is called once per tick, from the auction's
+// own goroutine, with tick counting from 0. ctx ends when the auction closes.
+type PriceNotifier func(ctx context.Context, auction *models.Auction, tick int, price float64, accepts chan<- models.Bid)
+
+// Dutch configures a descending-price auction. The clock announces Ticks
+// prices, falling in even steps from Start to Floor, one every Timeout/Ticks.
+// The first bidder to accept an announced price wins at that price; if none
+// accepts before the clock runs out, the auction goes unsold.
+type Dutch struct {
+	Start    float64
+	Floor    float64
+	Ticks    int
+	Announce PriceNotifier
+}
+
+// price returns the clock's price at tick, rounded to whole cents when the
+// auction uses integer amounts
+func (d *Dutch) price(tick int, integerAmounts bool) float64 {
+	price := d.Start - (d.Start-d.Floor)*float64(tick)/float64(d.Ticks-1)
+	if integerAmounts {
+		return models.FromCents(models.ToCents(price))
+	}
+	return price
+}
+
+// runDutch runs auction's price clock and sends it on results. An acceptance
+// counts only at a price the clock has announced, and it wins at that price,
+// even if the clock ticked on while it was in flight. Hammer grace and
+// minimum durations do not apply to the clock.
+func runDutch(ctx context.Context, auction *models.Auction, opts Options, results chan<- *models.Auction) {
+	dutch := opts.Dutch
+	auction.Dutch = &models.DutchClock{StartPrice: dutch.Start, FloorPrice: dutch.Floor, Ticks: dutch.Ticks}
+
+	clockCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ticker := time.NewTicker(opts.Timeout / time.Duration(dutch.Ticks))
+	defer ticker.Stop()
+	accepts := make(chan models.Bid, max(opts.BidBuffer, DefaultBidBuffer))
+
+	announced := make([]float64, 0, dutch.Ticks)
+	announce := func() {
+		price := dutch.price(len(announced), opts.IntegerAmounts)
+		announced = append(announced, price)
+		dutch.Announce(clockCtx, auction, len(announced)-1, price, accepts)
+	}
+
+	// sell takes an acceptance if it is for an announced price and passes
+	// the same checks as an open bid, recording where the clock stopped
+	sell := func(bid models.Bid) bool {
+		tick := slices.Index(announced, bid.Amount)
+		if tick < 0 {
+			auction.Reject()
+			return false
+		}
+		if opts.Faults.Fail(faults.SiteBidSubmit, faults.Key(auction.ID, bid.BidderID)) != nil {
+			return false // Injected fault: the acceptance is lost in transit
+		}
+		if !admit(auction, bid, opts) || !opts.BidLimit.take() || !auction.AddBid(bid) {
+			return false
+		}
+		auction.Dutch.Sold, auction.Dutch.SoldAtTick, auction.Dutch.ClearingPrice = true, tick, bid.Amount
+		return true
+	}
+
+	phaseStart := time.Now()
+	announce()
+	auction.Phases.BidderNotificationMs = elapsedMs(phaseStart)
+
+	phaseStart = time.Now()
+	sold, cancelled := false, false
+clock:
+	for !sold {
+		select {
+		case bid := <-accepts:
+			sold = sell(bid)
+		case <-ticker.C:
+			if len(announced) == dutch.Ticks {
+				break clock // The floor went unaccepted for a full tick
+			}
+			announce()
+		case <-ctx.Done():
+			cancelled = true
+			break clock
+		}
+	}
+
+	// As with open bids, acceptances sent before the close may still be
+	// buffered if the clock was starved; the first valid one still sells
+	auction.Close()
+	closedAt := time.Now()
+drain:
+	for !sold {
+		select {
+		case bid := <-accepts:
+			sold = !bid.Timestamp.After(closedAt) && sell(bid)
+		default:
+			break drain
+		}
+	}
+	cancel()
+
+	auction.EndTime = time.Now()
+	auction.Phases.BidCollectionMs = elapsedMs(phaseStart)
+
+	// A sale completes the auction early; only the run being stopped cancels it
+	auction.Status = models.StatusCompleted
+	if cancelled && !sold {
+		auction.Status = models.StatusCancelled
+	}
+
+	determineWinner(auction, opts)
+	results <- auction
+}
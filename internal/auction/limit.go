@@ -0,0 +1,61 @@
+package auction
+
+import "sync"
+
+// BidLimit caps the bids admitted across every auction of a run. The bid that
+// reaches the cap calls the stop function registered with OnReached; later
+// bids are refused.
+type BidLimit struct {
+	mu      sync.Mutex
+	max     int
+	bids    int
+	refused int
+	stop    func()
+}
+
+// NewBidLimit creates a limit admitting at most max bids
+func NewBidLimit(max int) *BidLimit {
+	return &BidLimit{max: max}
+}
+
+// OnReached registers fn to be called once the limit is reached; it must be
+// called before any auction runs
+func (l *BidLimit) OnReached(fn func()) {
+	l.stop = fn
+}
+
+// take admits one bid against the limit, reporting false once the limit has
+// been reached. A nil limit admits every bid.
+func (l *BidLimit) take() bool {
+	if l == nil {
+		return true
+	}
+	l.mu.Lock()
+	if l.bids >= l.max {
+		l.refused++
+		l.mu.Unlock()
+		return false
+	}
+	l.bids++
+	reached := l.bids == l.max
+	l.mu.Unlock()
+
+	if reached && l.stop != nil {
+		l.stop()
+	}
+	return true
+}
+
+// Counts returns the bids admitted and refused so far
+func (l *BidLimit) Counts() (bids, refused int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.bids, l.refused
+}
+
+// Reached reports whether the limit has been reached
+func (l *BidLimit) Reached() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.bids >= l.max
+}
@@ -0,0 +1,41 @@
+package auction
+
+import "time"
+
+// rateMeter tracks the most bids an auction's collector received within any
+// window, keeping only the arrival times still inside the current one
+type rateMeter struct {
+	window time.Duration
+	times  []time.Time
+	peak   int
+}
+
+// newRateMeter returns a meter over window, or nil if window is zero
+func newRateMeter(window time.Duration) *rateMeter {
+	if window <= 0 {
+		return nil
+	}
+	return &rateMeter{window: window}
+}
+
+// observe records a bid received at at; a nil meter records nothing
+func (m *rateMeter) observe(at time.Time) {
+	if m == nil {
+		return
+	}
+	m.times = append(m.times, at)
+	i := 0
+	for at.Sub(m.times[i]) > m.window {
+		i++
+	}
+	m.times = m.times[i:]
+	m.peak = max(m.peak, len(m.times))
+}
+
+// rate returns the peak as bids per second
+func (m *rateMeter) rate() float64 {
+	if m == nil {
+		return 0
+	}
+	return float64(m.peak) / m.window.Seconds()
+}
@@ -0,0 +1,104 @@
+package auction
+
+import (
+	"context"
+	"time"
+
+	"auction-simulator/internal/faults"
+	"auction-simulator/pkg/models"
+)
+
+// CommitNotifier tells bidders about an open commit-reveal auction and where
+// to send their commitments. ctx ends when the commit phase closes.
+type CommitNotifier func(ctx context.Context, auction *models.Auction, commits chan<- models.Commitment)
+
+// RevealNotifier asks bidders holding commitments to reveal them. ctx ends
+// when the reveal phase closes.
+type RevealNotifier func(ctx context.Context, auction *models.Auction, reveals chan<- models.Reveal)
+
+// Sealed configures a two-phase sealed-bid auction. Bidders commit hashed
+// bids until the auction's timeout, then have RevealWindow to reveal them.
+// Only revealed bids that match their commitment take part in winner
+// determination; the rest are voided and counted.
+type Sealed struct {
+	RevealWindow time.Duration
+	Commit       CommitNotifier
+	Reveal       RevealNotifier
+}
+
+// runSealed runs the commit and reveal phases of auction and sends it on
+// results. Hammer grace and minimum durations do not apply to sealed bids.
+func runSealed(ctx context.Context, auction *models.Auction, opts Options, results chan<- *models.Auction) {
+	buffer := max(opts.BidBuffer, DefaultBidBuffer)
+
+	// Commit phase: bidders learn of the auction and send commitments
+	commitCtx, cancelCommit := context.WithTimeoutCause(ctx, opts.Timeout, errDeadline)
+	defer cancelCommit()
+
+	phaseStart := time.Now()
+	commits := make(chan models.Commitment, buffer)
+	opts.Sealed.Commit(commitCtx, auction, commits)
+	auction.Phases.BidderNotificationMs = elapsedMs(phaseStart)
+
+	phaseStart = time.Now()
+	collectPhase(commitCtx, commits, func(c models.Commitment) time.Time { return c.CommittedAt }, func(c models.Commitment) {
+		if opts.Faults.Fail(faults.SiteBidSubmit, faults.Key(auction.ID, c.BidderID)) != nil {
+			return // Injected fault: the commitment is lost in transit
+		}
+		auction.Commit(c)
+	})
+	auction.Phases.BidCollectionMs = elapsedMs(phaseStart)
+	completed := context.Cause(commitCtx) == errDeadline
+
+	// Reveal phase, skipped if the auction was cancelled while taking commitments
+	if completed {
+		phaseStart = time.Now()
+		revealCtx, cancelReveal := context.WithTimeout(ctx, opts.Sealed.RevealWindow)
+		reveals := make(chan models.Reveal, buffer)
+		opts.Sealed.Reveal(revealCtx, auction, reveals)
+		collectPhase(revealCtx, reveals, func(r models.Reveal) time.Time { return r.Timestamp }, func(r models.Reveal) {
+			if bid, ok := auction.Open(r); ok && admit(auction, bid, opts) {
+				auction.AddBid(bid)
+			}
+		})
+		cancelReveal()
+		completed = ctx.Err() == nil
+		auction.Phases.RevealMs = elapsedMs(phaseStart)
+	}
+
+	auction.VoidUnrevealed()
+	auction.Close()
+	auction.EndTime = time.Now()
+
+	auction.Status = models.StatusCompleted
+	if !completed {
+		auction.Status = models.StatusCancelled
+	}
+
+	determineWinner(auction, opts)
+	results <- auction
+}
+
+// collectPhase hands every value received on ch to handle until ctx ends.
+// Values still buffered when it ends are handled too if stamped no later than
+// the close, since a starved collector may not have reached them in time.
+func collectPhase[T any](ctx context.Context, ch <-chan T, stamp func(T) time.Time, handle func(T)) {
+	for {
+		select {
+		case v := <-ch:
+			handle(v)
+		case <-ctx.Done():
+			closedAt := time.Now()
+			for {
+				select {
+				case v := <-ch:
+					if !stamp(v).After(closedAt) {
+						handle(v)
+					}
+				default:
+					return
+				}
+			}
+		}
+	}
+}
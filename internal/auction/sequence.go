@@ -0,0 +1,99 @@
+package auction
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"auction-simulator/pkg/models"
+)
+
+// SequenceRecorder logs bids in the order collectors receive them. One
+// recorder is shared by every auction of a run, so the sequence captures the
+// interleaving across auctions as well as within them.
+type SequenceRecorder struct {
+	mu   sync.Mutex
+	bids []models.SequencedBid
+}
+
+// NewSequenceRecorder creates an empty recorder
+func NewSequenceRecorder() *SequenceRecorder {
+	return &SequenceRecorder{}
+}
+
+// record logs bid as received by auction now. A nil recorder does nothing.
+func (r *SequenceRecorder) record(auction *models.Auction, bid models.Bid) {
+	if r == nil {
+		return
+	}
+	received := time.Since(auction.StartTime)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bids = append(r.bids, models.SequencedBid{
+		Seq:         len(r.bids) + 1,
+		AuctionID:   auction.ID,
+		OffsetNs:    received.Nanoseconds(),
+		BidOffsetNs: bid.Timestamp.Sub(auction.StartTime).Nanoseconds(),
+		Bid:         bid,
+	})
+}
+
+// Sequence returns a copy of the bids recorded so far
+func (r *SequenceRecorder) Sequence(seed int64, numAuctions int) *models.BidSequence {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &models.BidSequence{
+		Seed:        seed,
+		NumAuctions: numAuctions,
+		Bids:        append([]models.SequencedBid(nil), r.bids...),
+	}
+}
+
+// ReplayNotifier returns a Notifier that feeds each auction the bids recorded
+// for it in seq instead of notifying bidders. Bids are sent one at a time in
+// recorded order, each at its recorded receive offset and restamped at its
+// recorded placement offset, so the collector sees the same sequence. Once
+// the auction stops collecting, the remaining bids are sent at once for the
+// collector to drain.
+func ReplayNotifier(seq *models.BidSequence) Notifier {
+	byAuction := make(map[int][]models.SequencedBid)
+	for _, sb := range seq.Bids {
+		byAuction[sb.AuctionID] = append(byAuction[sb.AuctionID], sb)
+	}
+	for _, bids := range byAuction {
+		sort.Slice(bids, func(i, j int) bool { return bids[i].Seq < bids[j].Seq })
+	}
+
+	return func(ctx context.Context, auction *models.Auction, bidChan chan<- models.Bid) {
+		bids := byAuction[auction.ID]
+		if len(bids) == 0 {
+			return
+		}
+		go func() {
+			for _, sb := range bids {
+				waitUntil(ctx, auction.StartTime.Add(time.Duration(sb.OffsetNs)))
+				bid := sb.Bid
+				bid.Timestamp = auction.StartTime.Add(time.Duration(sb.BidOffsetNs))
+				if open, _ := auction.Send(bidChan, bid); !open {
+					return
+				}
+			}
+		}()
+	}
+}
+
+// waitUntil sleeps until t or until ctx ends, whichever comes first
+func waitUntil(ctx context.Context, t time.Time) {
+	wait := time.Until(t)
+	if wait <= 0 {
+		return
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
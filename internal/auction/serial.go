@@ -0,0 +1,68 @@
+package auction
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"auction-simulator/internal/faults"
+	"auction-simulator/pkg/models"
+)
+
+// BidSource returns every bid the bidders place on an open auction, each
+// timestamped at the simulated moment it would arrive. It runs on the
+// caller's goroutine.
+type BidSource func(auction *models.Auction) []models.Bid
+
+// RunSerial executes a single auction entirely on the calling goroutine, with
+// no channels or timers. Bids are taken from placeBids and processed in
+// timestamp order (ties in the order returned) against a simulated clock, so
+// the result depends only on the random sources, not on scheduling. The
+// auction ends at its deadline, extended by hammer grace like Run; bids
+// timestamped after it are dropped and counted in the returned late count.
+func RunSerial(auctionID int, opts Options, placeBids BidSource) (*models.Auction, int, error) {
+	if err := opts.Faults.Fail(faults.SiteAuctionRun, faults.Key(auctionID)); err != nil {
+		return nil, 0, fmt.Errorf("auction %d: %w", auctionID, err)
+	}
+
+	auction := newAuction(auctionID, opts)
+	auction.StartTime = time.Now()
+
+	phaseStart := time.Now()
+	bids := placeBids(auction)
+	auction.Phases.BidderNotificationMs = elapsedMs(phaseStart)
+
+	phaseStart = time.Now()
+	sort.SliceStable(bids, func(i, j int) bool { return bids[i].Timestamp.Before(bids[j].Timestamp) })
+	late := 0
+	for _, bid := range bids {
+		if bid.Timestamp.After(auction.Deadline()) {
+			late++
+			continue
+		}
+		if opts.Faults.Fail(faults.SiteBidSubmit, faults.Key(auction.ID, bid.BidderID)) != nil {
+			continue // Injected fault: the bid is lost in transit
+		}
+		if !admit(auction, bid, opts) {
+			continue
+		}
+		if !opts.BidLimit.take() {
+			// The run's bid limit is spent: the auction closes here
+			auction.Status = models.StatusCancelled
+			break
+		}
+		if auction.AddBid(bid) && extends(auction, bid, opts) {
+			auction.Extend(opts.HammerGrace)
+		}
+	}
+	auction.Close()
+
+	auction.EndTime = auction.Deadline()
+	if auction.Status != models.StatusCancelled {
+		auction.Status = models.StatusCompleted
+	}
+	auction.Phases.BidCollectionMs = elapsedMs(phaseStart)
+
+	determineWinner(auction, opts)
+	return auction, late, nil
+}
@@ -0,0 +1,88 @@
+package bidder
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"auction-simulator/pkg/models"
+)
+
+// Attention models a bidder's limited attention: it takes part in at most
+// MaxActive auctions at once, and after a win it sits out auctions opening
+// within Cooldown of that auction's close. A zero field leaves that limit off.
+// The population shares one Attention so declines are counted in one place;
+// each bidder keeps its own state.
+type Attention struct {
+	MaxActive int
+	Cooldown  time.Duration
+
+	saturated atomic.Int64
+	cooling   atomic.Int64
+}
+
+// Declines returns how many auctions bidders declined because they were
+// already in MaxActive auctions and because they were cooling down after a win
+func (at *Attention) Declines() (saturated, cooling int) {
+	return int(at.saturated.Load()), int(at.cooling.Load())
+}
+
+// attention is one bidder's share of the Attention state. Bid goroutines for
+// concurrent auctions and the collector reporting wins all reach it, so every
+// field is guarded by mu.
+type attention struct {
+	mu       sync.Mutex
+	active   int
+	lastWin  time.Time
+	declined int
+}
+
+// engage reports whether the bidder has the attention to join auction. If so,
+// the auction counts against MaxActive until ctx ends, when it stops
+// collecting bids; if not, the decline is counted.
+func (b *Bidder) engage(ctx context.Context, auction *models.Auction) bool {
+	at := b.Attention
+	if at == nil {
+		return true
+	}
+
+	s := &b.attention
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case at.Cooldown > 0 && !s.lastWin.IsZero() && auction.StartTime.Before(s.lastWin.Add(at.Cooldown)):
+		at.cooling.Add(1)
+	case at.MaxActive > 0 && s.active >= at.MaxActive:
+		at.saturated.Add(1)
+	default:
+		s.active++
+		context.AfterFunc(ctx, func() {
+			s.mu.Lock()
+			s.active--
+			s.mu.Unlock()
+		})
+		return true
+	}
+	s.declined++
+	return false
+}
+
+// RecordWin starts the bidder's cooldown from the close of an auction it won.
+// Wins reported out of order keep the latest close.
+func (b *Bidder) RecordWin(closed time.Time) {
+	s := &b.attention
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if closed.After(s.lastWin) {
+		s.lastWin = closed
+	}
+}
+
+// Declined returns how many auctions the bidder declined for lack of attention
+func (b *Bidder) Declined() int {
+	s := &b.attention
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.declined
+}
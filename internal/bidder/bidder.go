@@ -1,9 +1,16 @@
 package bidder
 
 import (
+	"context"
+	"math/bits"
 	"math/rand"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"auction-simulator/internal/resource"
+	"auction-simulator/internal/rng"
 	"auction-simulator/pkg/models"
 )
 
@@ -11,65 +18,755 @@ import (
 type Bidder struct {
 	ID                int
 	ParticipationRate float64 // Probability of participating (0.6-0.8)
+	Group             *Group  // Affiliation group sharing value signals, nil if independent
+
+	Weights  *[20]float64 // Attribute weights, drawn once; nil draws new ones for every valuation
+	Budget   float64      // Maximum bid, 0 for no cap
+	Strategy Strategy     // How the valuation is turned into a bid
+
+	// Aggressiveness scales the valuation before the strategy applies:
+	// above 1 a risk-seeking bidder bids over what the item is worth to it,
+	// below 1 a cautious one shades its bid. 1 is neutral.
+	Aggressiveness float64
+
+	// VisibleAttributes marks the attributes the bidder observes; it values
+	// the others at hiddenAttributeGuess. Nil observes every attribute.
+	VisibleAttributes *[20]bool
+
+	// DeadlineAware caps the processing delay at the time left before the
+	// auction deadline, less deadlineMargin, so the bid is not missed
+	DeadlineAware bool
+
+	// Bounds clamps every calculated bid; nil leaves bids unbounded
+	Bounds *Bounds
+
+	// ProcessingTimeout bounds how long computing a bid may take; a bid not
+	// ready in time is abandoned and counted. Zero waits indefinitely.
+	ProcessingTimeout time.Duration
+
+	// Arrivals staggers when the bidder shows up to each auction; nil means
+	// it is there from the open until the close
+	Arrivals *Arrivals
+
+	// Throttle holds bid computation to the run's CPU quota; nil never waits
+	Throttle *resource.Throttle
+
+	// ProxyRate is the chance each bid is placed by proxy, leaving the
+	// calculated amount as a maximum for the auction to bid up to
+	ProxyRate float64
+
+	// CommonValueNoise bounds how far, as a fraction either way, the bidder's
+	// estimate of a common-value auction's true value may be off
+	CommonValueNoise float64
+
+	// Burst replaces the single bid with bursts of bids for load testing;
+	// nil places one bid per auction
+	Burst *Burst
+
+	// Attention bounds how many auctions the bidder joins at once and how
+	// soon after a win; nil leaves its attention unlimited
+	Attention *Attention
+	attention attention
+
+	// Seed is the seed of the bidder's own random source, which draws its
+	// participation rate and weights when it is created. Its participation,
+	// delays and valuations in each auction come from a stream derived from
+	// Seed and the auction ID; see auctionDraws. Every stream comes from
+	// source, the run's generator.
+	Seed   int64
+	source rng.Factory
+	rng    *rand.Rand
+
+	// sealed holds committed bids, by auction ID, until they are revealed
+	sealed   map[int]sealedBid
+	sealedMu sync.Mutex
+
+	// notified and participated count auctions seen and joined, for checking
+	// realized participation against ParticipationRate
+	notified     atomic.Int64
+	participated atomic.Int64
+	abandoned    atomic.Int64
+}
+
+// hiddenAttributeGuess is what a bidder assumes an attribute it cannot
+// observe is worth: the mean of the uniform attribute distribution
+const hiddenAttributeGuess = 0.5
+
+// maxBundleBids bounds how many bundles a bidder bids on in a combinatorial
+// auction, keeping winner determination linear in the number of bidders
+const maxBundleBids = 5
+
+// deadlineMargin is how long before the deadline a deadline-aware bidder aims
+// to have submitted its bid
+const deadlineMargin = 20 * time.Millisecond
+
+// Strategy selects how a bidder turns its valuation into a bid
+type Strategy string
+
+const (
+	// StrategyDefault bids the valuation with ±20% noise
+	StrategyDefault Strategy = "default"
+	// StrategyAggressive bids at or above the valuation (100-120%)
+	StrategyAggressive Strategy = "aggressive"
+	// StrategyConservative shades bids well below the valuation (60-90%)
+	StrategyConservative Strategy = "conservative"
+	// StrategyValueBased bids the valuation without noise
+	StrategyValueBased Strategy = "value-based"
+)
+
+// Group is a set of affiliated bidders that share information about item value.
+// Members still compete, but their valuations are correlated through a common signal.
+type Group struct {
+	ID     int
+	seed   int64
+	source rng.Factory
+}
+
+// NewGroup creates an affiliation group with a seed drawn from src, whose
+// signals come from source
+func NewGroup(id int, src *rand.Rand, source rng.Factory) *Group {
+	return &Group{
+		ID:     id,
+		seed:   src.Int63(),
+		source: source,
+	}
+}
+
+// Signal returns the group's shared valuation factor (0.8-1.2) for an auction.
+// It is derived deterministically from the group seed and auction ID, so every
+// member sees the same value without any shared mutable state.
+func (g *Group) Signal(auctionID int) float64 {
+	r := g.source.New(g.seed + int64(auctionID))
+	return 0.8 + r.Float64()*0.4
+}
+
+// NewBidder creates a new bidder with given ID and strategy and its own
+// stream from source, seeded from src
+func NewBidder(id int, src *rand.Rand, source rng.Factory, strategy Strategy) *Bidder {
+	b := NewBidderFromSeed(id, src.Int63(), source)
+	b.Strategy = strategy
+	return b
+}
+
+// NewBidderFromSeed creates the default-strategy bidder that NewBidder
+// generates when it draws seed, so a single bidder can be recreated and
+// replayed on its own
+func NewBidderFromSeed(id int, seed int64, source rng.Factory) *Bidder {
+	b := &Bidder{
+		ID:             id,
+		Strategy:       StrategyDefault,
+		Aggressiveness: 1,
+		Seed:           seed,
+		source:         source,
+		rng:            source.New(seed),
+	}
+	b.ParticipationRate = 0.6 + b.rng.Float64()*0.2 // 60-80% participation rate
+	b.Weights = b.drawWeights()
+	return b
+}
+
+// drawWeights draws a fixed attribute weight vector from the bidder's source,
+// so it values the same auction consistently
+func (b *Bidder) drawWeights() *[20]float64 {
+	var weights [20]float64
+	for i := range weights {
+		weights[i] = b.rng.Float64()
+	}
+	return &weights
+}
+
+// auctionSeedStride spaces the seeds of a bidder's per-auction streams, so
+// bidders whose seeds are close together, as hand-written profiles' often
+// are, still get unrelated streams for each auction
+const auctionSeedStride = 0x5851f42d4c957f2d
+
+// draws is a bidder's random stream for one auction. What the bidder draws
+// for an auction then does not depend on how its goroutines for other
+// auctions are scheduled, so a seed reproduces every bid of a concurrent run.
+// Goroutines of the same auction, such as a Dutch clock's acceptances, may
+// share it, so draws are serialized.
+type draws struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+// auctionDraws returns the bidder's stream for an auction, seeded from its
+// seed and the auction ID
+func (b *Bidder) auctionDraws(auctionID int) *draws {
+	return &draws{r: b.source.New(b.Seed + int64(auctionID)*auctionSeedStride)}
+}
+
+// float64 draws a float64 in [0, 1)
+func (d *draws) float64() float64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.r.Float64()
+}
+
+// intn draws an int in [0, n)
+func (d *draws) intn(n int) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.r.Intn(n)
+}
+
+// Profile describes the bidder in the bidders file format, including its seed
+func (b *Bidder) Profile() models.BidderProfile {
+	p := models.BidderProfile{
+		ID:                b.ID,
+		ParticipationRate: b.ParticipationRate,
+		Budget:            b.Budget,
+		Strategy:          string(b.Strategy),
+		Aggressiveness:    b.Aggressiveness,
+		DeadlineAware:     b.DeadlineAware,
+		Seed:              b.Seed,
+	}
+	if b.Weights != nil {
+		p.Weights = b.Weights[:]
+	}
+	if b.VisibleAttributes != nil {
+		p.VisibleAttributes = []int{}
+		for i, visible := range b.VisibleAttributes {
+			if visible {
+				p.VisibleAttributes = append(p.VisibleAttributes, i)
+			}
+		}
+	}
+	return p
+}
+
+// Participation returns how many auctions the bidder was notified of and how
+// many of those it decided to join
+func (b *Bidder) Participation() (notified, participated int) {
+	return int(b.notified.Load()), int(b.participated.Load())
+}
+
+// Abandoned returns how many of the bidder's bids were abandoned for
+// exceeding ProcessingTimeout
+func (b *Bidder) Abandoned() int {
+	return int(b.abandoned.Load())
+}
+
+// Bounds is an economic floor and ceiling on calculated bids, shared by the
+// bidder population. Amounts outside it are clamped, not dropped, and each
+// clamp is counted. A zero Min or Max leaves that side unbounded.
+type Bounds struct {
+	Min float64
+	Max float64
+
+	atMin atomic.Int64
+	atMax atomic.Int64
+}
+
+// clamp returns amount limited to the bounds, counting any adjustment
+func (bb *Bounds) clamp(amount float64) float64 {
+	if bb.Min > 0 && amount < bb.Min {
+		bb.atMin.Add(1)
+		return bb.Min
+	}
+	if bb.Max > 0 && amount > bb.Max {
+		bb.atMax.Add(1)
+		return bb.Max
+	}
+	return amount
+}
+
+// Clamped returns how many bids were raised to Min and lowered to Max
+func (bb *Bounds) Clamped() (atMin, atMax int) {
+	return int(bb.atMin.Load()), int(bb.atMax.Load())
+}
+
+// Tracker counts bid goroutines that have been started but not yet finished
+// and optionally caps how many may run at once across all auctions
+type Tracker struct {
+	pending atomic.Int64
+	peak    atomic.Int64
+	late    atomic.Int64
+
+	// slots holds one token per running bid goroutine; nil means unlimited
+	slots chan struct{}
+
+	// flights holds each auction's running bid goroutines, so an auction can
+	// wait for its own before it closes
+	flights  map[int]*flight
+	flightMu sync.Mutex
+}
+
+// flight is one auction's running bid goroutines; done is closed once the
+// last finishes
+type flight struct {
+	n    int
+	done chan struct{}
+}
+
+// NewTracker creates a tracker allowing at most limit concurrent bid
+// goroutines; a limit of zero or less means unlimited
+func NewTracker(limit int) *Tracker {
+	t := &Tracker{flights: make(map[int]*flight)}
+	if limit > 0 {
+		t.slots = make(chan struct{}, limit)
+	}
+	return t
+}
+
+// Peak returns the highest number of bid goroutines that ran at once
+func (t *Tracker) Peak() int {
+	return int(t.peak.Load())
+}
+
+// acquire reserves a slot for a new bid goroutine for auctionID, blocking
+// while the limit is reached. It returns false if ctx ends first.
+func (t *Tracker) acquire(ctx context.Context, auctionID int) bool {
+	if t.slots != nil {
+		select {
+		case t.slots <- struct{}{}:
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	t.flightMu.Lock()
+	f := t.flights[auctionID]
+	if f == nil {
+		f = &flight{done: make(chan struct{})}
+		t.flights[auctionID] = f
+	}
+	f.n++
+	t.flightMu.Unlock()
+
+	n := t.pending.Add(1)
+	for {
+		peak := t.peak.Load()
+		if n <= peak || t.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	return true
+}
+
+// release frees the slot held by a finished bid goroutine for auctionID
+func (t *Tracker) release(auctionID int) {
+	t.flightMu.Lock()
+	f := t.flights[auctionID]
+	if f.n--; f.n == 0 {
+		close(f.done)
+		delete(t.flights, auctionID)
+	}
+	t.flightMu.Unlock()
+
+	t.pending.Add(-1)
+	if t.slots != nil {
+		<-t.slots
+	}
+}
+
+// LateBids returns how many bids were ready only after their auction's deadline
+func (t *Tracker) LateBids() int {
+	return int(t.late.Load())
 }
 
-// NewBidder creates a new bidder with given ID
-func NewBidder(id int) *Bidder {
-	return &Bidder{
-		ID:                id,
-		ParticipationRate: 0.6 + rand.Float64()*0.2, // 60-80% participation rate
+// AddLate counts bids found late outside a bid goroutine, as the serial path does
+func (t *Tracker) AddLate(n int) {
+	t.late.Add(int64(n))
+}
+
+// Pending returns the number of bid goroutines still in flight
+func (t *Tracker) Pending() int {
+	return int(t.pending.Load())
+}
+
+// Drain waits up to timeout for the bid goroutines of auctionID to finish
+func (t *Tracker) Drain(auctionID int, timeout time.Duration) {
+	t.flightMu.Lock()
+	f := t.flights[auctionID]
+	t.flightMu.Unlock()
+	if f == nil {
+		return
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-f.done:
+	case <-timer.C:
 	}
 }
 
-// ConsiderBid decides whether to bid and places a bid if decided to participate
-func (b *Bidder) ConsiderBid(auction *models.Auction, bidChan chan<- models.Bid) {
+// ConsiderBid decides whether to bid and places a bid if decided to participate.
+// A bidder whose attention is saturated declines the auction. The bid goroutine
+// is registered with tracker, which may be nil; if tracker is at its limit,
+// ConsiderBid blocks until a slot frees up or ctx ends.
+func (b *Bidder) ConsiderBid(ctx context.Context, auction *models.Auction, bidChan chan<- models.Bid, tracker *Tracker) {
 	// Decide whether to participate
-	if rand.Float64() > b.ParticipationRate {
+	b.notified.Add(1)
+	d := b.auctionDraws(auction.ID)
+	if d.float64() > b.ParticipationRate {
 		return // Not participating in this auction
 	}
+	if !b.engage(ctx, auction) {
+		return // Busy with other auctions or cooling down after a win
+	}
+	b.participated.Add(1)
 
-	go b.placeBid(auction, bidChan)
+	p := b.arrive(auction, d)
+	spawn(ctx, tracker, auction.ID, func() {
+		if p.wait(ctx, auction) {
+			b.placeBid(auction, bidChan, tracker, p, d)
+		}
+	})
 }
 
-// placeBid calculates and places a bid for the given auction
-func (b *Bidder) placeBid(auction *models.Auction, bidChan chan<- models.Bid) {
-	// Simulate processing delay (10-500ms)
-	processingDelay := time.Duration(10+rand.Intn(490)) * time.Millisecond
-	time.Sleep(processingDelay)
+// spawn runs fn on a new goroutine registered with tracker, which may be nil.
+// If tracker is at its limit, spawn blocks until a slot frees up, and drops
+// fn if ctx ends first.
+func spawn(ctx context.Context, tracker *Tracker, auctionID int, fn func()) {
+	if tracker == nil {
+		go fn()
+		return
+	}
+
+	if !tracker.acquire(ctx, auctionID) {
+		return // Auction ended while waiting for a slot
+	}
+	go func() {
+		defer tracker.release(auctionID)
+		fn()
+	}()
+}
 
-	// Calculate bid amount based on weighted attribute scoring
-	bidAmount := b.calculateBid(auction.Attributes)
+// placeBid calculates and places a bid for the given auction once the bidder
+// is present. Bids that are ready only after the deadline are counted as late
+// on tracker, if non-nil; those ready after the bidder departed are dropped.
+func (b *Bidder) placeBid(auction *models.Auction, bidChan chan<- models.Bid, tracker *Tracker, p presence, d *draws) {
+	deadline := auction.Deadline()
+	time.Sleep(b.processingDelay(d, time.Until(p.until(auction, deadline))))
+	b.Throttle.Pace()
 
-	bid := models.Bid{
-		BidderID:  b.ID,
-		Amount:    bidAmount,
-		Timestamp: time.Now(),
+	bid, ok := b.computeBid(auction, time.Now(), d)
+	if !ok {
+		return
+	}
+	if p.departed(auction, bid.Timestamp) {
+		auction.MarkLeftEarly(b.ID)
+		return
+	}
+	if bid.Timestamp.After(deadline) {
+		if tracker != nil {
+			tracker.late.Add(1)
+		}
+		return
+	}
+	if b.Burst != nil {
+		b.burst(auction, bid, bidChan, p, d)
+		return
+	}
+	if bid, ok = b.meetIncrement(auction, bid); !ok {
+		return
 	}
 
 	// Try to submit bid (may fail if auction has already closed)
+	auction.Send(bidChan, bid)
+}
+
+// meetIncrement checks bid against the auction's minimum increment before it
+// is sent. A bid short of the minimum is raised to it if the bidder values the
+// item that much and its budget and the bid bounds allow; otherwise it is
+// withheld, since the auction would reject it. The leader can still move
+// before the bid arrives, in which case the auction rejects it anyway.
+func (b *Bidder) meetIncrement(auction *models.Auction, bid models.Bid) (models.Bid, bool) {
+	amount, cents, ok := auction.MinimumBid()
+	if !ok || bid.Amount >= amount {
+		return bid, true
+	}
+	if amount > bid.Valuation || (b.Budget > 0 && amount > b.Budget) || (b.Bounds != nil && b.Bounds.Max > 0 && amount > b.Bounds.Max) {
+		return bid, false
+	}
+	bid.Amount, bid.Cents = amount, cents
+	if bid.MaxBid > 0 {
+		bid.MaxBid, bid.MaxBidCents = amount, cents
+	}
+	return bid, true
+}
+
+// Bid is the serial counterpart of ConsiderBid: it decides whether to bid and
+// returns the bid without sleeping or sending it, timestamped at the simulated
+// moment its processing delay ends after the bidder arrived
+func (b *Bidder) Bid(auction *models.Auction) (models.Bid, bool) {
+	b.notified.Add(1)
+	d := b.auctionDraws(auction.ID)
+	if d.float64() > b.ParticipationRate {
+		return models.Bid{}, false
+	}
+	b.participated.Add(1)
+
+	deadline := auction.Deadline()
+	p := b.arrive(auction, d)
+	arrived := auction.StartTime.Add(p.arrive)
+	delay := b.processingDelay(d, p.until(auction, deadline).Sub(arrived))
+	bid, ok := b.computeBid(auction, arrived.Add(delay), d)
+	if ok && p.departed(auction, bid.Timestamp) {
+		auction.MarkLeftEarly(b.ID)
+		return models.Bid{}, false
+	}
+	return bid, ok
+}
+
+// computeBid runs newBid, giving up once ProcessingTimeout passes. A valuation
+// that hangs then costs only its own goroutine, never the auction's
+// responsiveness; the abandoned bid is counted.
+func (b *Bidder) computeBid(auction *models.Auction, at time.Time, d *draws) (models.Bid, bool) {
+	if b.ProcessingTimeout <= 0 {
+		return b.newBid(auction, at, d), true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.ProcessingTimeout)
+	defer cancel()
+
+	result := make(chan models.Bid, 1) // Buffered so an abandoned computation can still finish
+	go func() {
+		result <- b.newBid(auction, at, d)
+	}()
+
 	select {
-	case bidChan <- bid:
-		// Bid submitted successfully
+	case bid := <-result:
+		return bid, true
+	case <-ctx.Done():
+		b.abandoned.Add(1)
+		return models.Bid{}, false
+	}
+}
+
+// processingDelay draws how long the bidder takes to compute a bid (10-500ms).
+// A deadline-aware bidder caps it to finish deadlineMargin before the time
+// left runs out.
+func (b *Bidder) processingDelay(d *draws, left time.Duration) time.Duration {
+	delay := time.Duration(10+d.intn(490)) * time.Millisecond
+	if b.DeadlineAware {
+		delay = max(min(delay, left-deadlineMargin), 0)
+	}
+	return delay
+}
+
+// newBid calculates the bidder's bid for auction, stamped with at. In a
+// multi-unit auction the bid carries a demand schedule priced from its first
+// unit.
+func (b *Bidder) newBid(auction *models.Auction, at time.Time, d *draws) models.Bid {
+	bid := models.Bid{
+		BidderID:  b.ID,
+		Timestamp: at,
+		Strategy:  string(b.Strategy),
+	}
+	switch {
+	case auction.BundleItems > 1:
+		bid.Bundles, bid.Valuation = b.considerBundle(auction, d)
+		for _, bundle := range bid.Bundles {
+			bid.Amount = max(bid.Amount, bundle.Amount)
+		}
+	case auction.Units > 1:
+		bid.Demand, bid.Valuation = b.considerDemand(auction, d)
+		bid.Amount = bid.Demand[0].Price
 	default:
-		// Channel closed or full, auction likely ended
+		// Calculate bid amount based on weighted attribute scoring
+		bid.Amount, bid.Valuation = b.calculateBid(auction, d)
+	}
+	if b.Group != nil {
+		bid.GroupID = b.Group.ID
+	}
+	if auction.IntegerAmounts {
+		bid.Cents = models.ToCents(bid.Amount)
+	}
+	if b.ProxyRate > 0 && d.float64() < b.ProxyRate {
+		bid.MaxBid, bid.MaxBidCents = bid.Amount, bid.Cents
+	}
+	return bid
+}
+
+// considerDemand returns the bidder's demand schedule for a multi-unit
+// auction. The first unit is valued like a single-item bid; each further unit
+// is worth a fixed fraction (60-95%) of the one before, as marginal value
+// falls with quantity. A budget caps the total the schedule can commit. It
+// also returns the bidder's valuation of the first unit.
+func (b *Bidder) considerDemand(auction *models.Auction, d *draws) ([]models.DemandPoint, float64) {
+	price, valuation := b.calculateBid(auction, d)
+	decay := 0.6 + d.float64()*0.35
+
+	schedule := []models.DemandPoint{{Quantity: 1, Price: price}}
+	total := price
+	for q := 2; q <= auction.Units; q++ {
+		price *= decay
+		if auction.IntegerAmounts {
+			price = models.FromCents(models.ToCents(price))
+		}
+		if b.Budget > 0 && total+price > b.Budget {
+			break
+		}
+		total += price
+		schedule = append(schedule, models.DemandPoint{Quantity: q, Price: price})
+	}
+	if auction.IntegerAmounts {
+		for i := range schedule {
+			schedule[i].Cents = models.ToCents(schedule[i].Price)
+		}
+	}
+	return schedule, valuation
+}
+
+// considerBundle returns the bidder's bids on bundles of a combinatorial
+// auction's items. Each item is worth what the bidder would bid on its block
+// of attributes alone; a bundle adds a complementarity premium, drawn per
+// bid (0-30%), that grows with its size, so the bidder prefers the items
+// together. It bids on every item together and on up to maxBundleBids-1 other
+// bundles drawn at random, each capped by its budget. It also returns the
+// bidder's valuation of every item together.
+func (b *Bidder) considerBundle(auction *models.Auction, d *draws) ([]models.BundleBid, float64) {
+	ap := b.appraise(auction, d)
+	items := auction.BundleItems
+	premium := d.float64() * 0.3
+	synergy := func(mask uint32) float64 {
+		return 1 + premium*float64(bits.OnesCount32(mask)-1)/float64(items-1)
+	}
+
+	all := uint32(1)<<items - 1
+	masks := []uint32{all}
+	for range maxBundleBids - 1 {
+		mask := uint32(1 + d.intn(int(all)))
+		if !slices.Contains(masks, mask) {
+			masks = append(masks, mask)
+		}
+	}
+
+	bundles := make([]models.BundleBid, 0, len(masks))
+	for _, mask := range masks {
+		var bundle models.BundleBid
+		var amount float64
+		for item := range items {
+			if mask&(1<<item) == 0 {
+				continue
+			}
+			lo, hi := models.ItemAttributes(item, items)
+			bundle.Items = append(bundle.Items, item)
+			amount += ap.bid(lo, hi)
+			bundle.Valuation += ap.value(lo, hi)
+		}
+		bundle.Valuation *= synergy(mask)
+		bundle.Amount = b.finishBid(auction, amount*synergy(mask))
+		if auction.IntegerAmounts {
+			bundle.Cents = models.ToCents(bundle.Amount)
+		}
+		bundles = append(bundles, bundle)
+	}
+	return bundles, bundles[0].Valuation
+}
+
+// calculateBid calculates bid amount based on auction attributes, rounded to
+// whole cents when the auction uses integer amounts
+func (b *Bidder) calculateBid(auction *models.Auction, d *draws) (amount, valuation float64) {
+	if auction.TrueValue > 0 {
+		return b.estimateBid(auction, d)
 	}
+	ap := b.appraise(auction, d)
+	return b.finishBid(auction, ap.bid(0, 20)), ap.value(0, 20)
 }
 
-// calculateBid calculates bid amount based on auction attributes
-func (b *Bidder) calculateBid(attributes [20]float64) float64 {
-	// Generate random weights for this bidder's preferences
-	var score float64
+// estimateBid bids on a common-value auction from the bidder's noisy
+// estimate of its true value, scaled by its aggressiveness and strategy. The
+// item is worth its true value to whoever wins it, so that is the valuation.
+func (b *Bidder) estimateBid(auction *models.Auction, d *draws) (amount, valuation float64) {
+	estimate := auction.TrueValue * (1 + b.CommonValueNoise*(2*d.float64()-1))
+	amount = estimate * b.shade(d, auction.Attributes)
+	if b.Aggressiveness > 0 {
+		amount *= b.Aggressiveness
+	}
+	return b.finishBid(auction, amount), auction.TrueValue
+}
+
+// appraisal is a bidder's reading of one auction: each attribute's weighted
+// score, in full and as observed, and the noise and strategy factors that turn
+// scores into a valuation and a bid
+type appraisal struct {
+	score, observed             [20]float64
+	randomFactor, strategyScale float64
+	aggressiveness              float64
+}
+
+// appraise scores every attribute of auction and draws the bid's noise
+func (b *Bidder) appraise(auction *models.Auction, d *draws) appraisal {
+	// Use the bidder's fixed preferences, or random weights if it has none,
+	// scaled by how much the auction says each attribute matters. The bid
+	// scores only what the bidder observes; its valuation scores everything.
+	ap := appraisal{aggressiveness: b.Aggressiveness, strategyScale: 1}
 	for i := 0; i < 20; i++ {
-		weight := rand.Float64()
-		score += attributes[i] * weight
+		var weight float64
+		if b.Weights != nil {
+			weight = b.Weights[i]
+		} else {
+			weight = d.float64()
+		}
+		if auction.AttributeImportance != nil {
+			weight *= auction.AttributeImportance[i]
+		}
+		ap.score[i] = auction.Attributes[i] * weight
+		if b.VisibleAttributes == nil || b.VisibleAttributes[i] {
+			ap.observed[i] = auction.Attributes[i] * weight
+		} else {
+			ap.observed[i] = hiddenAttributeGuess * weight
+		}
 	}
 
-	// Normalize and scale to a reasonable bid range (e.g., 100-10000)
-	bidAmount := 100 + (score/20)*9900
+	// Add the strategy's noise. Grouped bidders share most of theirs through
+	// the group signal.
+	ap.randomFactor = b.noise(d)
+	if b.Group != nil {
+		ap.randomFactor *= b.Group.Signal(auction.ID)
+	}
 
-	// Add some randomness (±20%)
-	randomFactor := 0.8 + rand.Float64()*0.4
-	bidAmount *= randomFactor
+	ap.strategyScale = b.shade(d, ap.observed)
+	return ap
+}
+
+// worth normalizes a score summed over attributes [lo, hi) and scales it to a
+// reasonable bid range (e.g., 100-10000 over all 20)
+func worth(sum float64, lo, hi int) float64 {
+	return 100*float64(hi-lo)/20 + (sum/20)*9900
+}
 
+// value is the bidder's valuation of attributes [lo, hi)
+func (ap appraisal) value(lo, hi int) float64 {
+	score := 0.0
+	for _, s := range ap.score[lo:hi] {
+		score += s
+	}
+	return worth(score, lo, hi) * ap.randomFactor
+}
+
+// bid is the bidder's raw bid for attributes [lo, hi), before its budget and
+// bounds apply
+func (ap appraisal) bid(lo, hi int) float64 {
+	observed := 0.0
+	for _, s := range ap.observed[lo:hi] {
+		observed += s
+	}
+	amount := worth(observed, lo, hi) * ap.randomFactor
+	if ap.aggressiveness > 0 {
+		amount *= ap.aggressiveness
+	}
+	return amount * ap.strategyScale
+}
+
+// finishBid caps a raw bid by the bidder's budget and bounds, rounding it to
+// whole cents when the auction uses integer amounts
+func (b *Bidder) finishBid(auction *models.Auction, bidAmount float64) float64 {
+	if b.Budget > 0 {
+		bidAmount = min(bidAmount, b.Budget)
+	}
+
+	// The economic bounds apply last, so they hold even against a budget
+	if b.Bounds != nil {
+		bidAmount = b.Bounds.clamp(bidAmount)
+	}
+
+	if auction.IntegerAmounts {
+		return models.FromCents(models.ToCents(bidAmount))
+	}
 	return bidAmount
 }
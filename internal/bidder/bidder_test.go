@@ -0,0 +1,122 @@
+package bidder
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"auction-simulator/pkg/models"
+)
+
+// TestBoundsClamp drives calculateBid with extreme parameters; every amount
+// must land within the bounds, and the clamps must be counted at the bound
+// they hit
+func TestBoundsClamp(t *testing.T) {
+	tests := []struct {
+		name           string
+		aggressiveness float64
+		attribute      float64
+		budget         float64
+		min, max       float64
+		atMin, atMax   bool
+	}{
+		{"huge aggressiveness", 1e6, 10, 0, 150, 900, false, true},
+		{"tiny aggressiveness", 1e-6, 10, 0, 150, 900, true, false},
+		{"worthless item", 1, 0, 0, 150, 900, true, false},
+		{"priceless item", 1, 1e9, 0, 150, 900, false, true},
+		{"budget below the floor", 1, 10, 50, 150, 900, true, false},
+		{"floor only", 1e-6, 10, 0, 150, 0, true, false},
+		{"ceiling only", 1e6, 10, 0, 0, 900, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBidderFromSeed(1, 42, nil)
+			b.Aggressiveness = tt.aggressiveness
+			b.Budget = tt.budget
+			b.Bounds = &Bounds{Min: tt.min, Max: tt.max}
+
+			const auctions = 200
+			for id := 1; id <= auctions; id++ {
+				auction := models.NewAuction(id, time.Second, 0)
+				for i := range auction.Attributes {
+					auction.Attributes[i] = tt.attribute
+				}
+				amount, _ := b.calculateBid(auction, b.auctionDraws(id))
+				if (tt.min > 0 && amount < tt.min) || (tt.max > 0 && amount > tt.max) {
+					t.Fatalf("auction %d: amount %v outside [%v, %v]", id, amount, tt.min, tt.max)
+				}
+			}
+
+			atMin, atMax := b.Bounds.Clamped()
+			if want := clampCount(tt.atMin, auctions); atMin != want {
+				t.Errorf("%d bids raised to the floor, want %d", atMin, want)
+			}
+			if want := clampCount(tt.atMax, auctions); atMax != want {
+				t.Errorf("%d bids lowered to the ceiling, want %d", atMax, want)
+			}
+		})
+	}
+}
+
+// clampCount is how many of n bids a bound should have clamped
+func clampCount(clamped bool, n int) int {
+	if clamped {
+		return n
+	}
+	return 0
+}
+
+// TestStableWeights values the same auction twice with one bidder. Its fixed
+// weights must score the auction identically each time, so its valuations
+// differ only by the explicit random factor; a fresh per-auction stream
+// reproduces the bid exactly.
+func TestStableWeights(t *testing.T) {
+	b := NewBidderFromSeed(1, 42, nil)
+	auction := models.NewAuction(7, time.Second, 0)
+	for i := range auction.Attributes {
+		auction.Attributes[i] = float64(i%10) + 0.5
+	}
+
+	// Two appraisals drawing from one stream get different random factors
+	d := b.auctionDraws(auction.ID)
+	first, second := b.appraise(auction, d), b.appraise(auction, d)
+	if first.score != second.score {
+		t.Errorf("attribute scores differ between calls:\n%v\n%v", first.score, second.score)
+	}
+	if first.randomFactor == second.randomFactor {
+		t.Fatalf("both appraisals drew random factor %v; the test needs two draws", first.randomFactor)
+	}
+	if x, y := first.value(0, 20)/first.randomFactor, second.value(0, 20)/second.randomFactor; math.Abs(x-y) > 1e-9 {
+		t.Errorf("valuations without the random factor differ: %v and %v", x, y)
+	}
+
+	amount1, value1 := b.calculateBid(auction, b.auctionDraws(auction.ID))
+	amount2, value2 := b.calculateBid(auction, b.auctionDraws(auction.ID))
+	if amount1 != amount2 || value1 != value2 {
+		t.Errorf("calculateBid gave %v (value %v), then %v (value %v)", amount1, value1, amount2, value2)
+	}
+}
+
+// TestTrackerDrain checks that draining an auction waits for its own bid
+// goroutines only, and gives up at the timeout
+func TestTrackerDrain(t *testing.T) {
+	tracker := NewTracker(0)
+	spawn(context.Background(), tracker, 1, func() { time.Sleep(50 * time.Millisecond) })
+	spawn(context.Background(), tracker, 2, func() { time.Sleep(time.Second) })
+
+	start := time.Now()
+	tracker.Drain(1, 5*time.Second)
+	if waited := time.Since(start); waited < 50*time.Millisecond || waited > 500*time.Millisecond {
+		t.Errorf("draining auction 1 took %v, want about 50ms", waited)
+	}
+	start = time.Now()
+	tracker.Drain(2, 100*time.Millisecond)
+	if waited := time.Since(start); waited > 500*time.Millisecond {
+		t.Errorf("draining auction 2 took %v, want the 100ms timeout", waited)
+	}
+	if pending := tracker.Pending(); pending != 1 {
+		t.Errorf("%d bid goroutines pending, want auction 2's", pending)
+	}
+	tracker.Drain(3, time.Second) // Nothing in flight: returns at once
+}
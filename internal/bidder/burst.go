@@ -0,0 +1,52 @@
+package bidder
+
+import (
+	"sync/atomic"
+	"time"
+
+	"auction-simulator/pkg/models"
+)
+
+// Burst turns bidders into a load generator for the collector: instead of a
+// single bid, each sends Count bursts of Size copies of its bid back to back,
+// every burst after its own processing delay. The population shares one Burst
+// so sent and dropped bids are counted in one place.
+type Burst struct {
+	Size  int
+	Count int
+
+	sent    atomic.Int64
+	dropped atomic.Int64
+}
+
+// Counts returns how many burst bids were sent and how many of those were
+// dropped because the auction's bid channel was full
+func (bb *Burst) Counts() (sent, dropped int) {
+	return int(bb.sent.Load()), int(bb.dropped.Load())
+}
+
+// burst sends the bidder's bursts for auction, starting at once with bid and
+// stopping early once the bidder can no longer bid or the auction has closed
+func (b *Bidder) burst(auction *models.Auction, bid models.Bid, bidChan chan<- models.Bid, p presence, d *draws) {
+	until := p.until(auction, auction.Deadline())
+	for n := range b.Burst.Count {
+		if n > 0 {
+			time.Sleep(b.processingDelay(d, time.Until(until)))
+		}
+		if time.Now().After(until) || auction.IsClosed() {
+			return
+		}
+		for range b.Burst.Size {
+			b.Throttle.Pace()
+			bid.Timestamp = time.Now()
+			open, sent := auction.Send(bidChan, bid)
+			if !open {
+				return
+			}
+			b.Burst.sent.Add(1)
+			if !sent {
+				b.Burst.dropped.Add(1)
+			}
+		}
+	}
+}
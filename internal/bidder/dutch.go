@@ -0,0 +1,118 @@
+package bidder
+
+import (
+	"context"
+	"time"
+
+	"auction-simulator/pkg/models"
+)
+
+// ClockWatcher is a bidder watching one Dutch auction's clock. Its valuation
+// and the highest price it accepts are settled when it starts watching.
+type ClockWatcher struct {
+	bidder           *Bidder
+	draws            *draws
+	valuation, limit float64
+}
+
+// Watch decides whether the bidder watches a Dutch auction's clock, the
+// clock counterpart of deciding to bid in an open auction. It returns nil if
+// the bidder sits the auction out.
+func (b *Bidder) Watch(auction *models.Auction) *ClockWatcher {
+	b.notified.Add(1)
+	d := b.auctionDraws(auction.ID)
+	if d.float64() > b.ParticipationRate {
+		return nil // Not participating in this auction
+	}
+	b.participated.Add(1)
+
+	signal := 1.0
+	if b.Group != nil {
+		signal = b.Group.Signal(auction.ID)
+	}
+	w := &ClockWatcher{bidder: b, draws: d}
+	w.valuation, w.limit = b.dutchLimit(auction.Attributes, auction.AttributeImportance, signal)
+	return w
+}
+
+// ConsiderPrice is the clock counterpart of ConsiderBid: a watching bidder
+// accepts the announced price if it will pay it, by sending a bid for exactly
+// that price after a reaction time of 1-50ms
+func (w *ClockWatcher) ConsiderPrice(ctx context.Context, auction *models.Auction, price float64, accepts chan<- models.Bid, tracker *Tracker) {
+	if price > w.limit {
+		return
+	}
+
+	b := w.bidder
+	spawn(ctx, tracker, auction.ID, func() {
+		time.Sleep(time.Duration(1+w.draws.intn(50)) * time.Millisecond)
+
+		bid := models.Bid{
+			BidderID:  b.ID,
+			Amount:    price,
+			Valuation: w.valuation,
+			Strategy:  string(b.Strategy),
+			Timestamp: time.Now(),
+		}
+		if b.Group != nil {
+			bid.GroupID = b.Group.ID
+		}
+		if auction.IntegerAmounts {
+			bid.Cents = models.ToCents(price)
+		}
+		auction.Send(accepts, bid)
+	})
+}
+
+// WillAcceptAt reports whether the bidder would stop a Dutch clock at price
+// for an item with the given attributes, weighing them equally
+func (b *Bidder) WillAcceptAt(price float64, attrs [20]float64) bool {
+	_, limit := b.dutchLimit(attrs, nil, 1)
+	return price <= limit
+}
+
+// dutchLimit returns the bidder's valuation of attrs and the highest clock
+// price it accepts for them. Unlike a bid it draws no noise, so the bidder
+// gives the same answer at every tick; signal scales both as a group's common
+// signal does. The limit scores only what the bidder observes and is capped
+// by its budget and bounds.
+func (b *Bidder) dutchLimit(attrs [20]float64, importance *[20]float64, signal float64) (valuation, limit float64) {
+	var score, observed float64
+	for i, attr := range attrs {
+		weight := 0.5 // The mean of a drawn weight, for a bidder without fixed ones
+		if b.Weights != nil {
+			weight = b.Weights[i]
+		}
+		if importance != nil {
+			weight *= importance[i]
+		}
+		score += attr * weight
+		if b.VisibleAttributes == nil || b.VisibleAttributes[i] {
+			observed += attr * weight
+		} else {
+			observed += hiddenAttributeGuess * weight
+		}
+	}
+
+	// A clock bidder draws no noise, so each strategy shades by the midpoint
+	// of its range for open bids
+	scale := 1.0
+	switch b.Strategy {
+	case StrategyAggressive:
+		scale = 1.1
+	case StrategyConservative:
+		scale = 0.75
+	}
+	valuation = worth(score, 0, 20) * signal
+	limit = worth(observed, 0, 20) * signal * scale
+	if b.Aggressiveness > 0 {
+		limit *= b.Aggressiveness
+	}
+	if b.Budget > 0 {
+		limit = min(limit, b.Budget)
+	}
+	if b.Bounds != nil && b.Bounds.Max > 0 {
+		limit = min(limit, b.Bounds.Max)
+	}
+	return valuation, limit
+}
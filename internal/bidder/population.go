@@ -0,0 +1,222 @@
+package bidder
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"auction-simulator/internal/rng"
+	"auction-simulator/pkg/models"
+)
+
+// LoadProfiles reads a bidder population from a JSON or CSV file, chosen by
+// extension. A JSON file holds an array of profiles. A CSV file has the header
+// id,participation_rate,budget,strategy,weights where weights is empty or 20
+// space-separated values. Every profile is validated.
+func LoadProfiles(path string) ([]models.BidderProfile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bidders file: %w", err)
+	}
+	defer file.Close()
+
+	var profiles []models.BidderProfile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		profiles, err = readProfilesJSON(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse bidders file: %w", err)
+		}
+	case ".csv":
+		profiles, err = readProfilesCSV(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse bidders file: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported bidders file extension %q (want .json or .csv)", filepath.Ext(path))
+	}
+
+	if err := ValidateProfiles(profiles); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// readProfilesJSON parses a JSON array of profiles one entry at a time, so a
+// malformed entry is reported with its index and the line it starts on
+func readProfilesJSON(r io.Reader) ([]models.BidderProfile, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if tok, err := dec.Token(); err != nil {
+		return nil, err
+	} else if tok != json.Delim('[') {
+		return nil, errors.New("want a JSON array of profiles")
+	}
+
+	var profiles []models.BidderProfile
+	for i := 0; dec.More(); i++ {
+		line := lineAt(data, dec.InputOffset())
+		var p models.BidderProfile
+		if err := dec.Decode(&p); err != nil {
+			return nil, fmt.Errorf("bidder %d (line %d): %w", i, line, err)
+		}
+		profiles = append(profiles, p)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// lineAt returns the line of the first value at or after offset in data,
+// skipping the whitespace and comma that separate array entries
+func lineAt(data []byte, offset int64) int {
+	rest := bytes.TrimLeft(data[offset:], " \t\r\n,")
+	start := len(data) - len(rest)
+	return bytes.Count(data[:start], []byte("\n")) + 1
+}
+
+// readProfilesCSV parses the CSV population format described in LoadProfiles
+func readProfilesCSV(r io.Reader) ([]models.BidderProfile, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 5
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	want := []string{"id", "participation_rate", "budget", "strategy", "weights"}
+	if !slices.Equal(header, want) {
+		return nil, fmt.Errorf("header must be %s", strings.Join(want, ","))
+	}
+
+	var profiles []models.BidderProfile
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return profiles, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		p := models.BidderProfile{Strategy: record[3]}
+		if p.ID, err = strconv.Atoi(record[0]); err != nil {
+			return nil, fmt.Errorf("line %d: invalid id: %w", line, err)
+		}
+		if p.ParticipationRate, err = strconv.ParseFloat(record[1], 64); err != nil {
+			return nil, fmt.Errorf("line %d: invalid participation_rate: %w", line, err)
+		}
+		if record[2] != "" {
+			if p.Budget, err = strconv.ParseFloat(record[2], 64); err != nil {
+				return nil, fmt.Errorf("line %d: invalid budget: %w", line, err)
+			}
+		}
+		for _, field := range strings.Fields(record[4]) {
+			w, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid weight: %w", line, err)
+			}
+			p.Weights = append(p.Weights, w)
+		}
+		profiles = append(profiles, p)
+	}
+}
+
+// ValidateProfiles checks that IDs are positive and unique and that every field
+// is within range
+func ValidateProfiles(profiles []models.BidderProfile) error {
+	seen := make(map[int]bool, len(profiles))
+	for i, p := range profiles {
+		switch {
+		case p.ID <= 0:
+			return fmt.Errorf("bidder %d: id must be positive, got %d", i, p.ID)
+		case seen[p.ID]:
+			return fmt.Errorf("bidder %d: duplicate id %d", i, p.ID)
+		case p.ParticipationRate < 0 || p.ParticipationRate > 1:
+			return fmt.Errorf("bidder %d (id %d): participation_rate must be within [0, 1], got %v", i, p.ID, p.ParticipationRate)
+		case p.Budget < 0:
+			return fmt.Errorf("bidder %d (id %d): budget must not be negative, got %v", i, p.ID, p.Budget)
+		case len(p.Weights) != 0 && len(p.Weights) != 20:
+			return fmt.Errorf("bidder %d (id %d): weights must list 20 values, got %d", i, p.ID, len(p.Weights))
+		case p.Aggressiveness < 0:
+			return fmt.Errorf("bidder %d (id %d): aggressiveness must not be negative, got %v", i, p.ID, p.Aggressiveness)
+		}
+		if p.Strategy != "" {
+			if _, err := ParseStrategy(p.Strategy); err != nil {
+				return fmt.Errorf("bidder %d (id %d): %w", i, p.ID, err)
+			}
+		}
+		for j, w := range p.Weights {
+			if w < 0 || w > 1 {
+				return fmt.Errorf("bidder %d (id %d): weight %d must be within [0, 1], got %v", i, p.ID, j, w)
+			}
+		}
+		for j, a := range p.VisibleAttributes {
+			if a < 0 || a >= 20 || slices.Contains(p.VisibleAttributes[:j], a) {
+				return fmt.Errorf("bidder %d (id %d): visible attribute %d must be a distinct index within [0, 19]", i, p.ID, a)
+			}
+		}
+		seen[p.ID] = true
+	}
+	return nil
+}
+
+// NewBidderFromProfile creates a bidder from a validated profile. A profile
+// without a seed gets one from src. Its streams come from source.
+func NewBidderFromProfile(p models.BidderProfile, src *rand.Rand, source rng.Factory) *Bidder {
+	seed := p.Seed
+	if seed == 0 {
+		seed = src.Int63()
+	}
+	b := &Bidder{
+		ID:                p.ID,
+		ParticipationRate: p.ParticipationRate,
+		Budget:            p.Budget,
+		Strategy:          Strategy(p.Strategy),
+		Aggressiveness:    p.Aggressiveness,
+		DeadlineAware:     p.DeadlineAware,
+		Seed:              seed,
+		source:            source,
+		rng:               source.New(seed),
+	}
+	// A generated bidder spends its first draws on its participation rate and
+	// weights; make them too so a recorded seed continues with the same stream.
+	// A profile without weights keeps the drawn ones.
+	b.rng.Float64()
+	b.Weights = b.drawWeights()
+	if b.Strategy == "" {
+		b.Strategy = StrategyDefault
+	}
+	if b.Aggressiveness == 0 {
+		b.Aggressiveness = 1
+	}
+	if len(p.Weights) == 20 {
+		var weights [20]float64
+		copy(weights[:], p.Weights)
+		b.Weights = &weights
+	}
+	if len(p.VisibleAttributes) > 0 {
+		var visible [20]bool
+		for _, i := range p.VisibleAttributes {
+			visible[i] = true
+		}
+		b.VisibleAttributes = &visible
+	}
+	return b
+}
@@ -0,0 +1,85 @@
+package bidder
+
+import (
+	"context"
+	"time"
+
+	"auction-simulator/pkg/models"
+)
+
+// Arrivals staggers when bidders show up to an auction. Each participating
+// bidder arrives at a uniform time within the auction window; with
+// probability DepartureRate it also leaves at a uniform time between its
+// arrival and the deadline, dropping its bid if that is not ready by then.
+type Arrivals struct {
+	DepartureRate float64
+}
+
+// presence is when a bidder is at one auction, as offsets from its start;
+// depart is zero for a bidder that stays until the close
+type presence struct {
+	arrive, depart time.Duration
+}
+
+// presence draws the bidder's arrival and departure over window from d, or
+// returns the zero presence (there from the start until the close) without
+// Arrivals
+func (b *Bidder) presence(window time.Duration, d *draws) presence {
+	if b.Arrivals == nil || window <= 0 {
+		return presence{}
+	}
+	p := presence{arrive: time.Duration(d.float64() * float64(window))}
+	if d.float64() < b.Arrivals.DepartureRate {
+		p.depart = p.arrive + time.Duration(d.float64()*float64(window-p.arrive))
+	}
+	return p
+}
+
+// until returns the earlier of the auction deadline and the bidder's
+// departure, the latest it can still place a bid
+func (p presence) until(auction *models.Auction, deadline time.Time) time.Time {
+	if departure := auction.StartTime.Add(p.depart); p.depart > 0 && departure.Before(deadline) {
+		return departure
+	}
+	return deadline
+}
+
+// departed reports whether the bidder had left auction by at
+func (p presence) departed(auction *models.Auction, at time.Time) bool {
+	return p.depart > 0 && at.After(auction.StartTime.Add(p.depart))
+}
+
+// arrive draws the bidder's arrival and departure over the auction's window
+// and records them on the auction when arrivals are staggered
+func (b *Bidder) arrive(auction *models.Auction, d *draws) presence {
+	p := b.presence(auction.Deadline().Sub(auction.StartTime), d)
+	if b.Arrivals != nil {
+		auction.AddPresence(models.Presence{
+			BidderID:   b.ID,
+			ArrivedMs:  msOf(p.arrive),
+			DepartedMs: msOf(p.depart),
+		})
+	}
+	return p
+}
+
+// wait sleeps until the bidder arrives, reporting false if ctx ends first
+func (p presence) wait(ctx context.Context, auction *models.Auction) bool {
+	wait := time.Until(auction.StartTime.Add(p.arrive))
+	if wait <= 0 {
+		return true
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// msOf converts d to fractional milliseconds
+func msOf(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
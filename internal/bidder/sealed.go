@@ -0,0 +1,124 @@
+package bidder
+
+import (
+	"context"
+	"encoding/hex"
+	"time"
+
+	"auction-simulator/pkg/models"
+)
+
+// revealProbability is the chance a bidder reveals a commitment; the rest
+// walk away from it, as a bidder might on deciding it overbid
+const revealProbability = 0.95
+
+// sealedBid is a committed bid kept private until the reveal phase, with the
+// auction's draws for revealing it
+type sealedBid struct {
+	bid   models.Bid
+	nonce string
+	draws *draws
+}
+
+// ConsiderCommit is the commit-phase counterpart of ConsiderBid: a
+// participating bidder commits to a sealed bid after its processing delay
+func (b *Bidder) ConsiderCommit(ctx context.Context, auction *models.Auction, commits chan<- models.Commitment, tracker *Tracker) {
+	b.notified.Add(1)
+	d := b.auctionDraws(auction.ID)
+	if d.float64() > b.ParticipationRate {
+		return // Not participating in this auction
+	}
+	b.participated.Add(1)
+
+	spawn(ctx, tracker, auction.ID, func() {
+		deadline := auction.Deadline()
+		time.Sleep(b.processingDelay(d, time.Until(deadline)))
+
+		now := time.Now()
+		if now.After(deadline) {
+			if tracker != nil {
+				tracker.late.Add(1)
+			}
+			return
+		}
+		commitment, ok := b.commit(auction, now, d)
+		if !ok {
+			return
+		}
+
+		select {
+		case commits <- commitment:
+		default:
+			// Buffer full, auction likely ended
+		}
+	})
+}
+
+// ConsiderReveal asks a bidder holding a commitment in auction to reveal it,
+// after a delay of 10-500ms. Reveals arriving after the reveal window closes
+// are ignored, leaving the commitment void.
+func (b *Bidder) ConsiderReveal(ctx context.Context, auction *models.Auction, reveals chan<- models.Reveal, tracker *Tracker) {
+	b.sealedMu.Lock()
+	sealed, committed := b.sealed[auction.ID]
+	b.sealedMu.Unlock()
+	if !committed {
+		return
+	}
+
+	spawn(ctx, tracker, auction.ID, func() {
+		time.Sleep(time.Duration(10+sealed.draws.intn(490)) * time.Millisecond)
+
+		reveal, ok := b.Reveal(auction.ID, time.Now())
+		if !ok {
+			return
+		}
+		select {
+		case reveals <- reveal:
+		default:
+		}
+	})
+}
+
+// commit computes the bidder's bid for auction from d, stamped with at, and
+// seals it: the bid and a random nonce are kept for Reveal, and only their
+// hash is returned. It returns false if the bid was abandoned.
+func (b *Bidder) commit(auction *models.Auction, at time.Time, d *draws) (models.Commitment, bool) {
+	bid, ok := b.computeBid(auction, at, d)
+	if !ok {
+		return models.Commitment{}, false
+	}
+
+	var nonce [16]byte
+	d.mu.Lock()
+	d.r.Read(nonce[:])
+	d.mu.Unlock()
+	sealed := sealedBid{bid: bid, nonce: hex.EncodeToString(nonce[:]), draws: d}
+
+	b.sealedMu.Lock()
+	if b.sealed == nil {
+		b.sealed = make(map[int]sealedBid)
+	}
+	b.sealed[auction.ID] = sealed
+	b.sealedMu.Unlock()
+
+	return models.Commitment{
+		BidderID:    b.ID,
+		Hash:        models.CommitHash(auction.ID, bid, sealed.nonce),
+		CommittedAt: at,
+	}, true
+}
+
+// Reveal discloses the bidder's commitment in an auction, stamped with at. It
+// returns false if the bidder has no commitment there or walks away from it.
+// Each commitment is revealed at most once.
+func (b *Bidder) Reveal(auctionID int, at time.Time) (models.Reveal, bool) {
+	b.sealedMu.Lock()
+	sealed, ok := b.sealed[auctionID]
+	delete(b.sealed, auctionID)
+	b.sealedMu.Unlock()
+
+	if !ok || sealed.draws.float64() >= revealProbability {
+		return models.Reveal{}, false
+	}
+	return models.Reveal{Bid: sealed.bid, Nonce: sealed.nonce, Timestamp: at}, true
+}
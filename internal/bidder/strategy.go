@@ -0,0 +1,140 @@
+package bidder
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// BidStrategy turns a bidder's valuation into its bid. Noise returns the
+// bidder's own valuation noise factor, drawn from rng; grouped bidders share
+// most of their noise through the group signal, which multiplies it. Calculate
+// then returns the factor the valuation is scaled by, given the bidder's
+// weighted score of each attribute it observes and drawing any randomness
+// from rng, so a strategy only shades.
+type BidStrategy interface {
+	Noise(grouped bool, rng *rand.Rand) float64
+	Calculate(attrs [20]float64, rng *rand.Rand) float64
+}
+
+// Noisy gives a strategy the usual valuation noise: ±20%, or ±5% for a
+// grouped bidder. Embed it to use it.
+type Noisy struct{}
+
+// Noise draws a factor in [0.8, 1.2), or [0.95, 1.05) if grouped
+func (Noisy) Noise(grouped bool, rng *rand.Rand) float64 {
+	// The ungrouped factor is drawn either way, so a seed draws what it
+	// always has
+	noise := 0.8 + rng.Float64()*0.4
+	if grouped {
+		noise = 0.95 + rng.Float64()*0.1
+	}
+	return noise
+}
+
+// WeightedRandom bids the bidder's noisy valuation unchanged
+type WeightedRandom struct{ Noisy }
+
+// Calculate returns 1 without drawing
+func (WeightedRandom) Calculate(attrs [20]float64, rng *rand.Rand) float64 {
+	return 1
+}
+
+// Aggressive bids at or above the valuation, 100-120% of it
+type Aggressive struct{ Noisy }
+
+// Calculate draws a factor in [1, 1.2)
+func (Aggressive) Calculate(attrs [20]float64, rng *rand.Rand) float64 {
+	return 1 + rng.Float64()*0.2
+}
+
+// Conservative shades bids well below the valuation, 60-90% of it
+type Conservative struct{ Noisy }
+
+// Calculate draws a factor in [0.6, 0.9)
+func (Conservative) Calculate(attrs [20]float64, rng *rand.Rand) float64 {
+	return 0.6 + rng.Float64()*0.3
+}
+
+// ValueBased bids exactly what the observed attributes are worth to the
+// bidder. It draws no valuation noise, so the same item always draws the same
+// bid.
+type ValueBased struct{}
+
+// Noise returns 1 without drawing
+func (ValueBased) Noise(grouped bool, rng *rand.Rand) float64 {
+	return 1
+}
+
+// Calculate returns 1 without drawing
+func (ValueBased) Calculate(attrs [20]float64, rng *rand.Rand) float64 {
+	return 1
+}
+
+var (
+	strategiesMu sync.RWMutex
+	// bidStrategies maps every known strategy name to its implementation
+	bidStrategies = map[Strategy]BidStrategy{
+		StrategyDefault:      WeightedRandom{},
+		StrategyAggressive:   Aggressive{},
+		StrategyConservative: Conservative{},
+		StrategyValueBased:   ValueBased{},
+	}
+)
+
+// RegisterStrategy makes s available under name, to bidder profiles, the
+// strategy mix and NewBidder alike. It fails if name is empty or taken.
+func RegisterStrategy(name Strategy, s BidStrategy) error {
+	if name == "" || s == nil {
+		return fmt.Errorf("a strategy needs a name and an implementation")
+	}
+	strategiesMu.Lock()
+	defer strategiesMu.Unlock()
+	if _, ok := bidStrategies[name]; ok {
+		return fmt.Errorf("strategy %q is already registered", name)
+	}
+	bidStrategies[name] = s
+	return nil
+}
+
+// ParseStrategy returns the strategy named name
+func ParseStrategy(name string) (Strategy, error) {
+	strategiesMu.RLock()
+	defer strategiesMu.RUnlock()
+	if _, ok := bidStrategies[Strategy(name)]; !ok {
+		names := make([]string, 0, len(bidStrategies))
+		for s := range bidStrategies {
+			names = append(names, fmt.Sprintf("%q", s))
+		}
+		slices.Sort(names)
+		return "", fmt.Errorf("unknown strategy %q (want one of %s)", name, strings.Join(names, ", "))
+	}
+	return Strategy(name), nil
+}
+
+// bidStrategy returns the implementation of the bidder's strategy
+func (b *Bidder) bidStrategy() BidStrategy {
+	strategiesMu.RLock()
+	defer strategiesMu.RUnlock()
+	if s, ok := bidStrategies[b.Strategy]; ok {
+		return s
+	}
+	return WeightedRandom{}
+}
+
+// noise draws the bidder's own valuation noise factor from d
+func (b *Bidder) noise(d *draws) float64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return b.bidStrategy().Noise(b.Group != nil, d.r)
+}
+
+// shade draws the bidder's strategy factor for observed attribute scores
+// from d
+func (b *Bidder) shade(d *draws, observed [20]float64) float64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return b.bidStrategy().Calculate(observed, d.r)
+}
@@ -0,0 +1,132 @@
+package bidder
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"auction-simulator/pkg/models"
+)
+
+// strategyRanges is the range [lo, hi) of each strategy's shading factor;
+// lo == hi means the factor is exactly lo
+var strategyRanges = []struct {
+	strategy Strategy
+	lo, hi   float64
+}{
+	{StrategyDefault, 1, 1},
+	{StrategyAggressive, 1, 1.2},
+	{StrategyConservative, 0.6, 0.9},
+	{StrategyValueBased, 1, 1},
+}
+
+// inRange reports whether x lies in [lo, hi), or equals lo when lo == hi
+func inRange(x, lo, hi float64) bool {
+	if lo == hi {
+		return x == lo
+	}
+	return x >= lo && x < hi
+}
+
+// TestStrategyRanges draws each strategy's factor many times; every factor
+// must fall in its range, and the random strategies must spread across it
+func TestStrategyRanges(t *testing.T) {
+	var attrs [20]float64
+	for i := range attrs {
+		attrs[i] = float64(i) / 4
+	}
+	for _, tt := range strategyRanges {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			strategy := bidStrategies[tt.strategy]
+			r := rand.New(rand.NewSource(1))
+			lowest, highest := tt.hi, tt.lo
+			for range 10000 {
+				f := strategy.Calculate(attrs, r)
+				if !inRange(f, tt.lo, tt.hi) {
+					t.Fatalf("factor %v outside [%v, %v)", f, tt.lo, tt.hi)
+				}
+				lowest, highest = min(lowest, f), max(highest, f)
+			}
+			if spread := tt.hi - tt.lo; spread > 0 && (lowest > tt.lo+spread/20 || highest < tt.hi-spread/20) {
+				t.Errorf("factors span [%v, %v], want most of [%v, %v)", lowest, highest, tt.lo, tt.hi)
+			}
+		})
+	}
+}
+
+// TestStrategyBids checks each strategy through calculateBid: the bid is the
+// valuation scaled by a factor in the strategy's range. The bidder sees every
+// attribute, so its observed score and valuation agree.
+func TestStrategyBids(t *testing.T) {
+	for _, tt := range strategyRanges {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			b := NewBidderFromSeed(1, 42, nil)
+			b.Strategy = tt.strategy
+			for id := 1; id <= 200; id++ {
+				auction := models.NewAuction(id, time.Second, 0)
+				for i := range auction.Attributes {
+					auction.Attributes[i] = float64((id+i)%10) + 0.5
+				}
+				amount, valuation := b.calculateBid(auction, b.auctionDraws(id))
+				// The ratio is only as exact as the float arithmetic behind it
+				if f := amount / valuation; f < tt.lo-1e-9 || f > tt.hi+1e-9 {
+					t.Fatalf("auction %d: bid %v is %v of valuation %v, want [%v, %v]", id, amount, f, valuation, tt.lo, tt.hi)
+				}
+			}
+		})
+	}
+}
+
+func TestParseStrategy(t *testing.T) {
+	for _, tt := range strategyRanges {
+		if got, err := ParseStrategy(string(tt.strategy)); err != nil || got != tt.strategy {
+			t.Errorf("ParseStrategy(%q) = %q, %v", tt.strategy, got, err)
+		}
+	}
+	if _, err := ParseStrategy("reckless"); err == nil {
+		t.Error("unknown strategy was accepted")
+	}
+}
+
+// halfStrategy is a caller's strategy: half the valuation, without noise
+type halfStrategy struct{}
+
+func (halfStrategy) Noise(grouped bool, rng *rand.Rand) float64 { return 1 }
+
+func (halfStrategy) Calculate(attrs [20]float64, rng *rand.Rand) float64 { return 0.5 }
+
+// TestRegisterStrategy registers a strategy and bids with it; its noise and
+// shading must both come from it, with no change to how bids are appraised
+func TestRegisterStrategy(t *testing.T) {
+	const name Strategy = "half"
+	if err := RegisterStrategy(name, halfStrategy{}); err != nil {
+		t.Fatalf("RegisterStrategy: %v", err)
+	}
+	if err := RegisterStrategy(name, halfStrategy{}); err == nil {
+		t.Error("a second strategy named half was accepted")
+	}
+	if err := RegisterStrategy(StrategyDefault, halfStrategy{}); err == nil {
+		t.Error("a built-in strategy was replaced")
+	}
+	if got, err := ParseStrategy(string(name)); err != nil || got != name {
+		t.Fatalf("ParseStrategy(%q) = %q, %v", name, got, err)
+	}
+
+	half := NewBidderFromSeed(1, 42, nil)
+	half.Strategy = name
+	valueBased := NewBidderFromSeed(1, 42, nil)
+	valueBased.Strategy = StrategyValueBased
+	auction := models.NewAuction(1, time.Second, 0)
+	for i := range auction.Attributes {
+		auction.Attributes[i] = float64(i%10) + 0.5
+	}
+	amount, valuation := half.calculateBid(auction, half.auctionDraws(1))
+	_, want := valueBased.calculateBid(auction, valueBased.auctionDraws(1))
+	if valuation != want {
+		t.Errorf("valuation %v, want the noiseless %v", valuation, want)
+	}
+	if f := amount / valuation; math.Abs(f-0.5) > 1e-9 {
+		t.Errorf("bid %v is %v of valuation %v, want 0.5", amount, f, valuation)
+	}
+}
@@ -0,0 +1,147 @@
+package faults
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+)
+
+// ErrInjected is wrapped by every error produced by an Injector
+var ErrInjected = errors.New("injected fault")
+
+// Site identifies an operation that can have faults injected
+type Site string
+
+const (
+	// SiteAuctionRun fails an auction before it starts
+	SiteAuctionRun Site = "auction_run"
+	// SiteBidSubmit drops a bid as it reaches the collector
+	SiteBidSubmit Site = "bid_submit"
+	// SiteOutputWrite fails an output file write
+	SiteOutputWrite Site = "output_write"
+)
+
+// operation identifies one keyed operation at a site
+type operation struct {
+	site Site
+	key  uint64
+}
+
+// Injector makes operations fail with a fixed probability for resilience testing.
+// A nil Injector never injects faults, so call sites need no guards.
+type Injector struct {
+	rate   float64
+	seed   int64
+	seen   map[operation]int
+	counts map[Site]int
+	mu     sync.Mutex
+}
+
+// NewInjector creates an injector failing operations with the given probability.
+// Its draws come from seed alone, so enabling it does not perturb the
+// simulation's random sequence.
+func NewInjector(rate float64, seed int64) *Injector {
+	return &Injector{
+		rate:   rate,
+		seed:   seed,
+		seen:   make(map[operation]int),
+		counts: make(map[Site]int),
+	}
+}
+
+// Key identifies an operation by IDs, such as an auction's and a bidder's
+func Key(ids ...int) uint64 {
+	var key uint64
+	for _, id := range ids {
+		key = mix(key ^ uint64(id))
+	}
+	return key
+}
+
+// NameKey identifies an operation by name, such as an output file's
+func NameKey(name string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return h.Sum64()
+}
+
+// Fail returns a non-nil error if a fault should be injected into the
+// operation with key at site. Whether it fails depends only on the seed, the
+// site, the key and how many times the key was seen before, not on the order
+// concurrent operations reach the injector in.
+func (in *Injector) Fail(site Site, key uint64) error {
+	if in == nil {
+		return nil
+	}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	op := operation{site, key}
+	n := in.seen[op]
+	in.seen[op]++
+	if in.draw(op, n) >= in.rate {
+		return nil
+	}
+	in.counts[site]++
+	return fmt.Errorf("%s: %w", site, ErrInjected)
+}
+
+// draw returns the uniform draw in [0, 1) for the nth time op is seen
+func (in *Injector) draw(op operation, n int) float64 {
+	var b [24]byte
+	binary.LittleEndian.PutUint64(b[0:], uint64(in.seed))
+	binary.LittleEndian.PutUint64(b[8:], op.key)
+	binary.LittleEndian.PutUint64(b[16:], uint64(n))
+	h := fnv.New64a()
+	h.Write([]byte(op.site))
+	h.Write(b[:])
+	return float64(mix(h.Sum64())>>11) / (1 << 53)
+}
+
+// mix is the SplitMix64 finalizer, spreading every input bit over the output
+func mix(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ x>>30) * 0xbf58476d1ce4e5b9
+	x = (x ^ x>>27) * 0x94d049bb133111eb
+	return x ^ x>>31
+}
+
+// Counts returns the number of faults injected so far at each site
+func (in *Injector) Counts() map[string]int {
+	if in == nil {
+		return nil
+	}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	counts := make(map[string]int, len(in.counts))
+	for site, n := range in.counts {
+		counts[string(site)] = n
+	}
+	return counts
+}
+
+// Total returns the number of faults injected across all sites
+func (in *Injector) Total() int {
+	total := 0
+	for _, n := range in.Counts() {
+		total += n
+	}
+	return total
+}
+
+// Sites returns the sites with injected faults in a stable order
+func (in *Injector) Sites() []string {
+	counts := in.Counts()
+	sites := make([]string, 0, len(counts))
+	for site := range counts {
+		sites = append(sites, site)
+	}
+	sort.Strings(sites)
+	return sites
+}
@@ -0,0 +1,120 @@
+package faults
+
+import (
+	"errors"
+	"maps"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+// failures returns which of the bids of auctions×bidders an injector with
+// seed fails, drawing them in the order given
+func failures(seed int64, order [][2]int) map[[2]int]bool {
+	in := NewInjector(0.3, seed)
+	failed := make(map[[2]int]bool)
+	for _, op := range order {
+		if err := in.Fail(SiteBidSubmit, Key(op[0], op[1])); err != nil {
+			failed[op] = true
+		}
+	}
+	return failed
+}
+
+// TestDeterministicDraws draws the same operations in different orders; each
+// operation must fail or succeed the same way in every order, so concurrent
+// auctions see the same faults whatever their scheduling
+func TestDeterministicDraws(t *testing.T) {
+	var ops [][2]int
+	for auction := 1; auction <= 20; auction++ {
+		for bidder := 1; bidder <= 50; bidder++ {
+			ops = append(ops, [2]int{auction, bidder})
+		}
+	}
+	want := failures(7, ops)
+	if n := len(want); n < 250 || n > 350 {
+		t.Fatalf("%d of %d operations failed at rate 0.3", n, len(ops))
+	}
+
+	shuffled := append([][2]int(nil), ops...)
+	rand.New(rand.NewSource(1)).Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+	if got := failures(7, shuffled); !maps.Equal(got, want) {
+		t.Errorf("shuffled order failed %d operations, want the same %d", len(got), len(want))
+	}
+	if got := failures(8, ops); maps.Equal(got, want) {
+		t.Error("a different seed failed the same operations")
+	}
+
+	// Concurrent callers, one per auction, must also agree
+	in := NewInjector(0.3, 7)
+	var mu sync.Mutex
+	got := make(map[[2]int]bool)
+	var wg sync.WaitGroup
+	for auction := 1; auction <= 20; auction++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for bidder := 1; bidder <= 50; bidder++ {
+				if in.Fail(SiteBidSubmit, Key(auction, bidder)) != nil {
+					mu.Lock()
+					got[[2]int{auction, bidder}] = true
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if !maps.Equal(got, want) {
+		t.Errorf("concurrent callers failed %d operations, want the same %d", len(got), len(want))
+	}
+	if n := in.Counts()[string(SiteBidSubmit)]; n != len(want) {
+		t.Errorf("counted %d faults, want %d", n, len(want))
+	}
+}
+
+// TestRepeatedKey draws one key many times at one site: repeats get fresh
+// draws, and the same key at another site draws independently
+func TestRepeatedKey(t *testing.T) {
+	in := NewInjector(0.5, 1)
+	failed := 0
+	for range 1000 {
+		if in.Fail(SiteOutputWrite, NameKey("summary.json")) != nil {
+			failed++
+		}
+	}
+	if failed < 400 || failed > 600 {
+		t.Errorf("%d of 1000 repeated writes failed at rate 0.5", failed)
+	}
+
+	a, b := NewInjector(0.5, 1), NewInjector(0.5, 1)
+	same := 0
+	for i := range 1000 {
+		if (a.Fail(SiteAuctionRun, Key(i)) != nil) == (b.Fail(SiteBidSubmit, Key(i)) != nil) {
+			same++
+		}
+	}
+	if same > 600 {
+		t.Errorf("two sites agreed on %d of 1000 draws for the same keys", same)
+	}
+}
+
+func TestNilInjector(t *testing.T) {
+	var in *Injector
+	if err := in.Fail(SiteAuctionRun, Key(1)); err != nil {
+		t.Errorf("nil injector failed an operation: %v", err)
+	}
+	if in.Total() != 0 || len(in.Sites()) != 0 {
+		t.Error("nil injector reports faults")
+	}
+}
+
+func TestInjectedError(t *testing.T) {
+	in := NewInjector(1, 1)
+	err := in.Fail(SiteAuctionRun, Key(3))
+	if !errors.Is(err, ErrInjected) {
+		t.Fatalf("Fail = %v, want an error wrapping ErrInjected", err)
+	}
+	if in.Total() != 1 || in.Sites()[0] != string(SiteAuctionRun) {
+		t.Errorf("counts %v, want one fault at %s", in.Counts(), SiteAuctionRun)
+	}
+}
@@ -0,0 +1,202 @@
+package grpcstream
+
+import (
+	"errors"
+	"log"
+	"net"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"auction-simulator/pkg/models"
+	"auction-simulator/proto/auctionv1"
+)
+
+// SubscriberBuffer is how many results a stream may fall behind by before it
+// is cut off, so a slow consumer never stalls the simulation
+const SubscriberBuffer = 64
+
+// subscriber is one open StreamAuctions call
+type subscriber struct {
+	auctions chan *auctionv1.Auction
+	// err is the status to end the stream with once auctions is closed; nil
+	// ends it successfully
+	err error
+}
+
+// Server serves the AuctionStream gRPC service generated from
+// proto/auction.proto. Each stream first receives every auction completed so
+// far, then each new auction as it is published, and ends when Finish is
+// called.
+type Server struct {
+	auctionv1.UnimplementedAuctionStreamServer
+
+	addr       string
+	grpcServer *grpc.Server
+
+	mu          sync.Mutex
+	history     []*auctionv1.Auction
+	subscribers map[*subscriber]bool
+	finished    bool
+}
+
+// NewServer creates a gRPC stream server listening on addr
+func NewServer(addr string) *Server {
+	s := &Server{addr: addr, subscribers: make(map[*subscriber]bool)}
+	s.grpcServer = grpc.NewServer()
+	auctionv1.RegisterAuctionStreamServer(s.grpcServer, s)
+	return s
+}
+
+// Start begins serving requests in the background
+func (s *Server) Start() {
+	go func() {
+		ln, err := net.Listen("tcp", s.addr)
+		if err != nil {
+			log.Printf("gRPC stream server error: %v", err)
+			return
+		}
+		s.serve(ln)
+	}()
+}
+
+// serve serves requests on ln until the server is stopped
+func (s *Server) serve(ln net.Listener) {
+	if err := s.grpcServer.Serve(ln); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		log.Printf("gRPC stream server error: %v", err)
+	}
+}
+
+// Publish sends a finished auction to every open stream. It never blocks: a
+// stream whose buffer is full is ended with RESOURCE_EXHAUSTED.
+func (s *Server) Publish(auction *models.Auction) {
+	msg := toProto(auction)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.history = append(s.history, msg)
+	for sub := range s.subscribers {
+		select {
+		case sub.auctions <- msg:
+		default:
+			sub.err = status.Error(codes.ResourceExhausted,
+				"consumer fell more than "+strconv.Itoa(SubscriberBuffer)+" auctions behind")
+			s.end(sub)
+		}
+	}
+}
+
+// Finish ends every open stream successfully; later calls receive the full
+// history and end immediately
+func (s *Server) Finish() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.finished = true
+	for sub := range s.subscribers {
+		s.end(sub)
+	}
+}
+
+// end closes a subscriber's stream. Must be called with s.mu held.
+func (s *Server) end(sub *subscriber) {
+	delete(s.subscribers, sub)
+	close(sub.auctions)
+}
+
+// Stop shuts the server down, waiting briefly for streams to flush
+func (s *Server) Stop() {
+	stopped := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		s.grpcServer.Stop()
+	}
+}
+
+// StreamAuctions implements the AuctionStream.StreamAuctions call
+func (s *Server) StreamAuctions(_ *auctionv1.StreamAuctionsRequest, stream grpc.ServerStreamingServer[auctionv1.Auction]) error {
+	// Take the backlog and subscribe atomically so no auction is missed or repeated
+	sub := &subscriber{auctions: make(chan *auctionv1.Auction, SubscriberBuffer)}
+	s.mu.Lock()
+	backlog := s.history
+	if s.finished {
+		close(sub.auctions)
+	} else {
+		s.subscribers[sub] = true
+	}
+	s.mu.Unlock()
+	defer s.unsubscribe(sub)
+
+	for _, msg := range backlog {
+		if err := stream.Send(msg); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case msg, ok := <-sub.auctions:
+			if !ok {
+				return sub.err
+			}
+			if err := stream.Send(msg); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		}
+	}
+}
+
+// unsubscribe removes a stream that ended before the server closed it
+func (s *Server) unsubscribe(sub *subscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.subscribers[sub] {
+		s.end(sub)
+	}
+}
+
+// toProto converts a finished auction to its auction.v1.Auction message
+func toProto(a *models.Auction) *auctionv1.Auction {
+	msg := &auctionv1.Auction{
+		AuctionId:         int32(a.ID),
+		Attributes:        slices.Clone(a.Attributes[:]),
+		TimeoutMs:         a.TimeoutMs,
+		StartTimeUnixNano: a.StartTime.UnixNano(),
+		EndTimeUnixNano:   a.EndTime.UnixNano(),
+		Bids:              make([]*auctionv1.Bid, len(a.Bids)),
+		TotalBids:         int32(a.TotalBids),
+		Status:            string(a.Status),
+		TotalPaid:         a.TotalPaid,
+	}
+	for i := range a.Bids {
+		msg.Bids[i] = bidToProto(&a.Bids[i])
+	}
+	if a.Winner != nil {
+		msg.Winner = bidToProto(a.Winner)
+	}
+	return msg
+}
+
+// bidToProto converts a bid to its auction.v1.Bid message
+func bidToProto(bid *models.Bid) *auctionv1.Bid {
+	return &auctionv1.Bid{
+		BidderId:          int32(bid.BidderID),
+		Amount:            bid.Amount,
+		AmountCents:       bid.Cents,
+		GroupId:           int32(bid.GroupID),
+		TimestampUnixNano: bid.Timestamp.UnixNano(),
+	}
+}
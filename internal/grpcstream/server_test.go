@@ -0,0 +1,102 @@
+package grpcstream
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"auction-simulator/pkg/models"
+	"auction-simulator/proto/auctionv1"
+)
+
+// testAuction returns a finished auction with one bid per amount, won by the
+// last
+func testAuction(id int, amounts ...float64) *models.Auction {
+	start := time.Unix(1700000000, 0)
+	a := models.NewAuction(id, 200*time.Millisecond, 0)
+	a.StartTime, a.EndTime = start, start.Add(200*time.Millisecond)
+	a.Attributes[0] = 2.5
+	for i, amount := range amounts {
+		a.Bids = append(a.Bids, models.Bid{BidderID: i + 1, Amount: amount, Timestamp: start.Add(time.Duration(i+1) * time.Millisecond)})
+	}
+	a.TotalBids = len(a.Bids)
+	a.Winner = &a.Bids[len(a.Bids)-1]
+	a.Status = models.StatusCompleted
+	return a
+}
+
+// TestStreamAuctions is an example client: it calls StreamAuctions through
+// the generated client stub and receives each Auction. It must receive the
+// auctions published before it connected, then those published after, and
+// end with an OK status once the run finishes.
+func TestStreamAuctions(t *testing.T) {
+	s := NewServer("")
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	go s.serve(ln)
+	defer s.Stop()
+
+	s.Publish(testAuction(1, 100, 250.5))
+	s.Publish(testAuction(2, 300))
+
+	conn, err := grpc.NewClient(ln.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stream, err := auctionv1.NewAuctionStreamClient(conn).StreamAuctions(ctx, &auctionv1.StreamAuctionsRequest{})
+	if err != nil {
+		t.Fatalf("StreamAuctions: %v", err)
+	}
+
+	want := []struct {
+		id, bids, winner int32
+		amount           float64
+	}{
+		{1, 2, 2, 250.5},
+		{2, 1, 1, 300},
+		{3, 3, 3, 140},
+	}
+	for i, w := range want {
+		auction, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("receiving auction %d: %v", w.id, err)
+		}
+		if i == 1 {
+			// Publish the rest once the stream has caught up, then end the run
+			s.Publish(testAuction(3, 120, 130, 140))
+			s.Finish()
+		}
+
+		if got := auction.GetAuctionId(); got != w.id {
+			t.Fatalf("got auction %d, want %d", got, w.id)
+		}
+		if got := auction.GetTotalBids(); got != w.bids || int32(len(auction.GetBids())) != w.bids {
+			t.Errorf("auction %d: total_bids %d with %d bids, want %d", w.id, got, len(auction.GetBids()), w.bids)
+		}
+		if got := auction.GetStatus(); got != string(models.StatusCompleted) {
+			t.Errorf("auction %d: status %q, want %q", w.id, got, models.StatusCompleted)
+		}
+		if attrs := auction.GetAttributes(); len(attrs) != 20 || attrs[0] != 2.5 {
+			t.Errorf("auction %d: attributes %v, want 20 starting with 2.5", w.id, attrs)
+		}
+		winner := auction.GetWinner()
+		if winner.GetBidderId() != w.winner || winner.GetAmount() != w.amount {
+			t.Errorf("auction %d: winner %d at %v, want %d at %v", w.id, winner.GetBidderId(), winner.GetAmount(), w.winner, w.amount)
+		}
+	}
+
+	if _, err := stream.Recv(); !errors.Is(err, io.EOF) {
+		t.Fatalf("after the last auction: got %v, want the stream to end OK", err)
+	}
+}
@@ -0,0 +1,306 @@
+package kafka
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"auction-simulator/pkg/models"
+)
+
+// Defaults for the producer
+const (
+	// QueueSize is how many results may wait to be produced before Send
+	// blocks, pushing back on the simulation
+	QueueSize = 256
+	// BatchSize is the most results produced in one request per partition
+	BatchSize = 100
+	// Linger is how long the producer waits to fill a batch
+	Linger = 50 * time.Millisecond
+
+	attempts = 3
+	backoff  = 200 * time.Millisecond
+	timeout  = 5 * time.Second
+	clientID = "auction-simulator"
+)
+
+// Producer publishes finished auctions to a Kafka topic as JSON messages keyed
+// by auction ID. It speaks the Kafka protocol directly: messages are batched
+// per partition, chosen by an FNV-1a hash of the key, and written with acks
+// from the partition leader. Failed requests are retried after refreshing
+// metadata; results still undelivered are logged and counted, never returned.
+type Producer struct {
+	brokers []string
+	topic   string
+
+	queue chan message
+	done  chan struct{}
+
+	// Only the run goroutine touches the connection and metadata state
+	conns         map[int32]net.Conn
+	addrs         map[int32]string
+	leaders       []int32 // leader broker ID by partition
+	correlationID int32
+
+	delivered atomic.Int64
+	failed    atomic.Int64
+	retries   atomic.Int64
+	blocked   atomic.Int64
+}
+
+// NewProducer creates a producer for topic on a comma-separated list of
+// bootstrap brokers and starts it in the background
+func NewProducer(brokers, topic string) *Producer {
+	p := &Producer{
+		topic: topic,
+		queue: make(chan message, QueueSize),
+		done:  make(chan struct{}),
+		conns: make(map[int32]net.Conn),
+		addrs: make(map[int32]string),
+	}
+	for _, b := range strings.Split(brokers, ",") {
+		if b = strings.TrimSpace(b); b != "" {
+			p.brokers = append(p.brokers, b)
+		}
+	}
+	go p.run()
+	return p
+}
+
+// Send queues the auction for publishing. It blocks while the queue is full,
+// which is counted as a backpressure event.
+func (p *Producer) Send(auction *models.Auction) {
+	value, err := json.Marshal(auction)
+	if err != nil {
+		log.Printf("Kafka: failed to encode auction %d: %v", auction.ID, err)
+		p.failed.Add(1)
+		return
+	}
+	m := message{key: []byte(strconv.Itoa(auction.ID)), value: value, timestamp: time.Now()}
+
+	select {
+	case p.queue <- m:
+	default:
+		p.blocked.Add(1)
+		p.queue <- m
+	}
+}
+
+// Close flushes every queued result, closes the broker connections and
+// reports the outcome. Send must not be called afterwards.
+func (p *Producer) Close() models.KafkaReport {
+	close(p.queue)
+	<-p.done
+	for _, c := range p.conns {
+		c.Close()
+	}
+	return models.KafkaReport{
+		Delivered: int(p.delivered.Load()),
+		Failed:    int(p.failed.Load()),
+		Retries:   int(p.retries.Load()),
+		Blocked:   int(p.blocked.Load()),
+	}
+}
+
+// run batches queued messages and produces them until the queue is closed
+func (p *Producer) run() {
+	defer close(p.done)
+
+	for first := range p.queue {
+		batch := []message{first}
+		linger := time.NewTimer(Linger)
+	fill:
+		for len(batch) < BatchSize {
+			select {
+			case m, ok := <-p.queue:
+				if !ok {
+					break fill
+				}
+				batch = append(batch, m)
+			case <-linger.C:
+				break fill
+			}
+		}
+		linger.Stop()
+		p.flush(batch)
+	}
+}
+
+// flush produces a batch, grouped by partition
+func (p *Producer) flush(batch []message) {
+	for attempt := 0; p.leaders == nil; attempt++ {
+		err := p.refreshMetadata()
+		if err == nil {
+			break
+		}
+		if attempt == attempts-1 {
+			log.Printf("Kafka: giving up on %d results: %v", len(batch), err)
+			p.failed.Add(int64(len(batch)))
+			return
+		}
+		p.retries.Add(1)
+		time.Sleep(backoff << attempt)
+	}
+
+	byPartition := make(map[int32][]message)
+	for _, m := range batch {
+		h := fnv.New32a()
+		h.Write(m.key)
+		partition := int32(h.Sum32() % uint32(len(p.leaders)))
+		byPartition[partition] = append(byPartition[partition], m)
+	}
+	for partition, messages := range byPartition {
+		if err := p.produce(partition, messages); err != nil {
+			log.Printf("Kafka: giving up on %d results for partition %d: %v", len(messages), partition, err)
+			p.failed.Add(int64(len(messages)))
+			continue
+		}
+		p.delivered.Add(int64(len(messages)))
+	}
+}
+
+// produce writes messages to one partition, retrying with exponential backoff
+// and fresh metadata after failures
+func (p *Producer) produce(partition int32, messages []message) error {
+	body := produceRequest(p.topic, partition, 1, timeout, recordBatch(messages))
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			p.retries.Add(1)
+			time.Sleep(backoff << (attempt - 1))
+			if err := p.refreshMetadata(); err != nil {
+				continue
+			}
+		}
+		if int(partition) >= len(p.leaders) {
+			err = fmt.Errorf("partition %d no longer exists", partition)
+			continue
+		}
+
+		var resp []byte
+		leader := p.leaders[partition]
+		resp, err = p.roundTrip(leader, apiProduce, produceVersion, body)
+		if err != nil {
+			p.drop(leader)
+			continue
+		}
+		var code int16
+		if code, err = parseProduce(resp); err != nil {
+			continue
+		}
+		if code == errNone {
+			return nil
+		}
+		err = fmt.Errorf("broker error code %d", code)
+		if !retriable(code) {
+			return err
+		}
+	}
+	return fmt.Errorf("%d attempts failed, last error: %w", attempts, err)
+}
+
+// refreshMetadata looks up the topic's partition leaders from the first
+// bootstrap broker that answers
+func (p *Producer) refreshMetadata() error {
+	var err error
+	for _, addr := range p.brokers {
+		var c net.Conn
+		if c, err = net.DialTimeout("tcp", addr, timeout); err != nil {
+			continue
+		}
+		var resp []byte
+		resp, err = p.exchange(c, apiMetadata, metadataVersion, metadataRequest(p.topic))
+		c.Close()
+		if err != nil {
+			continue
+		}
+
+		brokers, partitions, code, perr := parseMetadata(resp, p.topic)
+		if perr != nil {
+			err = perr
+			continue
+		}
+		if code != errNone || len(partitions) == 0 {
+			err = fmt.Errorf("topic %q unavailable (error code %d)", p.topic, code)
+			continue
+		}
+
+		for _, b := range brokers {
+			if p.addrs[b.id] != b.addr {
+				p.drop(b.id)
+				p.addrs[b.id] = b.addr
+			}
+		}
+		p.leaders = make([]int32, len(partitions))
+		for _, part := range partitions {
+			if int(part.id) < len(p.leaders) {
+				p.leaders[part.id] = part.leader
+			}
+		}
+		return nil
+	}
+	if err == nil {
+		err = fmt.Errorf("no brokers configured")
+	}
+	return fmt.Errorf("metadata request failed: %w", err)
+}
+
+// roundTrip sends a request to a broker, connecting if needed
+func (p *Producer) roundTrip(brokerID int32, apiKey, version int16, body []byte) ([]byte, error) {
+	c, ok := p.conns[brokerID]
+	if !ok {
+		addr, known := p.addrs[brokerID]
+		if !known {
+			return nil, fmt.Errorf("unknown broker %d", brokerID)
+		}
+		var err error
+		if c, err = net.DialTimeout("tcp", addr, timeout); err != nil {
+			return nil, err
+		}
+		p.conns[brokerID] = c
+	}
+	return p.exchange(c, apiKey, version, body)
+}
+
+// exchange writes one request on c and reads its response body
+func (p *Producer) exchange(c net.Conn, apiKey, version int16, body []byte) ([]byte, error) {
+	p.correlationID++
+	c.SetDeadline(time.Now().Add(timeout))
+	if _, err := c.Write(request(apiKey, version, p.correlationID, clientID, body)); err != nil {
+		return nil, err
+	}
+
+	var size [4]byte
+	if _, err := io.ReadFull(c, size[:]); err != nil {
+		return nil, err
+	}
+	resp := make([]byte, binary.BigEndian.Uint32(size[:]))
+	if _, err := io.ReadFull(c, resp); err != nil {
+		return nil, err
+	}
+	if len(resp) < 4 || int32(binary.BigEndian.Uint32(resp)) != p.correlationID {
+		return nil, fmt.Errorf("response does not match request %d", p.correlationID)
+	}
+	return resp[4:], nil
+}
+
+// drop closes the connection to a broker so the next request reconnects
+func (p *Producer) drop(brokerID int32) {
+	if c, ok := p.conns[brokerID]; ok {
+		c.Close()
+		delete(p.conns, brokerID)
+	}
+}
+
+func joinHostPort(host string, port int32) string {
+	return net.JoinHostPort(host, strconv.Itoa(int(port)))
+}
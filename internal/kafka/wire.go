@@ -0,0 +1,289 @@
+package kafka
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"time"
+)
+
+// Kafka API keys and the versions used. Produce v3 is the oldest version that
+// current brokers accept and the first to carry v2 record batches; neither
+// request uses the flexible encoding introduced later.
+const (
+	apiProduce  = 0
+	apiMetadata = 3
+
+	produceVersion  = 3
+	metadataVersion = 4
+)
+
+// Kafka error codes the producer reacts to; others are reported as-is
+const (
+	errNone                    = 0
+	errUnknownTopicOrPartition = 3
+	errLeaderNotAvailable      = 5
+	errNotLeaderForPartition   = 6
+	errRequestTimedOut         = 7
+)
+
+// retriable reports whether a produce error code clears up once metadata is
+// refreshed or the broker catches up
+func retriable(code int16) bool {
+	switch code {
+	case errUnknownTopicOrPartition, errLeaderNotAvailable, errNotLeaderForPartition, errRequestTimedOut:
+		return true
+	}
+	return false
+}
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// errShortResponse is returned when a response ends before a field it declares
+var errShortResponse = errors.New("kafka: truncated response")
+
+// encoder appends Kafka's big-endian primitive types
+type encoder struct {
+	b []byte
+}
+
+func (e *encoder) int8(v int8)   { e.b = append(e.b, byte(v)) }
+func (e *encoder) int16(v int16) { e.b = binary.BigEndian.AppendUint16(e.b, uint16(v)) }
+func (e *encoder) int32(v int32) { e.b = binary.BigEndian.AppendUint32(e.b, uint32(v)) }
+func (e *encoder) int64(v int64) { e.b = binary.BigEndian.AppendUint64(e.b, uint64(v)) }
+
+func (e *encoder) string(s string) {
+	e.int16(int16(len(s)))
+	e.b = append(e.b, s...)
+}
+
+// nullString encodes a nullable string as null, its only use here
+func (e *encoder) nullString() { e.int16(-1) }
+
+func (e *encoder) bytes(v []byte) {
+	e.int32(int32(len(v)))
+	e.b = append(e.b, v...)
+}
+
+// varint and varbytes use the zig-zag encoding of record fields
+func (e *encoder) varint(v int64) { e.b = binary.AppendVarint(e.b, v) }
+
+func (e *encoder) varbytes(v []byte) {
+	e.varint(int64(len(v)))
+	e.b = append(e.b, v...)
+}
+
+// decoder reads Kafka's primitive types, remembering the first error
+type decoder struct {
+	b   []byte
+	err error
+}
+
+func (d *decoder) take(n int) []byte {
+	if d.err != nil || n < 0 || len(d.b) < n {
+		d.err = errShortResponse
+		return nil
+	}
+	v := d.b[:n]
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *decoder) int8() int8 {
+	if v := d.take(1); v != nil {
+		return int8(v[0])
+	}
+	return 0
+}
+
+func (d *decoder) int16() int16 {
+	if v := d.take(2); v != nil {
+		return int16(binary.BigEndian.Uint16(v))
+	}
+	return 0
+}
+
+func (d *decoder) int32() int32 {
+	if v := d.take(4); v != nil {
+		return int32(binary.BigEndian.Uint32(v))
+	}
+	return 0
+}
+
+func (d *decoder) int64() int64 {
+	if v := d.take(8); v != nil {
+		return int64(binary.BigEndian.Uint64(v))
+	}
+	return 0
+}
+
+// string reads a string or nullable string; null reads as ""
+func (d *decoder) string() string {
+	n := d.int16()
+	if n < 0 {
+		return ""
+	}
+	return string(d.take(int(n)))
+}
+
+// arrayLen reads an array length, treating null as empty
+func (d *decoder) arrayLen() int {
+	return max(int(d.int32()), 0)
+}
+
+// message is one record to produce
+type message struct {
+	key, value []byte
+	timestamp  time.Time
+}
+
+// recordBatch encodes messages as a v2 record batch without compression
+func recordBatch(messages []message) []byte {
+	base := messages[0].timestamp.UnixMilli()
+	maxTimestamp := base
+
+	var records encoder
+	for i, m := range messages {
+		ts := m.timestamp.UnixMilli()
+		maxTimestamp = max(maxTimestamp, ts)
+
+		var r encoder
+		r.int8(0) // attributes
+		r.varint(ts - base)
+		r.varint(int64(i)) // offset delta
+		r.varbytes(m.key)
+		r.varbytes(m.value)
+		r.varint(0) // headers
+
+		records.varint(int64(len(r.b)))
+		records.b = append(records.b, r.b...)
+	}
+
+	// The CRC covers everything from the attributes to the end of the batch
+	var tail encoder
+	tail.int16(0) // attributes: no compression, create-time timestamps
+	tail.int32(int32(len(messages) - 1))
+	tail.int64(base)
+	tail.int64(maxTimestamp)
+	tail.int64(-1) // producer ID: not idempotent
+	tail.int16(-1) // producer epoch
+	tail.int32(-1) // base sequence
+	tail.int32(int32(len(messages)))
+	tail.b = append(tail.b, records.b...)
+
+	var batch encoder
+	batch.int64(0) // base offset, assigned by the broker
+	batch.int32(int32(4 + 1 + 4 + len(tail.b)))
+	batch.int32(-1) // partition leader epoch
+	batch.int8(2)   // magic
+	batch.b = binary.BigEndian.AppendUint32(batch.b, crc32.Checksum(tail.b, castagnoli))
+	batch.b = append(batch.b, tail.b...)
+	return batch.b
+}
+
+// request frames a request body with its size and a v1 request header
+func request(apiKey, version int16, correlationID int32, clientID string, body []byte) []byte {
+	var header encoder
+	header.int16(apiKey)
+	header.int16(version)
+	header.int32(correlationID)
+	header.string(clientID)
+
+	var e encoder
+	e.int32(int32(len(header.b) + len(body)))
+	e.b = append(e.b, header.b...)
+	e.b = append(e.b, body...)
+	return e.b
+}
+
+// broker is a broker address from a metadata response
+type broker struct {
+	id   int32
+	addr string
+}
+
+// partitionMeta is a partition's leader from a metadata response
+type partitionMeta struct {
+	id     int32
+	leader int32
+	err    int16
+}
+
+// metadataRequest asks for the partitions of one topic, creating it if the
+// broker allows automatic topic creation
+func metadataRequest(topic string) []byte {
+	var e encoder
+	e.int32(1)
+	e.string(topic)
+	e.int8(1) // allow_auto_topic_creation
+	return e.b
+}
+
+// parseMetadata reads the brokers and the topic's partitions from a v4
+// metadata response body
+func parseMetadata(body []byte, topic string) ([]broker, []partitionMeta, int16, error) {
+	d := &decoder{b: body}
+	d.int32() // throttle_time_ms
+
+	brokers := make([]broker, d.arrayLen())
+	for i := range brokers {
+		brokers[i].id = d.int32()
+		host := d.string()
+		port := d.int32()
+		d.string() // rack
+		brokers[i].addr = joinHostPort(host, port)
+	}
+	d.string() // cluster_id
+	d.int32()  // controller_id
+
+	var partitions []partitionMeta
+	topicErr := int16(errUnknownTopicOrPartition)
+	for range d.arrayLen() {
+		code := d.int16()
+		name := d.string()
+		d.int8() // is_internal
+		n := d.arrayLen()
+		var parts []partitionMeta
+		for range n {
+			p := partitionMeta{err: d.int16(), id: d.int32(), leader: d.int32()}
+			for range 2 { // replica_nodes, isr_nodes
+				d.take(4 * d.arrayLen())
+			}
+			parts = append(parts, p)
+		}
+		if name == topic {
+			topicErr, partitions = code, parts
+		}
+	}
+	return brokers, partitions, topicErr, d.err
+}
+
+// produceRequest sends one record batch to one partition
+func produceRequest(topic string, partition int32, acks int16, timeout time.Duration, batch []byte) []byte {
+	var e encoder
+	e.nullString() // transactional_id
+	e.int16(acks)
+	e.int32(int32(timeout.Milliseconds()))
+	e.int32(1)
+	e.string(topic)
+	e.int32(1)
+	e.int32(partition)
+	e.bytes(batch)
+	return e.b
+}
+
+// parseProduce returns the error code for the single partition produced to
+func parseProduce(body []byte) (int16, error) {
+	d := &decoder{b: body}
+	code := int16(errNone)
+	for range d.arrayLen() {
+		d.string() // name
+		for range d.arrayLen() {
+			d.int32() // index
+			code = d.int16()
+			d.int64() // base_offset
+			d.int64() // log_append_time_ms
+		}
+	}
+	return code, d.err
+}
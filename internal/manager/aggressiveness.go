@@ -0,0 +1,105 @@
+package manager
+
+import (
+	"sort"
+
+	"auction-simulator/pkg/models"
+)
+
+// aggressivenessBands is how many equal-sized bands the aggressiveness report
+// splits bidders into
+const aggressivenessBands = 4
+
+// bidderRecord is one bidder's auctions entered and won, and its bid total
+type bidderRecord struct {
+	aggressiveness float64
+	entries, wins  int
+	bids           int
+	total          float64
+}
+
+// buildAggressiveness relates each bidder's aggressiveness to its win rate. It
+// returns nil unless bidders differ in aggressiveness and at least two of them
+// entered an auction.
+func buildAggressiveness(bidders []models.BidderProfile, auctions []*models.Auction) *models.AggressivenessReport {
+	records := make(map[int]*bidderRecord, len(bidders))
+	varied := false
+	for _, p := range bidders {
+		records[p.ID] = &bidderRecord{aggressiveness: p.Aggressiveness}
+		varied = varied || p.Aggressiveness != bidders[0].Aggressiveness
+	}
+	if !varied {
+		return nil
+	}
+
+	for _, auction := range auctions {
+		entered := make(map[int]bool)
+		for _, bid := range auction.Bids {
+			if r, ok := records[bid.BidderID]; ok {
+				r.bids++
+				r.total += bid.Amount
+				entered[bid.BidderID] = true
+			}
+		}
+		for id := range entered {
+			records[id].entries++
+		}
+
+		winners := make(map[int]bool)
+		for _, alloc := range auction.Allocations {
+			winners[alloc.BidderID] = true
+		}
+		if auction.Winner != nil {
+			winners[auction.Winner.BidderID] = true
+		}
+		for id := range winners {
+			if r, ok := records[id]; ok {
+				r.wins++
+			}
+		}
+	}
+
+	var entrants []*bidderRecord
+	for _, r := range records {
+		if r.entries > 0 {
+			entrants = append(entrants, r)
+		}
+	}
+	if len(entrants) < 2 {
+		return nil
+	}
+	sort.Slice(entrants, func(i, j int) bool { return entrants[i].aggressiveness < entrants[j].aggressiveness })
+
+	aggressiveness := make([]float64, len(entrants))
+	winRates := make([]float64, len(entrants))
+	for i, r := range entrants {
+		aggressiveness[i] = r.aggressiveness
+		winRates[i] = float64(r.wins) / float64(r.entries)
+	}
+	report := &models.AggressivenessReport{Bidders: len(entrants)}
+	report.Correlation, _ = pearson(aggressiveness, winRates)
+
+	for band := range min(aggressivenessBands, len(entrants)) {
+		lo := band * len(entrants) / aggressivenessBands
+		hi := (band + 1) * len(entrants) / aggressivenessBands
+		if lo == hi {
+			continue
+		}
+		b := models.AggressivenessBand{
+			Min:     entrants[lo].aggressiveness,
+			Max:     entrants[hi-1].aggressiveness,
+			Bidders: hi - lo,
+		}
+		bids, total := 0, 0.0
+		for _, r := range entrants[lo:hi] {
+			b.Entries += r.entries
+			b.Wins += r.wins
+			bids += r.bids
+			total += r.total
+		}
+		b.WinRate = float64(b.Wins) / float64(b.Entries)
+		b.AvgBid = total / float64(bids)
+		report.Bands = append(report.Bands, b)
+	}
+	return report
+}
@@ -0,0 +1,80 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"auction-simulator/pkg/models"
+)
+
+// BidderStatsFileName is the file per-bidder statistics are written to
+const BidderStatsFileName = "bidder_stats.json"
+
+// SetBidderStats enables per-bidder statistics in the summary. They hold one
+// entry per bidder that bid, so they are off by default.
+func (og *OutputGenerator) SetBidderStats(enabled bool) {
+	og.bidderStats = enabled
+}
+
+// buildBidderStats counts, for every bidder with a stored bid, the auctions
+// it bid in, its bids, the auctions it won and what it paid under each
+// auction's pricing. Bidders are sorted by wins, most first, then by ID.
+func buildBidderStats(auctions []*models.Auction) []models.BidderStats {
+	byBidder := make(map[int]*models.BidderStats)
+	for _, auction := range auctions {
+		best := make(map[int]models.Bid)
+		for _, bid := range auction.Bids {
+			bs, ok := byBidder[bid.BidderID]
+			if !ok {
+				bs = &models.BidderStats{BidderID: bid.BidderID}
+				byBidder[bid.BidderID] = bs
+			}
+			bs.Bids++
+			if prev, ok := best[bid.BidderID]; !ok || bid.Amount > prev.Amount {
+				best[bid.BidderID] = bid
+			}
+		}
+
+		value, paid := settle(auction, best)
+		for id := range best {
+			bs := byBidder[id]
+			bs.Auctions++
+			if _, won := value[id]; won {
+				bs.Wins++
+			}
+			bs.TotalSpent += paid[id]
+		}
+	}
+
+	stats := make([]models.BidderStats, 0, len(byBidder))
+	for _, bs := range byBidder {
+		if bs.Auctions > 0 {
+			bs.WinRate = float64(bs.Wins) / float64(bs.Auctions)
+		}
+		stats = append(stats, *bs)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Wins != stats[j].Wins {
+			return stats[i].Wins > stats[j].Wins
+		}
+		return stats[i].BidderID < stats[j].BidderID
+	})
+	return stats
+}
+
+// WriteBidderStats writes bidder_stats.json, each bidder's participation,
+// bids, wins and spending, most wins first
+func (og *OutputGenerator) WriteBidderStats(result *models.RunResult) error {
+	data, err := json.MarshalIndent(buildBidderStats(result.Auctions), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal bidder stats: %w", err)
+	}
+
+	filename := filepath.Join(og.outputDir, BidderStatsFileName)
+	if err := og.writeFile(filename, data); err != nil {
+		return fmt.Errorf("failed to write bidder stats: %w", err)
+	}
+	return nil
+}
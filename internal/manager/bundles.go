@@ -0,0 +1,48 @@
+package manager
+
+import "auction-simulator/pkg/models"
+
+// buildBundles totals how combinatorial auctions cleared: the items they
+// sold, to how many winners, and the cleared value against what selling
+// every item together to the highest such bid would have raised. It returns
+// nil unless the auctions sold bundles.
+func buildBundles(auctions []*models.Auction) *models.BundleReport {
+	var report *models.BundleReport
+	winners := 0
+	for _, auction := range auctions {
+		if auction.BundleItems <= 1 {
+			continue
+		}
+		if report == nil {
+			report = &models.BundleReport{Items: auction.BundleItems}
+		}
+		report.Auctions++
+		report.ItemsOffered += auction.BundleItems
+		report.ClearedValue += auction.ClearedValue
+		winners += len(auction.Allocations)
+		for _, alloc := range auction.Allocations {
+			report.ItemsSold += len(alloc.Items)
+			if len(alloc.Items) == auction.BundleItems {
+				report.WholeSetWins++
+			}
+		}
+
+		whole := 0.0
+		for _, bid := range auction.Bids {
+			for _, bundle := range auction.BundlesOf(bid) {
+				if len(bundle.Items) == auction.BundleItems {
+					whole = max(whole, bundle.Amount)
+				}
+			}
+		}
+		report.WholeSetValue += whole
+	}
+	if report == nil {
+		return nil
+	}
+	report.AvgWinners = float64(winners) / float64(report.Auctions)
+	if report.WholeSetValue > 0 {
+		report.Gain = report.ClearedValue/report.WholeSetValue - 1
+	}
+	return report
+}
@@ -0,0 +1,31 @@
+package manager
+
+import "auction-simulator/pkg/models"
+
+// buildWinnersCurse measures, across common-value auctions with a winner, how
+// often and by how much the winner paid more than the item's true value. It
+// returns nil if no auction qualifies.
+func buildWinnersCurse(auctions []*models.Auction) *models.WinnersCurseReport {
+	report := &models.WinnersCurseReport{}
+	overpayment, margin := 0.0, 0.0
+	for _, auction := range auctions {
+		if auction.TrueValue <= 0 || auction.Winner == nil {
+			continue
+		}
+		report.Auctions++
+		margin += auction.TrueValue - auction.WinningPrice
+		if auction.Overpaid {
+			report.Overpaid++
+			overpayment += auction.WinningPrice - auction.TrueValue
+		}
+	}
+	if report.Auctions == 0 {
+		return nil
+	}
+	report.OverpaidRate = float64(report.Overpaid) / float64(report.Auctions)
+	report.AvgMargin = margin / float64(report.Auctions)
+	if report.Overpaid > 0 {
+		report.AvgOverpayment = overpayment / float64(report.Overpaid)
+	}
+	return report
+}
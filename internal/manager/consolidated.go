@@ -0,0 +1,99 @@
+package manager
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"auction-simulator/internal/faults"
+	"auction-simulator/pkg/models"
+)
+
+// ConsolidatedFileName is the file WriteConsolidatedResults writes in place of
+// per-auction result files
+const ConsolidatedFileName = "all_auctions.json"
+
+// WriteConsolidatedResults writes every auction result to all_auctions.json,
+// one JSON array in the order given. Auctions are encoded one at a time as
+// the file is written, so memory use does not grow with the output.
+func (og *OutputGenerator) WriteConsolidatedResults(auctions []*models.Auction) error {
+	if err := os.MkdirAll(og.outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := og.faults.Fail(faults.SiteOutputWrite, faults.NameKey(ConsolidatedFileName)); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ConsolidatedFileName, err)
+	}
+
+	filename := filepath.Join(og.outputDir, ConsolidatedFileName)
+	tmp := filename + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", ConsolidatedFileName, err)
+	}
+	defer os.Remove(tmp) // No-op once renamed into place
+
+	if err := encodeConsolidated(f, auctions); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", ConsolidatedFileName, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ConsolidatedFileName, err)
+	}
+	og.totalProcessed += len(auctions)
+	return os.Rename(tmp, filename)
+}
+
+// encodeConsolidated streams auctions to f as an indented JSON array. Each
+// auction is encoded into a reused buffer, which holds one auction at a time.
+func encodeConsolidated(f *os.File, auctions []*models.Auction) error {
+	w := bufio.NewWriter(f)
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("  ", "  ")
+
+	w.WriteString("[")
+	for i, auction := range auctions {
+		buf.Reset()
+		if err := enc.Encode(auction); err != nil {
+			return fmt.Errorf("failed to marshal auction %d: %w", auction.ID, err)
+		}
+		if i > 0 {
+			w.WriteString(",")
+		}
+		w.WriteString("\n  ")
+		w.Write(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+	}
+	if len(auctions) > 0 {
+		w.WriteString("\n")
+	}
+	w.WriteString("]\n")
+	return w.Flush()
+}
+
+// readConsolidated loads the auctions of a file written by
+// WriteConsolidatedResults, decoding them one at a time
+func readConsolidated(path string) ([]*models.Auction, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(bufio.NewReader(f))
+	if _, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	var auctions []*models.Auction
+	for dec.More() {
+		auction := &models.Auction{}
+		if err := dec.Decode(auction); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		auction.RestoreTimeout()
+		auctions = append(auctions, auction)
+	}
+	return auctions, nil
+}
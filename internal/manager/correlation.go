@@ -0,0 +1,129 @@
+package manager
+
+import (
+	"math"
+	"sort"
+
+	"auction-simulator/pkg/models"
+)
+
+// minCorrelationPoints is the fewest paired observations a correlation is
+// computed from; fewer would make it meaningless
+const minCorrelationPoints = 3
+
+// SetBidCorrelation enables the cross-auction bid correlation analysis in the
+// summary. It compares every pair of auctions, so it is off by default.
+func (og *OutputGenerator) SetBidCorrelation(enabled bool) {
+	og.bidCorrelation = enabled
+}
+
+// buildBidCorrelation measures whether bidders who bid high in one auction
+// also bid high in others, using each bidder's highest bid per auction.
+//
+// The aggregate is the mean Pearson correlation of bid amounts over the
+// bidders two auctions have in common, across every pair of auctions with
+// enough overlap. Per bidder, each bid is standardized against its auction
+// (a z-score), so a consistent bidder has a low z-score spread; market
+// correlation is how closely the bidder's bids track the auction average.
+func buildBidCorrelation(auctions []*models.Auction) *models.BidCorrelationReport {
+	// bids[i] maps bidder ID to that bidder's highest bid in auction i
+	bids := make([]map[int]float64, len(auctions))
+	type observation struct{ amount, z, mean float64 }
+	byBidder := make(map[int][]observation)
+
+	for i, auction := range auctions {
+		bids[i] = make(map[int]float64)
+		for _, bid := range auction.Bids {
+			if amount, ok := bids[i][bid.BidderID]; !ok || bid.Amount > amount {
+				bids[i][bid.BidderID] = bid.Amount
+			}
+		}
+
+		amounts := make([]float64, 0, len(bids[i]))
+		for _, amount := range bids[i] {
+			amounts = append(amounts, amount)
+		}
+		mean, sd := meanStdDev(amounts)
+		for id, amount := range bids[i] {
+			z := 0.0
+			if sd > 0 {
+				z = (amount - mean) / sd
+			}
+			byBidder[id] = append(byBidder[id], observation{amount, z, mean})
+		}
+	}
+
+	report := &models.BidCorrelationReport{}
+	total := 0.0
+	for i := range bids {
+		for j := i + 1; j < len(bids); j++ {
+			var xs, ys []float64
+			for id, x := range bids[i] {
+				if y, ok := bids[j][id]; ok {
+					xs = append(xs, x)
+					ys = append(ys, y)
+				}
+			}
+			if r, ok := pearson(xs, ys); ok {
+				total += r
+				report.AuctionPairs++
+			}
+		}
+	}
+	if report.AuctionPairs > 0 {
+		report.MeanPairwiseCorrelation = total / float64(report.AuctionPairs)
+	}
+
+	for id, observations := range byBidder {
+		zs := make([]float64, len(observations))
+		amounts := make([]float64, len(observations))
+		means := make([]float64, len(observations))
+		for k, o := range observations {
+			zs[k], amounts[k], means[k] = o.z, o.amount, o.mean
+		}
+		meanZ, sdZ := meanStdDev(zs)
+		market, _ := pearson(amounts, means)
+		report.Bidders = append(report.Bidders, models.BidderConsistency{
+			BidderID:          id,
+			Auctions:          len(observations),
+			MeanZScore:        meanZ,
+			ZScoreStdDev:      sdZ,
+			MarketCorrelation: market,
+		})
+	}
+	sort.Slice(report.Bidders, func(i, j int) bool { return report.Bidders[i].BidderID < report.Bidders[j].BidderID })
+	return report
+}
+
+// meanStdDev returns the mean and population standard deviation of xs
+func meanStdDev(xs []float64) (mean, sd float64) {
+	if len(xs) == 0 {
+		return 0, 0
+	}
+	for _, x := range xs {
+		mean += x
+	}
+	mean /= float64(len(xs))
+	for _, x := range xs {
+		sd += (x - mean) * (x - mean)
+	}
+	return mean, math.Sqrt(sd / float64(len(xs)))
+}
+
+// pearson returns the Pearson correlation of paired samples, or false if there
+// are too few pairs or either sample is constant
+func pearson(xs, ys []float64) (float64, bool) {
+	if len(xs) < minCorrelationPoints {
+		return 0, false
+	}
+	mx, sx := meanStdDev(xs)
+	my, sy := meanStdDev(ys)
+	if sx == 0 || sy == 0 {
+		return 0, false
+	}
+	cov := 0.0
+	for k := range xs {
+		cov += (xs[k] - mx) * (ys[k] - my)
+	}
+	return cov / float64(len(xs)) / (sx * sy), true
+}
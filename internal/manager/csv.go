@@ -0,0 +1,141 @@
+package manager
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"auction-simulator/pkg/models"
+)
+
+// CSV files written by -format csv and -write-samples
+const (
+	AuctionsCSVFileName = "auctions.csv"
+	BidsCSVFileName     = "bids.csv"
+	SamplesCSVFileName  = "resource_samples.csv"
+)
+
+// WriteAuctionResultsCSV writes auctions.csv, one row per auction. An auction
+// without a winner leaves the winner columns empty; grace_extensions counts
+// how often late bids pushed its deadline back.
+func (og *OutputGenerator) WriteAuctionResultsCSV(auctions []*models.Auction) error {
+	header := []string{
+		"auction_id", "total_bids", "winner_bidder_id", "winner_amount",
+		"duration_ms", "start_time", "end_time", "grace_extensions",
+	}
+	return og.writeCSV(AuctionsCSVFileName, header, func(w *csv.Writer) {
+		for _, a := range auctions {
+			winnerID, winnerAmount := "", ""
+			if a.Winner != nil {
+				winnerID = strconv.Itoa(a.Winner.BidderID)
+				winnerAmount = formatAmount(a.Winner.Amount)
+			}
+			w.Write([]string{
+				strconv.Itoa(a.ID),
+				strconv.Itoa(a.TotalBids),
+				winnerID,
+				winnerAmount,
+				strconv.FormatFloat(float64(a.EndTime.Sub(a.StartTime))/float64(time.Millisecond), 'f', 3, 64),
+				a.StartTime.Format(time.RFC3339Nano),
+				a.EndTime.Format(time.RFC3339Nano),
+				strconv.Itoa(a.GraceExtensions),
+			})
+		}
+	})
+}
+
+// WriteBidsCSV writes bids.csv, one row per stored bid across all auctions in
+// auction order. External bids leave strategy and valuation empty, and open
+// bids leave max_bid empty.
+func (og *OutputGenerator) WriteBidsCSV(auctions []*models.Auction) error {
+	header := []string{
+		"auction_id", "bidder_id", "amount", "valuation", "strategy",
+		"timestamp", "delta_from_first_bid_ms", "max_bid",
+	}
+	return og.writeCSV(BidsCSVFileName, header, func(w *csv.Writer) {
+		for _, a := range auctions {
+			for _, bid := range a.Bids {
+				valuation, maxBid := "", ""
+				if bid.Valuation != 0 {
+					valuation = formatAmount(bid.Valuation)
+				}
+				if bid.MaxBid > 0 {
+					maxBid = formatAmount(bid.MaxBid)
+				}
+				w.Write([]string{
+					strconv.Itoa(a.ID),
+					strconv.Itoa(bid.BidderID),
+					formatAmount(bid.Amount),
+					valuation,
+					bid.Strategy,
+					bid.Timestamp.Format(time.RFC3339Nano),
+					strconv.FormatFloat(bid.DeltaFromFirstBidMs, 'f', 3, 64),
+					maxBid,
+				})
+			}
+		}
+	})
+}
+
+// WriteSamplesCSV writes resource_samples.csv, the run's resource samples as a
+// time series. elapsed_ms counts from when monitoring started, and
+// auctions_closed is how many auctions had ended by each sample, so memory
+// and goroutine counts can be lined up against auctions closing.
+func (og *OutputGenerator) WriteSamplesCSV(result *models.RunResult) error {
+	ends := make([]time.Time, len(result.Auctions))
+	for i, a := range result.Auctions {
+		ends[i] = a.EndTime
+	}
+	slices.SortFunc(ends, time.Time.Compare)
+
+	header := []string{"elapsed_ms", "timestamp", "memory_mb", "goroutines", "cpu_percent", "auctions_closed"}
+	return og.writeCSV(SamplesCSVFileName, header, func(w *csv.Writer) {
+		closed := 0
+		for _, s := range result.Samples {
+			for closed < len(ends) && !ends[closed].After(s.Timestamp) {
+				closed++
+			}
+			w.Write([]string{
+				strconv.FormatFloat(float64(s.Timestamp.Sub(result.SamplesStart))/float64(time.Millisecond), 'f', 3, 64),
+				s.Timestamp.Format(time.RFC3339Nano),
+				strconv.FormatFloat(s.MemoryMB, 'f', 3, 64),
+				strconv.Itoa(s.NumGoroutines),
+				strconv.FormatFloat(s.CPUPercent, 'f', 1, 64),
+				strconv.Itoa(closed),
+			})
+		}
+	})
+}
+
+// writeCSV creates name in the output directory and writes header followed by
+// the rows from write
+func (og *OutputGenerator) writeCSV(name string, header []string, write func(w *csv.Writer)) error {
+	if err := os.MkdirAll(og.outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	file, err := os.Create(filepath.Join(og.outputDir, name))
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", name, err)
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+	w.Write(header)
+	write(w)
+	w.Flush()
+
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// formatAmount formats a money amount with two decimals
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
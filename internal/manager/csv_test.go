@@ -0,0 +1,52 @@
+package manager
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"auction-simulator/pkg/models"
+)
+
+// TestAuctionsCSVGraceExtensions writes an auction extended by late bids and
+// one that closed on time; grace_extensions must record how often each was
+// extended
+func TestAuctionsCSVGraceExtensions(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	extended := &models.Auction{ID: 1, TotalBids: 4, StartTime: start, EndTime: start.Add(350 * time.Millisecond), GraceExtensions: 3}
+	extended.Winner = &models.Bid{BidderID: 4, Amount: 103}
+	onTime := &models.Auction{ID: 2, TotalBids: 4, StartTime: start, EndTime: start.Add(200 * time.Millisecond)}
+
+	dir := t.TempDir()
+	if err := NewOutputGenerator(dir).WriteAuctionResultsCSV([]*models.Auction{extended, onTime}); err != nil {
+		t.Fatalf("WriteAuctionResultsCSV: %v", err)
+	}
+	f, err := os.Open(filepath.Join(dir, AuctionsCSVFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("reading %s: %v", AuctionsCSVFileName, err)
+	}
+
+	col := slices.Index(rows[0], "grace_extensions")
+	if col < 0 {
+		t.Fatalf("header %v has no grace_extensions column", rows[0])
+	}
+	want := [][]string{
+		{"1", "4", "4", "103.00", "350.000", "3"},
+		{"2", "4", "", "", "200.000", "0"},
+	}
+	for i, w := range want {
+		row := rows[i+1]
+		got := []string{row[0], row[1], row[2], row[3], row[4], row[col]}
+		if !slices.Equal(got, w) {
+			t.Errorf("row %d: id, bids, winner, amount, duration, extensions = %v, want %v", i+1, got, w)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package manager
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	"auction-simulator/pkg/models"
+)
+
+// SetFingerprint enables the run fingerprint in the summary
+func (og *OutputGenerator) SetFingerprint(enabled bool) {
+	og.fingerprint = enabled
+}
+
+// Fingerprint hashes every auction's outcome and combines the hashes, in
+// auction ID order, into the run's root digest
+func Fingerprint(auctions []*models.Auction) *models.RunFingerprint {
+	sorted := slices.Clone(auctions)
+	slices.SortFunc(sorted, func(a, b *models.Auction) int { return a.ID - b.ID })
+
+	fp := &models.RunFingerprint{Auctions: make([]models.AuctionHash, 0, len(sorted))}
+	root := sha256.New()
+	for _, auction := range sorted {
+		hash := auction.CanonicalHash()
+		fp.Auctions = append(fp.Auctions, models.AuctionHash{AuctionID: auction.ID, Hash: hash})
+		root.Write([]byte(hash))
+	}
+	fp.Root = hex.EncodeToString(root.Sum(nil))
+	return fp
+}
+
+// ReadFingerprint loads the fingerprint from an execution summary or a
+// single-file simulation written with the fingerprint enabled
+func ReadFingerprint(path string) (*models.RunFingerprint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		Fingerprint *models.RunFingerprint `json:"fingerprint"`
+		Summary     struct {
+			Fingerprint *models.RunFingerprint `json:"fingerprint"`
+		} `json:"summary"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if doc.Fingerprint == nil {
+		doc.Fingerprint = doc.Summary.Fingerprint
+	}
+	if doc.Fingerprint == nil {
+		return nil, fmt.Errorf("%s has no fingerprint; write it with -fingerprint", path)
+	}
+	return doc.Fingerprint, nil
+}
+
+// FirstDifference returns the lowest auction ID whose hash differs between
+// two fingerprints, or that only one of them has. It reports false if every
+// auction matches.
+func FirstDifference(want, got *models.RunFingerprint) (int, bool) {
+	i, j := 0, 0
+	for i < len(want.Auctions) || j < len(got.Auctions) {
+		switch {
+		case j == len(got.Auctions):
+			return want.Auctions[i].AuctionID, true
+		case i == len(want.Auctions):
+			return got.Auctions[j].AuctionID, true
+		case want.Auctions[i].AuctionID < got.Auctions[j].AuctionID:
+			return want.Auctions[i].AuctionID, true
+		case want.Auctions[i].AuctionID > got.Auctions[j].AuctionID:
+			return got.Auctions[j].AuctionID, true
+		case want.Auctions[i].Hash != got.Auctions[j].Hash:
+			return want.Auctions[i].AuctionID, true
+		}
+		i++
+		j++
+	}
+	return 0, false
+}
@@ -0,0 +1,87 @@
+package manager
+
+import (
+	"sort"
+
+	"auction-simulator/pkg/models"
+)
+
+// informationRecord is one bidder's visible attribute count and how it fared
+type informationRecord struct {
+	visible       int
+	entries, wins int
+	surplus       float64
+}
+
+// buildInformation relates how many attributes each bidder observed to its
+// win rate and surplus. It returns nil unless some bidder has a visibility
+// mask, and leaves out bids without a valuation.
+func buildInformation(bidders []models.BidderProfile, auctions []*models.Auction) *models.InformationReport {
+	records := make(map[int]*informationRecord, len(bidders))
+	masked := false
+	for _, p := range bidders {
+		visible := len(p.VisibleAttributes)
+		if visible == 0 {
+			visible = 20
+		} else {
+			masked = true
+		}
+		records[p.ID] = &informationRecord{visible: visible}
+	}
+	if !masked {
+		return nil
+	}
+
+	for _, auction := range auctions {
+		best := highestBids(auction)
+		value, paid := settle(auction, best)
+		for id := range best {
+			r, ok := records[id]
+			if !ok {
+				continue
+			}
+			r.entries++
+			if v, won := value[id]; won {
+				r.wins++
+				r.surplus += v
+			}
+			r.surplus -= paid[id]
+		}
+	}
+
+	report := &models.InformationReport{}
+	levels := make(map[int]*models.InformationLevel)
+	var visible, winRates, surpluses []float64
+	for _, r := range records {
+		if r.entries == 0 {
+			continue
+		}
+		report.Bidders++
+		visible = append(visible, float64(r.visible))
+		winRates = append(winRates, float64(r.wins)/float64(r.entries))
+		surpluses = append(surpluses, r.surplus/float64(r.entries))
+
+		level, ok := levels[r.visible]
+		if !ok {
+			level = &models.InformationLevel{Visible: r.visible}
+			levels[r.visible] = level
+		}
+		level.Bidders++
+		level.Entries += r.entries
+		level.Wins += r.wins
+		level.Surplus += r.surplus
+	}
+	if report.Bidders == 0 {
+		return nil
+	}
+	report.WinRateCorrelation, _ = pearson(visible, winRates)
+	report.SurplusCorrelation, _ = pearson(visible, surpluses)
+
+	for _, level := range levels {
+		level.WinRate = float64(level.Wins) / float64(level.Entries)
+		level.SurplusPerEntry = level.Surplus / float64(level.Entries)
+		report.Levels = append(report.Levels, *level)
+	}
+	sort.Slice(report.Levels, func(i, j int) bool { return report.Levels[i].Visible < report.Levels[j].Visible })
+	return report
+}
@@ -0,0 +1,57 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LockFilename is the lock file that marks an output directory as in use
+const LockFilename = ".auction-simulator.lock"
+
+// ErrOutputLocked is returned by Lock when another run holds the output directory
+var ErrOutputLocked = errors.New("output directory is locked by another run")
+
+// Lock claims the output directory for this run by creating a lock file with
+// O_EXCL, so two runs cannot interleave their output. The file records the
+// holder's PID and start time. With force, an existing lock is taken over,
+// e.g. one left behind by a killed run.
+func (og *OutputGenerator) Lock(force bool) error {
+	if err := os.MkdirAll(og.outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	path := filepath.Join(og.outputDir, LockFilename)
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if force {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+
+	file, err := os.OpenFile(path, flags, 0644)
+	if errors.Is(err, os.ErrExist) {
+		holder, _ := os.ReadFile(path)
+		return fmt.Errorf("%w (%s); remove %s or use -force if that run is gone",
+			ErrOutputLocked, strings.TrimSpace(string(holder)), path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create lock file: %w", err)
+	}
+	defer file.Close()
+
+	fmt.Fprintf(file, "pid %d since %s\n", os.Getpid(), time.Now().Format(time.RFC3339))
+	og.lockPath = path
+	return nil
+}
+
+// Unlock releases the output directory. It is safe to call more than once and
+// without holding the lock.
+func (og *OutputGenerator) Unlock() {
+	if og.lockPath == "" {
+		return
+	}
+	os.Remove(og.lockPath)
+	og.lockPath = ""
+}
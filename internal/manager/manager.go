@@ -2,95 +2,1118 @@ package manager
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"math"
+	"slices"
+	"sort"
 	"sync"
 	"time"
 
 	"auction-simulator/internal/auction"
 	"auction-simulator/internal/bidder"
+	"auction-simulator/internal/faults"
+	"auction-simulator/internal/resource"
+	"auction-simulator/internal/rng"
 	"auction-simulator/pkg/models"
 )
 
+// Defaults used when a SimConfig leaves a field unset
 const (
-	NumAuctions = 40
-	NumBidders  = 100
+	NumAuctions    = 40
+	NumBidders     = 100
+	AuctionTimeout = 5 * time.Second
 )
 
+// BurstRateWindow is the window over which burst runs measure each auction's
+// peak bid arrival rate
+const BurstRateWindow = 10 * time.Millisecond
+
+var (
+	// ErrUnknownAuction is returned when a bid targets an auction that was never started
+	ErrUnknownAuction = errors.New("unknown auction")
+	// ErrAuctionClosed is returned when a bid targets an auction that is no longer collecting bids
+	ErrAuctionClosed = errors.New("auction is closed")
+	// ErrInvalidBid is returned when a submitted bid has no bidder ID, an
+	// amount that is not positive and finite, or a negative or infinite max bid
+	ErrInvalidBid = errors.New("invalid bid")
+	// ErrBidBufferFull is returned when an auction's bid channel cannot accept more bids
+	ErrBidBufferFull = errors.New("auction bid buffer is full")
+	// ErrResultBufferFull fails an auction whose result finds the result
+	// buffer full under the error backpressure strategy
+	ErrResultBufferFull = errors.New("result buffer is full")
+)
+
+// runningAuction tracks the live state of an auction while it runs
+type runningAuction struct {
+	cancel  context.CancelFunc
+	auction *models.Auction
+	bidChan chan<- models.Bid
+}
+
 // Manager orchestrates the execution of multiple concurrent auctions
 type Manager struct {
-	config  models.ResourceConfig
-	bidders []*bidder.Bidder
+	config     models.SimConfig
+	bidders    []*bidder.Bidder
+	bounds     *bidder.Bounds
+	burst      *bidder.Burst
+	attention  *bidder.Attention
+	throttle   *resource.Throttle
+	acceptance auction.BidAcceptancePolicy
+
+	// inflight tracks bid goroutines so each auction can wait for its own to
+	// drain before it closes, and caps how many run at once across all auctions
+	inflight             *bidder.Tracker
+	pendingBidGoroutines int
+
+	// stopCause is why the run's context had ended when the last auction
+	// finished, nil if it had not
+	stopCause error
+
+	// faults injects failures for resilience testing; nil when disabled
+	faults         *faults.Injector
+	failedAuctions []int
+
+	// backpressure counts results that found the result buffer full
+	backpressure models.BackpressureReport
+
+	// ordering measures the reorder buffer when OrderedResults is set
+	ordering *models.OrderingReport
+
+	// recorder logs bid arrival order when RecordSequence is set
+	recorder *auction.SequenceRecorder
+
+	// bidLimit caps the bids admitted across all auctions when MaxTotalBids
+	// is set
+	bidLimit *auction.BidLimit
+
+	// memory holds back auction launches while the memory limit is exceeded
+	memory memoryGate
+
+	// templates are the attribute vectors auctions share, if any
+	templates [][20]float64
+
+	// reserves holds each auction's reserve price by ID - 1, if reserves are
+	// configured
+	reserves []float64
+
+	// timeouts holds each auction's timeout by ID - 1 when timeouts are drawn
+	// from a range; nil gives every auction AuctionTimeout
+	timeouts []time.Duration
+
+	// auctionSeed is the base seed of the per-auction streams auctions draw
+	// their attributes from, offset by auction ID
+	auctionSeed int64
+
+	// source is the run's generator, behind every stream the manager creates
+	source rng.Factory
+
+	// outstanding holds the start time of every auction whose result has not
+	// been collected yet, for the watchdog
+	outstanding map[int]time.Time
+	stuck       []int
+
+	// onComplete hooks are called from the result-collection loop for each
+	// finished auction, in registration order
+	onComplete []func(*models.Auction)
+
+	// running holds every auction that has been started but not yet finished;
+	// peakActive is the most it has held at once
+	running    map[int]*runningAuction
+	finished   map[int]bool
+	peakActive int
+	mu         sync.Mutex
 }
 
-// NewManager creates a new auction manager
-func NewManager(config models.ResourceConfig) *Manager {
-	// Create 100 bidders
-	bidders := make([]*bidder.Bidder, NumBidders)
-	for i := 0; i < NumBidders; i++ {
-		bidders[i] = bidder.NewBidder(i + 1)
+// NewManager creates a new auction manager whose every stream comes from
+// source. Bidders, groups, shared attributes and each auction's stream are
+// all derived from the run's top-level stream, seeded with the run seed,
+// which the manager uses only while it is created.
+func NewManager(config models.SimConfig, source rng.Factory) *Manager {
+	top := source.New(config.Seed)
+
+	// Create affiliation groups, if any
+	groups := make([]*bidder.Group, config.NumGroups)
+	for i := range groups {
+		groups[i] = bidder.NewGroup(i+1, top, source)
+	}
+
+	// Every bidder shares the same bounds so clamps are counted in one place
+	var bounds *bidder.Bounds
+	if config.MinBidAmount > 0 || config.MaxBidAmount > 0 {
+		bounds = &bidder.Bounds{Min: config.MinBidAmount, Max: config.MaxBidAmount}
+	}
+
+	var arrivals *bidder.Arrivals
+	if config.Arrivals {
+		arrivals = &bidder.Arrivals{DepartureRate: config.DepartureRate}
+	}
+
+	var burst *bidder.Burst
+	if config.BurstSize > 0 {
+		burst = &bidder.Burst{Size: config.BurstSize, Count: config.BurstCount}
+	}
+
+	var attention *bidder.Attention
+	if config.MaxActiveAuctions > 0 || config.WinCooldown > 0 {
+		attention = &bidder.Attention{MaxActive: config.MaxActiveAuctions, Cooldown: config.WinCooldown}
+	}
+
+	var throttle *resource.Throttle
+	if config.Resources.CPUQuota > 0 {
+		var ok bool
+		if throttle, ok = resource.NewThrottle(config.Resources.CPUQuota); !ok {
+			log.Printf("Warning: process CPU time is not available on this platform; the CPU quota is not enforced")
+		}
+	}
+
+	// Create the bidder population from the given profiles, or generate it,
+	// assigning groups round-robin
+	bidders := make([]*bidder.Bidder, config.NumBidders)
+	strategies := assignStrategies(config.StrategyMix, config.NumBidders)
+	for i := 0; i < config.NumBidders; i++ {
+		if len(config.Bidders) > 0 {
+			bidders[i] = bidder.NewBidderFromProfile(config.Bidders[i], top, source)
+		} else {
+			bidders[i] = bidder.NewBidder(i+1, top, source, strategies[i])
+			bidders[i].DeadlineAware = config.DeadlineAware
+		}
+		bidders[i].Bounds = bounds
+		bidders[i].ProxyRate = config.ProxyRate
+		bidders[i].CommonValueNoise = config.CommonValueNoise
+		bidders[i].ProcessingTimeout = config.BidderTimeout
+		bidders[i].Arrivals = arrivals
+		bidders[i].Burst = burst
+		bidders[i].Attention = attention
+		bidders[i].Throttle = throttle
+		if len(groups) > 0 {
+			bidders[i].Group = groups[i%len(groups)]
+		}
+	}
+
+	// Aggressiveness comes from its own stream, offset from the simulation's,
+	// so bidders keep the draws they would make with every bidder neutral
+	if config.AggressivenessMax > 0 {
+		stream := source.New(config.Seed + 2)
+		for i, b := range bidders {
+			if len(config.Bidders) > 0 && config.Bidders[i].Aggressiveness > 0 {
+				continue
+			}
+			b.Aggressiveness = config.AggressivenessMin + stream.Float64()*(config.AggressivenessMax-config.AggressivenessMin)
+		}
+	}
+
+	// Visible attributes come from a third stream, for the same reason
+	if config.VisibleMax > 0 {
+		stream := source.New(config.Seed + 3)
+		for i, b := range bidders {
+			if len(config.Bidders) > 0 && len(config.Bidders[i].VisibleAttributes) > 0 {
+				continue
+			}
+			var visible [20]bool
+			count := config.VisibleMin + stream.Intn(config.VisibleMax-config.VisibleMin+1)
+			for _, a := range stream.Perm(len(visible))[:count] {
+				visible[a] = true
+			}
+			b.VisibleAttributes = &visible
+		}
+	}
+
+	var injector *faults.Injector
+	if config.FaultRate > 0 {
+		// Offset the seed so the injector's stream is independent of the simulation's
+		injector = faults.NewInjector(config.FaultRate, config.Seed+1)
+	}
+
+	// The mode is validated before the manager is created
+	acceptance, _ := auction.AcceptancePolicy(config.Acceptance)
+
+	var recorder *auction.SequenceRecorder
+	if config.RecordSequence {
+		recorder = auction.NewSequenceRecorder()
+	}
+
+	var bidLimit *auction.BidLimit
+	if config.MaxTotalBids > 0 {
+		bidLimit = auction.NewBidLimit(config.MaxTotalBids)
+	}
+
+	// Shared attribute vectors are drawn after the bidders, so bidder seeds
+	// are the same whatever the attribute mode. The mode is validated before
+	// the manager is created.
+	count, _ := config.AttributeMode.Templates()
+	templates := make([][20]float64, count)
+	for i := range templates {
+		for j := range templates[i] {
+			templates[i][j] = top.Float64()
+		}
+	}
+
+	// Each auction draws its attributes from its own stream, so they do not
+	// depend on the order auctions start in or contend for a shared one
+	auctionSeed := top.Int63()
+
+	// Reserves come from their own stream, drawn up front so each auction's
+	// reserve does not depend on the order auctions start in
+	var reserves []float64
+	if config.ReserveMax > 0 {
+		stream := source.New(config.Seed + 4)
+		reserves = make([]float64, config.NumAuctions)
+		for i := range reserves {
+			reserves[i] = config.ReserveMin + stream.Float64()*(config.ReserveMax-config.ReserveMin)
+		}
+	}
+
+	// Timeouts come from a fifth stream, likewise drawn up front
+	var timeouts []time.Duration
+	if spread := config.AuctionTimeoutMax - config.AuctionTimeout; spread > 0 {
+		stream := source.New(config.Seed + 5)
+		timeouts = make([]time.Duration, config.NumAuctions)
+		for i := range timeouts {
+			timeouts[i] = config.AuctionTimeout + time.Duration(stream.Int63n(int64(spread)+1))
+		}
 	}
 
 	return &Manager{
-		config:  config,
-		bidders: bidders,
+		config:      config,
+		source:      source,
+		bounds:      bounds,
+		burst:       burst,
+		attention:   attention,
+		throttle:    throttle,
+		acceptance:  acceptance,
+		inflight:    bidder.NewTracker(config.MaxBidGoroutines),
+		faults:      injector,
+		bidders:     bidders,
+		recorder:    recorder,
+		bidLimit:    bidLimit,
+		templates:   templates,
+		reserves:    reserves,
+		timeouts:    timeouts,
+		auctionSeed: auctionSeed,
+		running:     make(map[int]*runningAuction),
+		finished:    make(map[int]bool),
+
+		outstanding: make(map[int]time.Time),
+	}
+}
+
+// assignStrategies returns the strategy of each of n generated bidders,
+// splitting them between mix's strategies by largest remainder, in mix order
+// and bidder ID order. An empty mix leaves every bidder on the default.
+func assignStrategies(mix []models.StrategyShare, n int) []bidder.Strategy {
+	strategies := make([]bidder.Strategy, n)
+	if len(mix) == 0 {
+		for i := range strategies {
+			strategies[i] = bidder.StrategyDefault
+		}
+		return strategies
 	}
+
+	total := 0.0
+	for _, s := range mix {
+		total += s.Share
+	}
+	counts := make([]int, len(mix))
+	remainders := make([]float64, len(mix))
+	assigned := 0
+	for i, s := range mix {
+		exact := s.Share / total * float64(n)
+		counts[i] = int(exact)
+		remainders[i] = exact - float64(counts[i])
+		assigned += counts[i]
+	}
+	for ; assigned < n; assigned++ {
+		largest := 0
+		for i := range remainders {
+			if remainders[i] > remainders[largest] {
+				largest = i
+			}
+		}
+		counts[largest]++
+		remainders[largest] = -1
+	}
+
+	i := 0
+	for k, s := range mix {
+		for range counts[k] {
+			strategies[i] = bidder.Strategy(s.Strategy)
+			i++
+		}
+	}
+	return strategies
+}
+
+// CancelAuction cancels a running auction, which finalizes immediately with the
+// bids collected so far
+func (m *Manager) CancelAuction(id int) error {
+	m.mu.Lock()
+	ra, ok := m.running[id]
+	m.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("auction %d is not running", id)
+	}
+	ra.cancel()
+	return nil
+}
+
+// SubmitBid routes an externally produced bid into a running auction. It fails
+// if the bid is invalid, or the auction is unknown, has stopped collecting
+// bids, or its buffer is full. In an integer-amount auction the bid's cents are
+// taken from its amounts, whatever the submitter sent.
+func (m *Manager) SubmitBid(auctionID int, bid models.Bid) error {
+	switch {
+	case bid.BidderID <= 0:
+		return fmt.Errorf("auction %d: %w: bidder ID must be positive, got %d", auctionID, ErrInvalidBid, bid.BidderID)
+	case !(bid.Amount > 0) || math.IsInf(bid.Amount, 1):
+		return fmt.Errorf("auction %d: %w: amount must be positive and finite, got %v", auctionID, ErrInvalidBid, bid.Amount)
+	case !(bid.MaxBid >= 0) || math.IsInf(bid.MaxBid, 1):
+		return fmt.Errorf("auction %d: %w: max bid must be finite and not negative, got %v", auctionID, ErrInvalidBid, bid.MaxBid)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ra, ok := m.running[auctionID]
+	if !ok || ra.bidChan == nil {
+		if m.finished[auctionID] {
+			return fmt.Errorf("auction %d: %w", auctionID, ErrAuctionClosed)
+		}
+		return fmt.Errorf("auction %d: %w", auctionID, ErrUnknownAuction)
+	}
+	if ra.auction.IntegerAmounts {
+		bid.Cents = models.ToCents(bid.Amount)
+		bid.MaxBidCents = models.ToCents(bid.MaxBid)
+	}
+	switch open, sent := ra.auction.Send(ra.bidChan, bid); {
+	case !open:
+		return fmt.Errorf("auction %d: %w", auctionID, ErrAuctionClosed)
+	case !sent:
+		return fmt.Errorf("auction %d: %w", auctionID, ErrBidBufferFull)
+	}
+	return nil
+}
+
+// Snapshot returns a consistent copy of a running auction's state
+func (m *Manager) Snapshot(auctionID int) (models.AuctionView, error) {
+	m.mu.Lock()
+	ra, ok := m.running[auctionID]
+	m.mu.Unlock()
+
+	if !ok || ra.auction == nil {
+		return models.AuctionView{}, fmt.Errorf("auction %d is not running", auctionID)
+	}
+	return ra.auction.Snapshot(), nil
+}
+
+// CurrentLeader returns the provisional highest bid of a running auction, or
+// nil if it has no bids yet
+func (m *Manager) CurrentLeader(auctionID int) (*models.Bid, error) {
+	m.mu.Lock()
+	ra, ok := m.running[auctionID]
+	m.mu.Unlock()
+
+	if !ok || ra.auction == nil {
+		return nil, fmt.Errorf("auction %d is not running", auctionID)
+	}
+	return ra.auction.CurrentLeader(), nil
+}
+
+// trackAuction registers the cancel function for a running auction
+func (m *Manager) trackAuction(id int, cancel context.CancelFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.running[id] = &runningAuction{cancel: cancel}
+	m.outstanding[id] = time.Now()
+	m.peakActive = max(m.peakActive, len(m.running))
+}
+
+// attachBidChannel records the bid channel of a running auction once it is open
+func (m *Manager) attachBidChannel(auction *models.Auction, bidChan chan<- models.Bid) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if ra, ok := m.running[auction.ID]; ok {
+		ra.auction = auction
+		ra.bidChan = bidChan
+	}
+}
+
+// untrackAuction removes a finished auction's live state
+func (m *Manager) untrackAuction(id int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.running, id)
+	m.finished[id] = true
+}
+
+// Faults returns the manager's fault injector, or nil if injection is disabled
+func (m *Manager) Faults() *faults.Injector {
+	return m.faults
+}
+
+// FailedAuctions returns the IDs of auctions that failed to run
+func (m *Manager) FailedAuctions() []int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	failed := append([]int(nil), m.failedAuctions...)
+	sort.Ints(failed)
+	return failed
+}
+
+// OnAuctionComplete registers fn to be called as each auction finishes. Hooks
+// are called from a single goroutine, so fn should return quickly. It must be
+// called before Run.
+func (m *Manager) OnAuctionComplete(fn func(*models.Auction)) {
+	m.onComplete = append(m.onComplete, fn)
+}
+
+// LateBids returns how many bids were ready only after their auction's deadline
+func (m *Manager) LateBids() int {
+	return m.inflight.LateBids()
+}
+
+// Participation compares each bidder's configured participation rate with the
+// share of notified auctions it joined. Bidders never notified are skipped.
+func (m *Manager) Participation() models.ParticipationReport {
+	var report models.ParticipationReport
+	total := 0.0
+	for _, b := range m.bidders {
+		notified, participated := b.Participation()
+		if notified == 0 {
+			continue
+		}
+
+		deviation := math.Abs(float64(participated)/float64(notified) - b.ParticipationRate)
+		total += deviation
+		report.Bidders++
+		if deviation > report.MaxAbsDeviation {
+			report.MaxAbsDeviation = deviation
+			report.MaxDeviationID = b.ID
+		}
+	}
+
+	if report.Bidders > 0 {
+		report.MeanAbsDeviation = total / float64(report.Bidders)
+	}
+	return report
+}
+
+// BidderProfiles returns the profile of every bidder, including the seed of
+// its random source, in bidder order
+func (m *Manager) BidderProfiles() []models.BidderProfile {
+	profiles := make([]models.BidderProfile, len(m.bidders))
+	for i, b := range m.bidders {
+		profiles[i] = b.Profile()
+	}
+	return profiles
 }
 
-// Run executes all auctions concurrently and returns the results
+// ClampedBids reports how many calculated bids were clamped to the amount
+// bounds, or nil if no bounds are configured
+func (m *Manager) ClampedBids() *models.ClampReport {
+	if m.bounds == nil {
+		return nil
+	}
+	atMin, atMax := m.bounds.Clamped()
+	return &models.ClampReport{AtMin: atMin, AtMax: atMax}
+}
+
+// Burst reports the bids sent and dropped by burst-mode bidders, or nil if
+// bidders place single bids
+func (m *Manager) Burst() *models.BurstReport {
+	if m.burst == nil {
+		return nil
+	}
+	sent, dropped := m.burst.Counts()
+	return &models.BurstReport{Size: m.burst.Size, Count: m.burst.Count, Sent: sent, Dropped: dropped}
+}
+
+// Attention reports the auctions bidders declined for lack of attention, or
+// nil if bidders' attention is unlimited
+func (m *Manager) Attention() *models.AttentionReport {
+	if m.attention == nil {
+		return nil
+	}
+	saturated, cooling := m.attention.Declines()
+	report := &models.AttentionReport{
+		MaxActive:   m.attention.MaxActive,
+		CooldownMs:  m.attention.Cooldown.Milliseconds(),
+		Saturated:   saturated,
+		CoolingDown: cooling,
+	}
+	participated := 0
+	for _, b := range m.bidders {
+		_, p := b.Participation()
+		participated += p
+		if b.Declined() > 0 {
+			report.Bidders = append(report.Bidders, b.ID)
+		}
+	}
+	if declined := saturated + cooling; declined > 0 {
+		report.DeclineRate = float64(declined) / float64(participated+declined)
+	}
+	return report
+}
+
+// recordWins starts the cooldown of every bidder that won in a, if bidders
+// cool down after wins
+func (m *Manager) recordWins(a *models.Auction) {
+	if m.attention == nil || m.attention.Cooldown <= 0 {
+		return
+	}
+	winners := make(map[int]bool)
+	for _, alloc := range a.Allocations {
+		winners[alloc.BidderID] = true
+	}
+	if a.Winner != nil {
+		winners[a.Winner.BidderID] = true
+	}
+	for _, b := range m.bidders {
+		if winners[b.ID] {
+			b.RecordWin(a.EndTime)
+		}
+	}
+}
+
+// Throttled returns how long bidder work waited for the CPU quota, or zero
+// without one
+func (m *Manager) Throttled() time.Duration {
+	if m.throttle == nil {
+		return 0
+	}
+	return m.throttle.Throttled()
+}
+
+// SlowBidders reports bids abandoned for exceeding the bidder processing
+// timeout, or nil if no timeout is configured
+func (m *Manager) SlowBidders() *models.SlowBidderReport {
+	if m.config.BidderTimeout <= 0 {
+		return nil
+	}
+	report := &models.SlowBidderReport{TimeoutMs: m.config.BidderTimeout.Milliseconds()}
+	for _, b := range m.bidders {
+		if n := b.Abandoned(); n > 0 {
+			report.AbandonedBids += n
+			report.Bidders = append(report.Bidders, b.ID)
+		}
+	}
+	return report
+}
+
+// OnBidLimit registers fn to stop the run once MaxTotalBids bids have been
+// admitted; it must be called before Run and does nothing without a limit
+func (m *Manager) OnBidLimit(fn func()) {
+	if m.bidLimit != nil {
+		m.bidLimit.OnReached(fn)
+	}
+}
+
+// BidLimit reports the bids admitted against MaxTotalBids and those refused
+// after it was reached, or nil if the run has no bid limit
+func (m *Manager) BidLimit() *models.BidLimitReport {
+	if m.bidLimit == nil {
+		return nil
+	}
+	bids, refused := m.bidLimit.Counts()
+	return &models.BidLimitReport{
+		Limit:   m.config.MaxTotalBids,
+		Bids:    bids,
+		Refused: refused,
+		Reached: m.bidLimit.Reached(),
+	}
+}
+
+// Sequence returns the recorded bid arrival order, or nil if RecordSequence
+// is not set
+func (m *Manager) Sequence() *models.BidSequence {
+	if m.recorder == nil {
+		return nil
+	}
+	return m.recorder.Sequence(m.config.Seed, m.config.NumAuctions)
+}
+
+// PeakBidGoroutines returns the highest number of bid goroutines that ran at once
+func (m *Manager) PeakBidGoroutines() int {
+	return m.inflight.Peak()
+}
+
+// PeakActiveAuctions returns the highest number of auctions that ran at once
+func (m *Manager) PeakActiveAuctions() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.peakActive
+}
+
+// StopCause returns why the run's context had ended by the time the last
+// auction finished, or nil if the run was not stopped
+func (m *Manager) StopCause() error {
+	return m.stopCause
+}
+
+// PendingBidGoroutines returns how many bid goroutines were still in flight
+// when Run finished, their auctions having given up waiting for them
+func (m *Manager) PendingBidGoroutines() int {
+	return m.pendingBidGoroutines
+}
+
+// auctionOptions returns the options for auction auctionID: those every
+// auction shares, its reserve price, and its sealed or Dutch format
+func (m *Manager) auctionOptions(auctionID int) auction.Options {
+	opts := auction.Options{
+		Timeout:             m.auctionTimeout(auctionID),
+		IntegerAmounts:      m.config.IntegerAmounts,
+		Pricing:             m.config.Pricing,
+		Units:               m.config.Units,
+		BundleItems:         m.config.BundleItems,
+		MinIncrement:        m.config.MinIncrement,
+		Fee:                 m.config.Fee,
+		AttributeImportance: m.config.AttributeImportance,
+		Acceptance:          m.acceptance,
+		Faults:              m.faults,
+		MinDuration:         m.config.MinDuration,
+		Drain:               func() { m.inflight.Drain(auctionID, m.config.DrainTimeout) },
+		HammerGrace:         m.config.HammerGrace,
+		HammerMaxExtensions: m.config.HammerMaxExtensions,
+		CoalesceWindow:      m.config.CoalesceWindow,
+		CoalesceKeep:        m.config.CoalesceKeep,
+		MaxStoredBids:       m.config.MaxStoredBids,
+		AttributeTemplates:  m.templates,
+		Recorder:            m.recorder,
+		BidLimit:            m.bidLimit,
+		ExpectedBids:        len(m.bidders),
+		BidBuffer:           len(m.bidders),
+		Rand:                m.source.New(m.auctionSeed + int64(auctionID)),
+		CommonValue:         m.config.CommonValueNoise > 0,
+	}
+	if m.reserves != nil {
+		opts.ReservePrice = m.reserves[auctionID-1]
+	}
+	if m.burst != nil {
+		opts.RateWindow = BurstRateWindow
+	}
+	if m.config.RevealWindow > 0 {
+		opts.Sealed = &auction.Sealed{
+			RevealWindow: m.config.RevealWindow,
+			Commit: func(ctx context.Context, a *models.Auction, commits chan<- models.Commitment) {
+				for _, b := range m.notificationOrder(a.ID) {
+					b.ConsiderCommit(ctx, a, commits, m.inflight)
+				}
+			},
+			Reveal: func(ctx context.Context, a *models.Auction, reveals chan<- models.Reveal) {
+				for _, b := range m.notificationOrder(a.ID) {
+					b.ConsiderReveal(ctx, a, reveals, m.inflight)
+				}
+			},
+		}
+	}
+	if m.config.DutchStart > 0 {
+		// Bidders decide whether to watch, and their limits, as the clock
+		// starts; Announce is only ever called from the auction's goroutine
+		var watchers []*bidder.ClockWatcher
+		opts.Dutch = &auction.Dutch{
+			Start: m.config.DutchStart,
+			Floor: m.config.DutchFloor,
+			Ticks: m.config.DutchTicks,
+			Announce: func(ctx context.Context, a *models.Auction, tick int, price float64, accepts chan<- models.Bid) {
+				if tick == 0 {
+					for _, b := range m.notificationOrder(a.ID) {
+						if w := b.Watch(a); w != nil {
+							watchers = append(watchers, w)
+						}
+					}
+				}
+				for _, w := range watchers {
+					w.ConsiderPrice(ctx, a, price, accepts, m.inflight)
+				}
+			},
+		}
+	}
+	return opts
+}
+
+// auctionTimeout returns how long auctionID collects bids
+func (m *Manager) auctionTimeout(auctionID int) time.Duration {
+	if m.timeouts != nil {
+		return m.timeouts[auctionID-1]
+	}
+	return m.config.AuctionTimeout
+}
+
+// notificationOrder returns the bidders in the order auctionID notifies them:
+// ID order, or with ShuffleBidders a permutation drawn from the run seed and
+// the auction ID, so every run with the same seed notifies in the same order
+func (m *Manager) notificationOrder(auctionID int) []*bidder.Bidder {
+	if !m.config.ShuffleBidders {
+		return m.bidders
+	}
+	order := slices.Clone(m.bidders)
+	stream := m.source.New(m.config.Seed + int64(auctionID))
+	stream.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+	return order
+}
+
+// runAuction runs a single auction, tracking it so it can be cancelled or fed
+// bids while it runs. It returns the finished auction, or nil after recording
+// the auction as failed if it cannot run.
+func (m *Manager) runAuction(ctx context.Context, auctionID int, attributeBias float64, notifyBidders auction.Notifier) *models.Auction {
+	// Give each auction its own cancel function so it can be stopped individually
+	auctionCtx, cancel := context.WithCancel(ctx)
+	m.trackAuction(auctionID, cancel)
+	defer func() {
+		m.untrackAuction(auctionID)
+		cancel()
+	}()
+
+	// Run auction with the configured timeout
+	opts := m.auctionOptions(auctionID)
+	opts.AttributeBias = attributeBias
+	single := make(chan *models.Auction, 1)
+	if err := auction.Run(auctionCtx, auctionID, opts, notifyBidders, single); err != nil {
+		m.fail(auctionID, err)
+		return nil
+	}
+	a := <-single
+	m.recordWins(a)
+	return a
+}
+
+// fail records an auction as failed
+func (m *Manager) fail(auctionID int, err error) {
+	log.Printf("Auction %d failed: %v", auctionID, err)
+	m.mu.Lock()
+	m.failedAuctions = append(m.failedAuctions, auctionID)
+	delete(m.outstanding, auctionID)
+	m.mu.Unlock()
+}
+
+// enqueue queues a finished auction for collection, applying the configured
+// backpressure strategy if the result buffer is full
+func (m *Manager) enqueue(results chan *models.Auction, a *models.Auction) {
+	for {
+		select {
+		case results <- a:
+			return
+		default:
+		}
+
+		switch m.config.Backpressure {
+		case models.BackpressureDropOldest:
+			select {
+			case oldest := <-results:
+				log.Printf("Result buffer full: dropping auction %d", oldest.ID)
+				m.mu.Lock()
+				m.backpressure.Dropped = append(m.backpressure.Dropped, oldest.ID)
+				delete(m.outstanding, oldest.ID)
+				m.mu.Unlock()
+			default:
+				// The collector emptied a slot first; retry the send
+			}
+		case models.BackpressureError:
+			m.mu.Lock()
+			m.backpressure.Rejected = append(m.backpressure.Rejected, a.ID)
+			m.mu.Unlock()
+			m.fail(a.ID, ErrResultBufferFull)
+			return
+		default:
+			m.mu.Lock()
+			m.backpressure.Blocked++
+			m.mu.Unlock()
+			results <- a
+			return
+		}
+	}
+}
+
+// gone reports whether an auction failed or had its result dropped, so it
+// will never be collected
+func (m *Manager) gone(id int) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Contains(m.failedAuctions, id) || slices.Contains(m.backpressure.Dropped, id)
+}
+
+// Ordering reports what delivering results in ID order cost, or nil if
+// OrderedResults is not set. Serial runs finish in order, holding nothing.
+func (m *Manager) Ordering() *models.OrderingReport {
+	if !m.config.OrderedResults {
+		return nil
+	}
+	if m.ordering == nil {
+		return &models.OrderingReport{}
+	}
+	return m.ordering
+}
+
+// Backpressure reports how often finished auctions found the result buffer
+// full, or nil if the buffer was left at its default size
+func (m *Manager) Backpressure() *models.BackpressureReport {
+	if m.config.ResultBuffer == 0 {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	report := m.backpressure
+	report.BufferSize = m.config.ResultBuffer
+	return &report
+}
+
+// runSequential runs auctions one at a time in ID order, letting the competition
+// seen in each auction shift the attributes of the next. The competition index
+// is the share of bidders who bid; every point above 0.5 raises the attribute
+// bias by FeedbackStrength, and every point below lowers it. The bias is kept
+// within ±0.5 so attributes still span a meaningful range.
+func (m *Manager) runSequential(ctx context.Context, notifyBidders auction.Notifier, results chan *models.Auction) {
+	bias := 0.0
+	for auctionID := 1; auctionID <= m.config.NumAuctions; auctionID++ {
+		if m.memory.wait(ctx); ctx.Err() != nil {
+			return
+		}
+
+		a := m.runAuction(ctx, auctionID, bias, notifyBidders)
+		if a == nil {
+			continue // The auction failed; carry the current bias forward unchanged
+		}
+		m.enqueue(results, a)
+		bias = m.nextBias(bias, a)
+	}
+}
+
+// nextBias returns the attribute bias for the auction after a, given the
+// competition a attracted; see runSequential
+func (m *Manager) nextBias(bias float64, a *models.Auction) float64 {
+	competition := float64(a.TotalBids) / float64(len(m.bidders))
+	bias += m.config.FeedbackStrength * (competition - 0.5)
+	return min(max(bias, -0.5), 0.5)
+}
+
+// runSerial runs every auction in ID order on the calling goroutine, asking
+// bidders in notification order for their bids; see auction.RunSerial. Feedback mode
+// threads the attribute bias through as runSequential does. Auctions never
+// accept external bids in serial mode.
+func (m *Manager) runSerial(ctx context.Context) ([]*models.Auction, time.Time, time.Time, error) {
+	placeBids := func(a *models.Auction) []models.Bid {
+		bids := make([]models.Bid, 0, len(m.bidders))
+		for _, b := range m.notificationOrder(a.ID) {
+			if bid, ok := b.Bid(a); ok {
+				bids = append(bids, bid)
+			}
+		}
+		return m.orderSubmissions(a, bids)
+	}
+
+	var auctionResults []*models.Auction
+	bias := 0.0
+	for auctionID := 1; auctionID <= m.config.NumAuctions; auctionID++ {
+		if m.stopCause = context.Cause(ctx); m.stopCause != nil {
+			break
+		}
+
+		opts := m.auctionOptions(auctionID)
+		opts.AttributeBias = bias
+		result, late, err := auction.RunSerial(auctionID, opts, placeBids)
+		m.stopCause = context.Cause(ctx)
+		if err != nil {
+			m.fail(auctionID, err)
+			continue
+		}
+		m.inflight.AddLate(late)
+		m.mu.Lock()
+		m.finished[auctionID] = true
+		m.mu.Unlock()
+
+		auctionResults = append(auctionResults, result)
+		fmt.Printf("Auction %d completed with %d bids\n", result.ID, result.TotalBids)
+		for _, fn := range m.onComplete {
+			fn(result)
+		}
+		if m.config.FeedbackStrength > 0 {
+			bias = m.nextBias(bias, result)
+		}
+	}
+
+	firstStart, lastEnd := timeBounds(auctionResults)
+	return auctionResults, firstStart, lastEnd, nil
+}
+
+// Run executes all auctions and returns the results. Auctions run concurrently
+// unless feedback mode requires them to run in sequence, or on the calling
+// goroutine alone in serial mode.
 func (m *Manager) Run(ctx context.Context) ([]*models.Auction, time.Time, time.Time, error) {
-	// Create channel for results
-	results := make(chan *models.Auction, NumAuctions)
+	if m.config.Serial {
+		return m.runSerial(ctx)
+	}
+
+	// Create channel for results; by default it holds every auction, so
+	// finished auctions never wait on the collector
+	bufferSize := m.config.ResultBuffer
+	if bufferSize == 0 {
+		bufferSize = m.config.NumAuctions
+	}
+	results := make(chan *models.Auction, bufferSize)
 
 	var wg sync.WaitGroup
 
+	var replay auction.Notifier
+	if m.config.ReplaySequence != nil {
+		replay = auction.ReplayNotifier(m.config.ReplaySequence)
+	}
+
 	// Create a function to notify all bidders about an auction
-	notifyBidders := func(auction *models.Auction, bidChan chan<- models.Bid) {
-		// Notify all 100 bidders about this auction
-		for _, b := range m.bidders {
-			b.ConsiderBid(auction, bidChan)
+	notifyBidders := func(ctx context.Context, auction *models.Auction, bidChan chan<- models.Bid) {
+		// Make the auction reachable for external bid submission
+		m.attachBidChannel(auction, bidChan)
+
+		// Replay the recorded bids in place of the bidders, or notify every
+		// bidder about this auction
+		if replay != nil {
+			replay(ctx, auction, bidChan)
+			return
+		}
+		for _, b := range m.notificationOrder(auction.ID) {
+			b.ConsiderBid(ctx, auction, bidChan, m.inflight)
 		}
 	}
 
-	// Launch all 40 auctions concurrently
-	for i := 1; i <= NumAuctions; i++ {
+	if m.config.FeedbackStrength > 0 {
+		// Feedback mode threads each auction's outcome into the next, so auctions
+		// run one after another in ID order
 		wg.Add(1)
-		go func(auctionID int) {
+		go func() {
 			defer wg.Done()
-
-			// Run auction with timeout (5 seconds)
-			timeout := 5 * time.Second
-			auction.Run(ctx, auctionID, timeout, notifyBidders, results)
-		}(i)
+			m.runSequential(ctx, notifyBidders, results)
+		}()
+	} else if m.config.MaxConcurrentAuctions > 0 {
+		// A bounded pool runs the auctions, so collection starts while later
+		// ones are still queued
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.runPooled(ctx, notifyBidders, results)
+		}()
+	} else {
+		// Launch all auctions concurrently, unless the run was stopped before
+		// it began
+		for i := 1; i <= m.config.NumAuctions && ctx.Err() == nil; i++ {
+			if m.memory.wait(ctx); ctx.Err() != nil {
+				break
+			}
+			wg.Add(1)
+			go func(auctionID int) {
+				defer wg.Done()
+				if a := m.runAuction(ctx, auctionID, 0, notifyBidders); a != nil {
+					m.enqueue(results, a)
+				}
+			}(i)
+		}
 	}
 
-	// Wait for all auctions to complete in a separate goroutine
+	// Wait for all auctions to complete in a separate goroutine. Only a stop
+	// that came before then cut the run short; one that comes while results
+	// are delivered stops nothing.
 	go func() {
 		wg.Wait()
+		m.stopCause = context.Cause(ctx)
 		close(results)
 	}()
 
-	// Collect all results
+	// Watch for auctions that never deliver a result while collection runs
+	collected := make(chan struct{})
+	go m.watch(collected)
+	defer close(collected)
+
+	// Collect all results, in ID order if configured
 	var auctionResults []*models.Auction
-	for result := range results {
+	deliver := func(result *models.Auction) {
 		auctionResults = append(auctionResults, result)
 		fmt.Printf("Auction %d completed with %d bids\n", result.ID, result.TotalBids)
+		for _, fn := range m.onComplete {
+			fn(result)
+		}
 	}
+	var order *reorderBuffer
+	if m.config.OrderedResults {
+		order = newReorderBuffer(m.config.NumAuctions, m.gone)
+	}
+	for result := range results {
+		m.mu.Lock()
+		delete(m.outstanding, result.ID)
+		m.mu.Unlock()
 
-	// Record actual first start time and last end time from results
-	var firstStart, lastEnd time.Time
-	if len(auctionResults) > 0 {
-		firstStart = auctionResults[0].StartTime
-		lastEnd = auctionResults[0].EndTime
+		if order == nil {
+			deliver(result)
+			continue
+		}
+		for _, ready := range order.push(result) {
+			deliver(ready)
+		}
+	}
+	if order != nil {
+		// Results held behind an auction that never finished go out last
+		for _, ready := range order.flush() {
+			deliver(ready)
+		}
+		m.ordering = order.ordering()
+	}
 
-		for _, a := range auctionResults {
-			if a.StartTime.Before(firstStart) {
-				firstStart = a.StartTime
-			}
-			if a.EndTime.After(lastEnd) {
-				lastEnd = a.EndTime
+	// Each auction drained its own bidders before closing; any still running
+	// outlasted the drain timeout
+	m.pendingBidGoroutines = m.inflight.Pending()
+
+	firstStart, lastEnd := timeBounds(auctionResults)
+	return auctionResults, firstStart, lastEnd, nil
+}
+
+// runPooled runs the auctions on MaxConcurrentAuctions workers, handing them
+// out in ID order. Queued auctions start only once a worker is free, so each
+// auction's start time is when it left the queue. Auctions still queued when
+// ctx ends are never run.
+func (m *Manager) runPooled(ctx context.Context, notifyBidders auction.Notifier, results chan *models.Auction) {
+	ids := make(chan int)
+	var workers sync.WaitGroup
+	for range min(m.config.MaxConcurrentAuctions, m.config.NumAuctions) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for auctionID := range ids {
+				if a := m.runAuction(ctx, auctionID, 0, notifyBidders); a != nil {
+					m.enqueue(results, a)
+				}
 			}
+		}()
+	}
+
+queue:
+	for auctionID := 1; auctionID <= m.config.NumAuctions; auctionID++ {
+		if m.memory.wait(ctx); ctx.Err() != nil {
+			break
+		}
+		select {
+		case ids <- auctionID:
+		case <-ctx.Done():
+			break queue
 		}
 	}
+	close(ids)
+	workers.Wait()
+}
 
-	return auctionResults, firstStart, lastEnd, nil
+// timeBounds returns the actual first start time and last end time of auctions
+func timeBounds(auctions []*models.Auction) (firstStart, lastEnd time.Time) {
+	if len(auctions) == 0 {
+		return
+	}
+	firstStart = auctions[0].StartTime
+	lastEnd = auctions[0].EndTime
+
+	for _, a := range auctions {
+		if a.StartTime.Before(firstStart) {
+			firstStart = a.StartTime
+		}
+		if a.EndTime.After(lastEnd) {
+			lastEnd = a.EndTime
+		}
+	}
+	return firstStart, lastEnd
 }
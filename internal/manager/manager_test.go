@@ -0,0 +1,110 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"auction-simulator/pkg/models"
+)
+
+// TestRunPooled runs more auctions than workers with a notifier that counts
+// the auctions collecting bids at any moment; the count must reach the pool
+// size but never exceed it, and every auction must still run
+func TestRunPooled(t *testing.T) {
+	const auctions, workers = 12, 3
+	timeout := 50 * time.Millisecond
+	m := NewManager(models.SimConfig{
+		Seed:                  1,
+		NumAuctions:           auctions,
+		AuctionTimeout:        timeout,
+		MaxConcurrentAuctions: workers,
+	}, nil)
+
+	// The notifier holds each auction until it stops collecting bids, so
+	// the count drops before the worker can start its next auction
+	var active, peak atomic.Int64
+	notify := func(ctx context.Context, auction *models.Auction, bidChan chan<- models.Bid) {
+		n := active.Add(1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+		<-ctx.Done()
+		active.Add(-1)
+	}
+
+	results := make(chan *models.Auction, auctions)
+	start := time.Now()
+	m.runPooled(context.Background(), notify, results)
+	elapsed := time.Since(start)
+	close(results)
+
+	if got := peak.Load(); got != workers {
+		t.Errorf("peak of %d auctions active at once, want %d", got, workers)
+	}
+	if got := m.PeakActiveAuctions(); got > workers {
+		t.Errorf("manager saw %d auctions active at once, want at most %d", got, workers)
+	}
+	seen := make(map[int]bool)
+	for a := range results {
+		seen[a.ID] = true
+	}
+	if len(seen) != auctions {
+		t.Errorf("%d auctions ran, want %d", len(seen), auctions)
+	}
+	// Four waves of three auctions cannot finish in less than four timeouts
+	if want := auctions / workers * timeout; elapsed < want {
+		t.Errorf("pool finished in %v, want at least %v", elapsed, want)
+	}
+}
+
+// TestSubmitBid submits external bids to a running integer-amount auction.
+// Bids without a bidder or a positive, finite amount must be refused with
+// ErrInvalidBid, and the cents of those routed must follow their amounts.
+func TestSubmitBid(t *testing.T) {
+	tests := []struct {
+		name    string
+		bid     models.Bid
+		invalid bool
+	}{
+		{"valid", models.Bid{BidderID: 1001, Amount: 95}, false},
+		{"mismatched cents", models.Bid{BidderID: 1001, Amount: 95, Cents: 1_000_000, MaxBid: 120, MaxBidCents: 1}, false},
+		{"zero bidder", models.Bid{Amount: 95}, true},
+		{"negative bidder", models.Bid{BidderID: -3, Amount: 95}, true},
+		{"zero amount", models.Bid{BidderID: 1001}, true},
+		{"negative amount", models.Bid{BidderID: 1001, Amount: -95}, true},
+		{"NaN amount", models.Bid{BidderID: 1001, Amount: math.NaN()}, true},
+		{"infinite amount", models.Bid{BidderID: 1001, Amount: math.Inf(1)}, true},
+		{"negative max bid", models.Bid{BidderID: 1001, Amount: 95, MaxBid: -1}, true},
+		{"infinite max bid", models.Bid{BidderID: 1001, Amount: 95, MaxBid: math.Inf(1)}, true},
+	}
+	for _, tt := range tests {
+		m := NewManager(models.SimConfig{Seed: 1, NumAuctions: 1, AuctionTimeout: time.Second}, nil)
+		auction := models.NewAuction(1, time.Second, 1)
+		auction.IntegerAmounts = true
+		bidChan := make(chan models.Bid, 1)
+		m.running[1] = &runningAuction{auction: auction, bidChan: bidChan}
+
+		err := m.SubmitBid(1, tt.bid)
+		if tt.invalid {
+			if !errors.Is(err, ErrInvalidBid) {
+				t.Errorf("%s: SubmitBid = %v, want ErrInvalidBid", tt.name, err)
+			}
+			if len(bidChan) != 0 {
+				t.Errorf("%s: invalid bid reached the auction", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: SubmitBid = %v, want nil", tt.name, err)
+			continue
+		}
+		got := <-bidChan
+		if got.Cents != models.ToCents(tt.bid.Amount) || got.MaxBidCents != models.ToCents(tt.bid.MaxBid) {
+			t.Errorf("%s: routed with %d cents and %d max bid cents, want them taken from %v and %v",
+				tt.name, got.Cents, got.MaxBidCents, tt.bid.Amount, tt.bid.MaxBid)
+		}
+	}
+}
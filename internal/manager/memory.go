@@ -0,0 +1,88 @@
+package manager
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"auction-simulator/internal/resource"
+	"auction-simulator/pkg/models"
+)
+
+// memoryPause is how long a report of memory over the limit holds back
+// auction launches: two sample intervals, so launches resume only once a
+// sample in between found memory back under the limit
+const memoryPause = 2 * resource.SampleInterval
+
+// memoryMaxWait caps how long one launch is held back. Memory held by finished
+// auctions' results is never freed, so a limit below it would otherwise stall
+// the run for good.
+const memoryMaxWait = time.Second
+
+// memoryGate holds back new auctions after the resource monitor reports
+// memory over the limit. The limit is soft: auctions already running keep
+// their memory, and launches resume once samples stop exceeding it.
+type memoryGate struct {
+	mu       sync.Mutex
+	until    time.Time
+	triggers int
+	delayed  int
+	paused   time.Duration
+}
+
+// OverMemoryLimit tells the manager that allocated memory exceeded the limit;
+// it is the resource monitor's memory limit callback. New auctions wait until
+// the monitor stops reporting the limit exceeded.
+func (m *Manager) OverMemoryLimit() {
+	m.memory.mu.Lock()
+	defer m.memory.mu.Unlock()
+	m.memory.triggers++
+	m.memory.until = time.Now().Add(memoryPause)
+}
+
+// wait blocks while launches are held back, for at most memoryMaxWait, or
+// until ctx is done
+func (g *memoryGate) wait(ctx context.Context) {
+	start := time.Now()
+	delayed := false
+	for {
+		g.mu.Lock()
+		left := min(time.Until(g.until), memoryMaxWait-time.Since(start))
+		if left <= 0 {
+			if delayed {
+				g.delayed++
+				g.paused += time.Since(start)
+			}
+			g.mu.Unlock()
+			return
+		}
+		g.mu.Unlock()
+
+		delayed = true
+		select {
+		case <-time.After(left):
+		case <-ctx.Done():
+			g.mu.Lock()
+			g.delayed++
+			g.paused += time.Since(start)
+			g.mu.Unlock()
+			return
+		}
+	}
+}
+
+// MemoryLimit reports how often memory exceeded the limit and how long
+// auction launches were held back, or nil if no limit is set
+func (m *Manager) MemoryLimit() *models.MemoryLimitReport {
+	if m.config.Resources.MaxMemoryMB <= 0 {
+		return nil
+	}
+	m.memory.mu.Lock()
+	defer m.memory.mu.Unlock()
+	return &models.MemoryLimitReport{
+		LimitMB:         m.config.Resources.MaxMemoryMB,
+		Triggers:        m.memory.triggers,
+		DelayedAuctions: m.memory.delayed,
+		PausedMs:        float64(m.memory.paused) / float64(time.Millisecond),
+	}
+}
@@ -0,0 +1,35 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"auction-simulator/pkg/models"
+)
+
+// TestMemoryLimitHoldsLaunches reports memory over the limit as the monitor's
+// callback does; the manager must hold the next launch back for about
+// memoryPause and report the trigger and the delayed launch
+func TestMemoryLimitHoldsLaunches(t *testing.T) {
+	m := &Manager{config: models.SimConfig{Resources: models.ResourceConfig{MaxMemoryMB: 100}}}
+
+	// Under the limit, launches go ahead at once
+	start := time.Now()
+	m.memory.wait(context.Background())
+	if waited := time.Since(start); waited > memoryPause/2 {
+		t.Fatalf("launch waited %v with memory under the limit", waited)
+	}
+
+	m.OverMemoryLimit()
+	start = time.Now()
+	m.memory.wait(context.Background())
+	if waited := time.Since(start); waited < memoryPause*9/10 || waited > memoryMaxWait {
+		t.Errorf("launch waited %v after memory exceeded the limit, want about %v", waited, memoryPause)
+	}
+
+	report := m.MemoryLimit()
+	if report.Triggers != 1 || report.DelayedAuctions != 1 || report.PausedMs <= 0 {
+		t.Errorf("report %+v, want 1 trigger and 1 delayed auction", report)
+	}
+}
@@ -0,0 +1,77 @@
+package manager
+
+import (
+	"slices"
+	"time"
+
+	"auction-simulator/pkg/models"
+)
+
+// heldResult is a finished auction waiting for lower IDs to be delivered
+type heldResult struct {
+	auction  *models.Auction
+	received time.Time
+}
+
+// reorderBuffer releases finished auctions in ascending ID order. A result
+// that arrives ahead of a lower ID is held until every lower ID has been
+// delivered or will never arrive.
+type reorderBuffer struct {
+	next    int
+	held    map[int]heldResult
+	gone    func(id int) bool
+	last    int
+	report  models.OrderingReport
+	maxHeld time.Duration
+}
+
+// newReorderBuffer creates a buffer for auctions 1 to last; gone reports IDs
+// that failed or were dropped and so will never arrive
+func newReorderBuffer(last int, gone func(id int) bool) *reorderBuffer {
+	return &reorderBuffer{next: 1, held: make(map[int]heldResult), gone: gone, last: last}
+}
+
+// push adds a finished auction and returns those now ready, in ID order
+func (r *reorderBuffer) push(a *models.Auction) []*models.Auction {
+	r.held[a.ID] = heldResult{auction: a, received: time.Now()}
+
+	var ready []*models.Auction
+	for r.next <= r.last {
+		if h, ok := r.held[r.next]; ok {
+			ready = append(ready, r.release(h))
+		} else if !r.gone(r.next) {
+			break
+		}
+		r.next++
+	}
+	r.report.PeakHeld = max(r.report.PeakHeld, len(r.held))
+	return ready
+}
+
+// flush returns every result still held, in ID order, once no more can arrive
+func (r *reorderBuffer) flush() []*models.Auction {
+	ids := make([]int, 0, len(r.held))
+	for id := range r.held {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	ready := make([]*models.Auction, 0, len(ids))
+	for _, id := range ids {
+		ready = append(ready, r.release(r.held[id]))
+	}
+	return ready
+}
+
+// release removes a held result, noting how long it waited
+func (r *reorderBuffer) release(h heldResult) *models.Auction {
+	delete(r.held, h.auction.ID)
+	r.maxHeld = max(r.maxHeld, time.Since(h.received))
+	return h.auction
+}
+
+// ordering reports the buffer's peak occupancy and longest hold
+func (r *reorderBuffer) ordering() *models.OrderingReport {
+	report := r.report
+	report.MaxHeldMs = float64(r.maxHeld) / float64(time.Millisecond)
+	return &report
+}
@@ -0,0 +1,86 @@
+package manager
+
+import (
+	"slices"
+	"testing"
+
+	"auction-simulator/pkg/models"
+)
+
+// TestReorderBuffer pushes results in completion order and checks they are
+// released strictly in ID order, each as soon as every lower ID is delivered
+// or known never to arrive
+func TestReorderBuffer(t *testing.T) {
+	tests := []struct {
+		name     string
+		last     int
+		gone     []int
+		pushes   []int
+		releases [][]int // Released by each push
+		flushed  []int
+		peakHeld int // Most results held back at once
+	}{
+		{
+			name:     "in order",
+			last:     3,
+			pushes:   []int{1, 2, 3},
+			releases: [][]int{{1}, {2}, {3}},
+			peakHeld: 0,
+		},
+		{
+			name:     "reversed",
+			last:     4,
+			pushes:   []int{4, 3, 2, 1},
+			releases: [][]int{nil, nil, nil, {1, 2, 3, 4}},
+			peakHeld: 3,
+		},
+		{
+			name:     "interleaved",
+			last:     5,
+			pushes:   []int{3, 1, 2, 5, 4},
+			releases: [][]int{nil, {1}, {2, 3}, nil, {4, 5}},
+			peakHeld: 1,
+		},
+		{
+			name:     "failed auction skipped",
+			last:     4,
+			gone:     []int{2},
+			pushes:   []int{3, 4, 1},
+			releases: [][]int{nil, nil, {1, 3, 4}},
+			peakHeld: 2,
+		},
+		{
+			name:     "flushed when a lower ID never arrives",
+			last:     4,
+			pushes:   []int{4, 2, 3},
+			releases: [][]int{nil, nil, nil},
+			flushed:  []int{2, 3, 4},
+			peakHeld: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newReorderBuffer(tt.last, func(id int) bool { return slices.Contains(tt.gone, id) })
+			for i, id := range tt.pushes {
+				if got := ids(r.push(&models.Auction{ID: id})); !slices.Equal(got, tt.releases[i]) {
+					t.Errorf("push %d released %v, want %v", id, got, tt.releases[i])
+				}
+			}
+			if got := ids(r.flush()); !slices.Equal(got, tt.flushed) {
+				t.Errorf("flush released %v, want %v", got, tt.flushed)
+			}
+			if got := r.ordering().PeakHeld; got != tt.peakHeld {
+				t.Errorf("peak held %d, want %d", got, tt.peakHeld)
+			}
+		})
+	}
+}
+
+// ids returns the IDs of auctions, or nil for none
+func ids(auctions []*models.Auction) []int {
+	var out []int
+	for _, a := range auctions {
+		out = append(out, a.ID)
+	}
+	return out
+}
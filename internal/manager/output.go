@@ -1,25 +1,148 @@
 package manager
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"auction-simulator/internal/faults"
 	"auction-simulator/pkg/models"
 )
 
+// DefaultResultNamePattern is the filename template used for per-auction result files
+const DefaultResultNamePattern = "auction_{id}_result.json"
+
 // OutputGenerator handles the generation of output files
 type OutputGenerator struct {
-	outputDir string
+	outputDir    string
+	resultFormat string
+
+	// resultPrefix and resultSuffix surround the ID in result file names
+	resultPrefix string
+	resultSuffix string
+
+	retention       RetentionPolicy
+	retentionReport *models.RetentionReport
+	totalProcessed  int
+
+	// faults injects write failures for resilience testing; nil when disabled
+	faults *faults.Injector
+
+	// lockPath is the lock file held on the output directory, if any
+	lockPath string
+
+	// bidCorrelation adds the cross-auction bid correlation analysis
+	bidCorrelation bool
+	// attributeRegression adds the regression of winning prices on attributes
+	attributeRegression bool
+	// fingerprint adds the run fingerprint
+	fingerprint bool
+	// bidderStats adds per-bidder statistics
+	bidderStats bool
 }
 
 // NewOutputGenerator creates a new output generator
 func NewOutputGenerator(outputDir string) *OutputGenerator {
-	return &OutputGenerator{
-		outputDir: outputDir,
+	og := &OutputGenerator{outputDir: outputDir}
+	og.SetResultNamePattern(DefaultResultNamePattern)
+	return og
+}
+
+// SetResultNamePattern configures the filename template for per-auction result files
+func (og *OutputGenerator) SetResultNamePattern(pattern string) error {
+	prefix, verb, suffix, err := parseResultName(pattern)
+	if err != nil {
+		return err
+	}
+	og.resultFormat = escapeFormat(prefix) + verb + escapeFormat(suffix)
+	og.resultPrefix = prefix
+	og.resultSuffix = suffix
+	return nil
+}
+
+// SetFaultInjector makes output writes subject to injected failures
+func (og *OutputGenerator) SetFaultInjector(injector *faults.Injector) {
+	og.faults = injector
+}
+
+// ResultFilename returns the file name (without directory) used for the given auction
+func (og *OutputGenerator) ResultFilename(auctionID int) string {
+	return fmt.Sprintf(og.resultFormat, auctionID)
+}
+
+// ParseResultNamePattern converts a filename template into a fmt format string.
+// The template must contain exactly one ID placeholder, written either as {id}
+// or with a width such as {id:04d} for zero-padded IDs.
+func ParseResultNamePattern(pattern string) (string, error) {
+	prefix, verb, suffix, err := parseResultName(pattern)
+	if err != nil {
+		return "", err
+	}
+	return escapeFormat(prefix) + verb + escapeFormat(suffix), nil
+}
+
+// parseResultName splits a filename template into the literal text before and
+// after its ID placeholder and the fmt verb that renders the ID
+func parseResultName(pattern string) (prefix, verb, suffix string, err error) {
+	start := strings.Index(pattern, "{id")
+	if start < 0 {
+		return "", "", "", fmt.Errorf("result name pattern %q must contain an {id} placeholder", pattern)
+	}
+	end := strings.Index(pattern[start:], "}")
+	if end < 0 {
+		return "", "", "", fmt.Errorf("result name pattern %q has an unterminated placeholder", pattern)
+	}
+	end += start
+
+	prefix, placeholder, suffix := pattern[:start], pattern[start+1:end], pattern[end+1:]
+	if strings.Contains(suffix, "{id") {
+		return "", "", "", fmt.Errorf("result name pattern %q must contain only one {id} placeholder", pattern)
+	}
+	if strings.ContainsAny(pattern, `/\`) {
+		return "", "", "", fmt.Errorf("result name pattern %q must not contain path separators", pattern)
+	}
+
+	verb = "%d"
+	if placeholder != "id" {
+		spec, ok := strings.CutPrefix(placeholder, "id:")
+		if !ok || !strings.HasSuffix(spec, "d") {
+			return "", "", "", fmt.Errorf("result name pattern %q has invalid placeholder {%s}", pattern, placeholder)
+		}
+		width := strings.TrimSuffix(spec, "d")
+		if _, err := strconv.ParseUint(width, 10, 8); err != nil {
+			return "", "", "", fmt.Errorf("result name pattern %q has invalid width in {%s}", pattern, placeholder)
+		}
+		verb = "%" + width + "d"
+	}
+
+	return prefix, verb, suffix, nil
+}
+
+// escapeFormat escapes literal text for use in a fmt format string
+func escapeFormat(s string) string {
+	return strings.ReplaceAll(s, "%", "%%")
+}
+
+// isResultFilename reports whether name matches the configured result file pattern
+func (og *OutputGenerator) isResultFilename(name string) bool {
+	id, ok := strings.CutPrefix(name, og.resultPrefix)
+	if !ok {
+		return false
+	}
+	id, ok = strings.CutSuffix(id, og.resultSuffix)
+	if !ok || id == "" {
+		return false
 	}
+	_, err := strconv.Atoi(strings.TrimSpace(id))
+	return err == nil
 }
 
 // WriteAuctionResults writes individual auction result files
@@ -30,62 +153,43 @@ func (og *OutputGenerator) WriteAuctionResults(auctions []*models.Auction) error
 	}
 
 	for _, auction := range auctions {
-		filename := filepath.Join(og.outputDir, fmt.Sprintf("auction_%d_result.json", auction.ID))
+		filename := filepath.Join(og.outputDir, og.ResultFilename(auction.ID))
 
 		data, err := json.MarshalIndent(auction, "", "  ")
 		if err != nil {
 			return fmt.Errorf("failed to marshal auction %d: %w", auction.ID, err)
 		}
 
-		if err := os.WriteFile(filename, data, 0644); err != nil {
+		if err := og.writeFile(filename, data); err != nil {
 			return fmt.Errorf("failed to write auction %d result: %w", auction.ID, err)
 		}
 	}
+	og.totalProcessed += len(auctions)
 
-	return nil
+	return og.applyRetention()
 }
 
-// WriteSummary writes the execution summary file
-func (og *OutputGenerator) WriteSummary(
-	auctions []*models.Auction,
-	firstStart, lastEnd time.Time,
-	maxCPUs int,
-	peakMemoryMB float64,
-	avgGoroutines int,
-) error {
-	// Calculate statistics
-	totalBids := 0
-	auctionsWithNoBids := 0
-
-	for _, auction := range auctions {
-		totalBids += auction.TotalBids
-		if auction.TotalBids == 0 {
-			auctionsWithNoBids++
-		}
-	}
-
-	avgBidsPerAuction := 0.0
-	if len(auctions) > 0 {
-		avgBidsPerAuction = float64(totalBids) / float64(len(auctions))
+// writeFile writes an output file, subject to fault injection
+func (og *OutputGenerator) writeFile(filename string, data []byte) error {
+	if err := og.faults.Fail(faults.SiteOutputWrite, faults.NameKey(filepath.Base(filename))); err != nil {
+		return err
 	}
+	return writeFileAtomic(filename, data)
+}
 
-	summary := models.ExecutionSummary{
-		TotalAuctions:        len(auctions),
-		FirstAuctionStart:    firstStart,
-		LastAuctionEnd:       lastEnd,
-		TotalExecutionTimeMs: lastEnd.Sub(firstStart).Milliseconds(),
-		ResourceProfile: models.ResourceProfile{
-			MaxCPUs:       maxCPUs,
-			PeakMemoryMB:  peakMemoryMB,
-			AvgGoroutines: avgGoroutines,
-		},
-		Statistics: models.Statistics{
-			TotalBids:          totalBids,
-			AvgBidsPerAuction:  avgBidsPerAuction,
-			AuctionsWithNoBids: auctionsWithNoBids,
-		},
+// writeFileAtomic writes data to a temporary file and renames it into place so
+// readers and retention never observe a partially written result
+func writeFileAtomic(filename string, data []byte) error {
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
 	}
+	return os.Rename(tmp, filename)
+}
 
+// WriteSummary writes the execution summary file
+func (og *OutputGenerator) WriteSummary(result *models.RunResult) error {
+	summary := og.summary(result)
 	filename := filepath.Join(og.outputDir, "execution_summary.json")
 
 	data, err := json.MarshalIndent(summary, "", "  ")
@@ -93,37 +197,140 @@ func (og *OutputGenerator) WriteSummary(
 		return fmt.Errorf("failed to marshal summary: %w", err)
 	}
 
-	if err := os.WriteFile(filename, data, 0644); err != nil {
+	if err := og.writeFile(filename, data); err != nil {
 		return fmt.Errorf("failed to write summary: %w", err)
 	}
 
 	return nil
 }
 
-// PrintSummary prints a summary to the console
-func (og *OutputGenerator) PrintSummary(
-	auctions []*models.Auction,
-	firstStart, lastEnd time.Time,
-	maxCPUs int,
-	peakMemoryMB float64,
-	avgGoroutines int,
-) {
-	totalBids := 0
-	auctionsWithNoBids := 0
+// summary builds the execution summary, adding what only the output
+// generator knows: retention, injected faults and the optional analyses
+func (og *OutputGenerator) summary(result *models.RunResult) models.ExecutionSummary {
+	summary := buildSummary(result)
+	summary.Retention = og.retentionReport
+	summary.InjectedFaults = og.faults.Counts()
+	if og.bidCorrelation {
+		summary.BidCorrelation = buildBidCorrelation(result.Auctions)
+	}
+	if og.attributeRegression {
+		summary.AttributeRegression = buildAttributeRegression(result.Auctions)
+	}
+	if og.fingerprint {
+		summary.Fingerprint = Fingerprint(result.Auctions)
+	}
+	if og.bidderStats {
+		summary.BidderStats = buildBidderStats(result.Auctions)
+	}
+	return summary
+}
 
-	for _, auction := range auctions {
-		totalBids += auction.TotalBids
-		if auction.TotalBids == 0 {
-			auctionsWithNoBids++
+// WriteBidders writes bidders.json, the profile and random seed of every
+// bidder. The file is a valid bidders file, so passing it back as the
+// population reproduces each bidder's random draws.
+func (og *OutputGenerator) WriteBidders(result *models.RunResult) error {
+	data, err := json.MarshalIndent(result.Bidders, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal bidders: %w", err)
+	}
+
+	filename := filepath.Join(og.outputDir, "bidders.json")
+	if err := og.writeFile(filename, data); err != nil {
+		return fmt.Errorf("failed to write bidders: %w", err)
+	}
+	return nil
+}
+
+// WriteSweepResults writes one CSV row per seed of a seed sweep to sweep_results.csv
+func (og *OutputGenerator) WriteSweepResults(rows []models.SweepResult) error {
+	return og.writeSweepCSV("sweep_results.csv", rows, false)
+}
+
+// WriteSearchResults writes search_results.csv, the rows of a seed search in
+// ranked order with a leading rank column
+func (og *OutputGenerator) WriteSearchResults(rows []models.SweepResult) error {
+	return og.writeSweepCSV("search_results.csv", rows, true)
+}
+
+// writeSweepCSV writes one row of metrics per run, optionally numbering the
+// rows from 1 as ranks
+func (og *OutputGenerator) writeSweepCSV(name string, rows []models.SweepResult, ranked bool) error {
+	if err := os.MkdirAll(og.outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	filename := filepath.Join(og.outputDir, name)
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", name, err)
+	}
+	defer file.Close()
+
+	header := []string{
+		"seed", "total_auctions", "total_bids", "total_revenue",
+		"no_bid_rate", "distinct_winners", "win_concentration", "execution_time_ms",
+	}
+	if ranked {
+		header = append([]string{"rank"}, header...)
+	}
+
+	w := csv.NewWriter(file)
+	w.Write(header)
+	for i, row := range rows {
+		record := []string{
+			strconv.FormatInt(row.Seed, 10),
+			strconv.Itoa(row.TotalAuctions),
+			strconv.Itoa(row.TotalBids),
+			strconv.FormatFloat(row.TotalRevenue, 'f', 2, 64),
+			strconv.FormatFloat(row.NoBidRate, 'f', 4, 64),
+			strconv.Itoa(row.DistinctWinners),
+			strconv.FormatFloat(row.WinConcentration, 'f', 4, 64),
+			strconv.FormatInt(row.ExecutionTimeMs, 10),
+		}
+		if ranked {
+			record = append([]string{strconv.Itoa(i + 1)}, record...)
 		}
+		w.Write(record)
 	}
+	w.Flush()
 
-	avgBidsPerAuction := 0.0
-	if len(auctions) > 0 {
-		avgBidsPerAuction = float64(totalBids) / float64(len(auctions))
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// PrintSweepSummary prints the range of each metric across a seed sweep
+func (og *OutputGenerator) PrintSweepSummary(rows []models.SweepResult) {
+	if len(rows) == 0 {
+		return
 	}
 
-	executionTime := lastEnd.Sub(firstStart)
+	metric := func(name string, value func(models.SweepResult) float64) {
+		lo, hi := value(rows[0]), value(rows[0])
+		for _, row := range rows[1:] {
+			lo = min(lo, value(row))
+			hi = max(hi, value(row))
+		}
+		fmt.Printf("  %-22s min %12.4f   max %12.4f   range %12.4f\n", name+":", lo, hi, hi-lo)
+	}
+
+	fmt.Printf("\nSeed Sweep (%d runs):\n", len(rows))
+	metric("Total Bids", func(r models.SweepResult) float64 { return float64(r.TotalBids) })
+	metric("Total Revenue", func(r models.SweepResult) float64 { return r.TotalRevenue })
+	metric("No-Bid Rate", func(r models.SweepResult) float64 { return r.NoBidRate })
+	metric("Distinct Winners", func(r models.SweepResult) float64 { return float64(r.DistinctWinners) })
+	metric("Win Concentration", func(r models.SweepResult) float64 { return r.WinConcentration })
+}
+
+// PrintSummary prints a summary to the console
+func (og *OutputGenerator) PrintSummary(result *models.RunResult) {
+	summary := buildSummary(result)
+	stats := summary.Statistics
+	phases := summary.AvgPhaseTimings
+	unit := summary.Config.DurationUnit
+	profile := summary.ResourceProfile
+	executionTime := result.LastEnd.Sub(result.FirstStart)
 
 	fmt.Println()
 	for range 60 {
@@ -136,23 +343,724 @@ func (og *OutputGenerator) PrintSummary(
 	}
 	fmt.Println()
 
-	fmt.Printf("\nTotal Auctions:           %d\n", len(auctions))
-	fmt.Printf("Total Execution Time:     %v (%.2f seconds)\n", executionTime, executionTime.Seconds())
-	fmt.Printf("First Auction Start:      %s\n", firstStart.Format(time.RFC3339))
-	fmt.Printf("Last Auction End:         %s\n", lastEnd.Format(time.RFC3339))
+	fmt.Printf("\nTotal Auctions:           %d\n", summary.TotalAuctions)
+	fmt.Printf("Run Status:               %s", summary.Status.Outcome)
+	if summary.Status.Detail != "" {
+		fmt.Printf(" (%s)", summary.Status.Detail)
+	}
+	fmt.Println()
+	if summary.Config.ShuffleBidders {
+		fmt.Println("Bidder Order:             shuffled per auction (seeded)")
+	} else {
+		fmt.Println("Bidder Order:             bidder ID")
+	}
+	if summary.TotalAuctions == 0 {
+		// There is no first start or last end to measure between
+		fmt.Println("Total Execution Time:     n/a (no auctions completed)")
+	} else {
+		fmt.Printf("Total Execution Time:     %v (%.2f seconds)\n", executionTime, executionTime.Seconds())
+		fmt.Printf("First Auction Start:      %s\n", summary.FirstAuctionStart.Format(time.RFC3339))
+		fmt.Printf("Last Auction End:         %s\n", summary.LastAuctionEnd.Format(time.RFC3339))
+	}
 
 	fmt.Println("\nBid Statistics:")
-	fmt.Printf("  Total Bids:             %d\n", totalBids)
-	fmt.Printf("  Avg Bids per Auction:   %.2f\n", avgBidsPerAuction)
-	fmt.Printf("  Auctions with No Bids:  %d\n", auctionsWithNoBids)
+	fmt.Printf("  Total Bids:             %d\n", stats.TotalBids)
+	fmt.Printf("  Avg Bids per Auction:   %.2f\n", stats.AvgBidsPerAuction)
+	fmt.Printf("  Auctions with No Bids:  %d\n", stats.AuctionsWithNoBids)
+	if summary.Config.ReserveMax > 0 {
+		fmt.Printf("  Reserve Not Met:        %d (reserve %.2f-%.2f)\n", stats.AuctionsReserveNotMet, summary.Config.ReserveMin, summary.Config.ReserveMax)
+	}
+	if summary.Config.DutchStart > 0 {
+		fmt.Printf("  Dutch Clock Sales:      %d, at tick %.1f of %d on average (%.2f down to %.2f)\n",
+			stats.DutchSold, stats.AvgDutchSaleTick, summary.Config.DutchTicks, summary.Config.DutchStart, summary.Config.DutchFloor)
+	}
+	fmt.Printf("  Avg HHI:                %.4f\n", stats.AvgHHI)
+	fmt.Printf("  Late Bids:              %d\n", stats.LateBids)
+	if clamped := summary.ClampedBids; clamped != nil {
+		fmt.Printf("  Clamped to Min/Max:     %d / %d\n", clamped.AtMin, clamped.AtMax)
+	}
+	if slow := summary.SlowBidders; slow != nil {
+		fmt.Printf("  Abandoned (slow):       %d from %d bidders (timeout %v)\n",
+			slow.AbandonedBids, len(slow.Bidders), summary.Config.BidderTimeout)
+	}
+	if at := summary.Attention; at != nil {
+		fmt.Printf("  Declined (attention):   %d saturated, %d cooling down, from %d bidders (%.1f%%)\n",
+			at.Saturated, at.CoolingDown, len(at.Bidders), at.DeclineRate*100)
+	}
+	if summary.Config.HammerGrace > 0 {
+		fmt.Printf("  Grace Extensions:       %d in %d auctions (grace %v, max %d)\n",
+			stats.GraceExtensions, stats.AuctionsExtended, summary.Config.HammerGrace, summary.Config.HammerMaxExtensions)
+	}
+	if summary.Config.MinDuration > 0 {
+		fmt.Printf("  Min Duration Holds:     %d (min %v)\n", stats.MinDurationFloorHits, summary.Config.MinDuration)
+	}
+	if summary.Config.RevealWindow > 0 {
+		fmt.Printf("  Commitments:            %d (%d unrevealed, %d invalid; reveal window %v)\n",
+			stats.Commitments, stats.UnrevealedCommits, stats.InvalidReveals, summary.Config.RevealWindow)
+	}
+	switch {
+	case summary.Config.Acceptance != models.AcceptAll && summary.Config.MinIncrement > 0:
+		fmt.Printf("  Rejected Bids:          %d (%s, min increment %.2f)\n", stats.RejectedBids, summary.Config.Acceptance, summary.Config.MinIncrement)
+	case summary.Config.Acceptance != models.AcceptAll:
+		fmt.Printf("  Rejected Bids:          %d (%s)\n", stats.RejectedBids, summary.Config.Acceptance)
+	case summary.Config.MinIncrement > 0:
+		fmt.Printf("  Rejected Bids:          %d (min increment %.2f)\n", stats.RejectedBids, summary.Config.MinIncrement)
+	}
+	if summary.Config.ProxyRate > 0 {
+		fmt.Printf("  Proxy Bids:             %d, winning %d auctions (rate %.2f)\n", stats.ProxyBids, stats.ProxyWins, summary.Config.ProxyRate)
+	}
+	if summary.Config.CoalesceWindow > 0 {
+		fmt.Printf("  Merged Bids:            %d (window %v, keep %s)\n", stats.MergedBids, summary.Config.CoalesceWindow, summary.Config.CoalesceKeep)
+	}
+	if summary.Config.MaxStoredBids > 0 {
+		fmt.Printf("  Discarded Bids:         %d (kept top %d per auction)\n", stats.DiscardedBids, summary.Config.MaxStoredBids)
+	}
+	fmt.Printf("  Total Revenue:          %.2f (%s)\n", stats.TotalRevenue, summary.Config.Pricing)
+	if summary.Config.Fee != nil {
+		fmt.Printf("  Fees / Net Revenue:     %.2f / %.2f\n", stats.TotalFees, stats.NetRevenue)
+	}
+	if summary.Config.Units > 1 {
+		fmt.Printf("  Units Sold:             %d of %d (%d per auction)\n", stats.UnitsSold, summary.Config.Units*summary.TotalAuctions, summary.Config.Units)
+		fmt.Printf("  Avg Clearing Price:     %.2f\n", stats.AvgClearingPrice)
+	}
+	if summary.Config.Pricing == models.PricingAllPay {
+		fmt.Printf("  Avg Bidder Loss:        %.2f\n", stats.AvgBidderLoss)
+	}
+	if summary.Config.Pricing == models.PricingSecondPrice {
+		fmt.Printf("  Avg Winner Discount:    %.2f\n", stats.AvgWinnerDiscount)
+	}
+	fmt.Printf("  Avg Revisions/Bidder:   %.3f\n", summary.Revisions.AvgRevisionsPerBidder)
+	fmt.Printf("  Avg Increase/Revision:  %.2f\n", summary.Revisions.AvgIncreasePerRevision)
+
+	if timing := summary.BidTiming; timing != nil {
+		fmt.Println("\nBid Timing (after each auction's first bid):")
+		fmt.Printf("  Following Bids:         %d\n", timing.Bids)
+		fmt.Printf("  Mean Delta:             %s\n", unit.FormatMs(timing.MeanMs))
+		fmt.Printf("  Delta p50/p95/p99:      %s / %s / %s\n", unit.FormatMs(timing.PercentilesMs.P50),
+			unit.FormatMs(timing.PercentilesMs.P95), unit.FormatMs(timing.PercentilesMs.P99))
+		for _, w := range timing.Within {
+			fmt.Printf("  Within %-16s %.1f%%\n", fmt.Sprintf("%dms:", w.WindowMs), w.Share*100)
+		}
+	}
+
+	if arrivals := summary.Arrivals; arrivals != nil {
+		fmt.Println("\nBidder Arrivals (after each auction opened):")
+		fmt.Printf("  Arrivals:               %d (mean %s, p50 %s)\n", arrivals.Arrivals,
+			unit.FormatMs(arrivals.MeanArrivalMs), unit.FormatMs(arrivals.ArrivalMs.P50))
+		fmt.Printf("  Early Departures:       %d (p50 %s)\n", arrivals.Departures, unit.FormatMs(arrivals.DepartureMs.P50))
+		fmt.Printf("  Left Before Bidding:    %d\n", arrivals.LeftEarly)
+		fmt.Printf("  Mean Stay:              %s\n", unit.FormatMs(arrivals.MeanStayMs))
+	}
+
+	if burst := summary.Burst; burst != nil {
+		fmt.Printf("\nBid Bursts (%d x %d bids per bidder):\n", burst.Count, burst.Size)
+		fmt.Printf("  Sent / Dropped:         %d / %d\n", burst.Sent, burst.Dropped)
+		fmt.Printf("  Peak Bid Rate p50/p95:  %.0f / %.0f bids/s\n", burst.PeakBidRate.P50, burst.PeakBidRate.P95)
+		fmt.Printf("  Max Peak Bid Rate:      %.0f bids/s\n", burst.MaxBidRate)
+	}
+
+	if limit := summary.BidLimit; limit != nil {
+		fmt.Printf("\nBid Limit (%d bids):\n", limit.Limit)
+		fmt.Printf("  Admitted / Refused:     %d / %d\n", limit.Bids, limit.Refused)
+		fmt.Printf("  Reached:                %t\n", limit.Reached)
+		if len(limit.CutShort) > 0 {
+			fmt.Printf("  Cut Short:              %d auctions %v\n", len(limit.CutShort), limit.CutShort)
+		}
+	}
+
+	fmt.Println("\nParticipation (configured vs realized rate):")
+	fmt.Printf("  Mean Abs Deviation:     %.4f\n", summary.Participation.MeanAbsDeviation)
+	fmt.Printf("  Max Abs Deviation:      %.4f (bidder %d)\n", summary.Participation.MaxAbsDeviation, summary.Participation.MaxDeviationID)
+
+	fmt.Println("\nAvg Phase Timings:")
+	fmt.Printf("  Attribute Generation:   %s\n", unit.FormatMs(phases.AttributeGenerationMs))
+	fmt.Printf("  Bidder Notification:    %s\n", unit.FormatMs(phases.BidderNotificationMs))
+	fmt.Printf("  Bid Collection:         %s\n", unit.FormatMs(phases.BidCollectionMs))
+	if phases.RevealMs > 0 {
+		fmt.Printf("  Reveal:                 %s\n", unit.FormatMs(phases.RevealMs))
+	}
+	fmt.Printf("  Winner Determination:   %s\n", unit.FormatMs(phases.WinnerDeterminationMs))
 
 	fmt.Println("\nResource Usage:")
-	fmt.Printf("  Max CPUs:               %d\n", maxCPUs)
-	fmt.Printf("  Peak Memory:            %.2f MB\n", peakMemoryMB)
-	fmt.Printf("  Avg Goroutines:         %d\n", avgGoroutines)
+	fmt.Printf("  Requested CPUs:         %d\n", profile.RequestedCPUs)
+	fmt.Printf("  Max CPUs:               %d\n", profile.MaxCPUs)
+	fmt.Printf("  CPU Utilization:        %.1f%% avg, %.1f%% peak\n", profile.AvgCPUPercent, profile.PeakCPUPercent)
+	fmt.Printf("  Peak Memory:            %.2f MB\n", profile.PeakMemoryMB)
+	fmt.Printf("  Avg Goroutines:         %d\n", profile.AvgGoroutines)
+	fmt.Printf("  Peak Bid Goroutines:    %d\n", profile.PeakBidGoroutines)
+	fmt.Printf("  Peak Active Auctions:   %d\n", profile.PeakActiveAuctions)
+	fmt.Printf("  Memory p50/p95/p99:     %.2f / %.2f / %.2f MB\n",
+		profile.MemoryPercentilesMB.P50, profile.MemoryPercentilesMB.P95, profile.MemoryPercentilesMB.P99)
+	fmt.Printf("  Goroutines p50/p95/p99: %.0f / %.0f / %.0f\n",
+		profile.GoroutinePercentiles.P50, profile.GoroutinePercentiles.P95, profile.GoroutinePercentiles.P99)
+	if quota := profile.CPUQuota; quota != nil {
+		fmt.Printf("  CPU Quota / Achieved:   %.2f / %.2f cores (throttled %s)\n",
+			quota.Quota, quota.AchievedCores, unit.FormatMs(quota.ThrottledMs))
+	}
+	if limit := profile.MemoryLimit; limit != nil {
+		fmt.Printf("  Memory Limit:           %d MB, exceeded in %d samples (%d auctions delayed %s)\n",
+			limit.LimitMB, limit.Triggers, limit.DelayedAuctions, unit.FormatMs(limit.PausedMs))
+	}
+
+	if len(summary.Groups) > 0 {
+		fmt.Println("\nAffiliation Groups:")
+		for _, g := range summary.Groups {
+			fmt.Printf("  Group %-3d %3d bidders  avg bid %9.2f  wins %3d (%.1f%%)\n",
+				g.GroupID, g.Bidders, g.AvgBid, g.Wins, g.WinRate*100)
+		}
+	}
+
+	if len(summary.AttributeTemplates) > 0 {
+		fmt.Printf("\nAttribute Templates (%s):\n", summary.Config.AttributeMode)
+		for _, use := range summary.AttributeTemplates {
+			fmt.Printf("  Template %-3d %3d auctions\n", use.Template, len(use.AuctionIDs))
+		}
+	}
+
+	if len(summary.Strategies) > 1 {
+		fmt.Println("\nStrategies:")
+		for _, s := range summary.Strategies {
+			fmt.Printf("  %-12s %3d bidders  avg bid %9.2f  wins %3d (%.1f%%)  spend %10.2f  surplus %10.2f\n",
+				s.Strategy, s.Bidders, s.AvgBid, s.Wins, s.WinRate*100, s.TotalSpend, s.Surplus)
+		}
+	}
+
+	if c := summary.WinnersCurse; c != nil {
+		fmt.Printf("\nWinner's Curse (%d common-value auctions, noise ±%.0f%%):\n", c.Auctions, summary.Config.CommonValueNoise*100)
+		fmt.Printf("  Overpaid Auctions:      %d (%.1f%%)\n", c.Overpaid, c.OverpaidRate*100)
+		fmt.Printf("  Avg Overpayment:        %.2f\n", c.AvgOverpayment)
+		fmt.Printf("  Avg Winner Margin:      %.2f\n", c.AvgMargin)
+	}
+
+	if w := summary.Welfare; w != nil {
+		fmt.Printf("\nWelfare (%d auctions):\n", w.Auctions)
+		fmt.Printf("  Optimal / Achieved:     %.2f / %.2f\n", w.OptimalWelfare, w.AchievedWelfare)
+		fmt.Printf("  Price of Anarchy:       %.4f\n", w.PriceOfAnarchy)
+		fmt.Printf("  Efficiency Loss:        %.2f%%\n", w.EfficiencyLoss*100)
+		fmt.Printf("  Misallocated Auctions:  %d\n", w.Misallocated)
+	}
+
+	if a := summary.Aggressiveness; a != nil {
+		fmt.Printf("\nAggressiveness (%d bidders, correlation with win rate %+.3f):\n", a.Bidders, a.Correlation)
+		for _, b := range a.Bands {
+			fmt.Printf("  %.3f-%.3f  %3d bidders  avg bid %9.2f  wins %3d/%-4d (%.1f%%)\n",
+				b.Min, b.Max, b.Bidders, b.AvgBid, b.Wins, b.Entries, b.WinRate*100)
+		}
+	}
+
+	if info := summary.Information; info != nil {
+		fmt.Printf("\nInformation (%d bidders, correlation with win rate %+.3f, with surplus %+.3f):\n",
+			info.Bidders, info.WinRateCorrelation, info.SurplusCorrelation)
+		for _, l := range info.Levels {
+			fmt.Printf("  %2d visible  %3d bidders  wins %3d/%-4d (%.1f%%)  surplus/entry %9.2f\n",
+				l.Visible, l.Bidders, l.Wins, l.Entries, l.WinRate*100, l.SurplusPerEntry)
+		}
+	}
+
+	if bu := summary.Bundles; bu != nil {
+		fmt.Printf("\nCombinatorial Bundles (%d auctions of %d items):\n", bu.Auctions, bu.Items)
+		fmt.Printf("  Items Sold:             %d of %d\n", bu.ItemsSold, bu.ItemsOffered)
+		fmt.Printf("  Avg Winners/Auction:    %.2f\n", bu.AvgWinners)
+		fmt.Printf("  Whole-Set Wins:         %d\n", bu.WholeSetWins)
+		fmt.Printf("  Cleared Value:          %.2f\n", bu.ClearedValue)
+		fmt.Printf("  Whole-Set Value:        %.2f (%+.2f%% from splitting)\n", bu.WholeSetValue, bu.Gain*100)
+	}
+
+	if og.retentionReport != nil {
+		fmt.Println("\nRetention:")
+		fmt.Printf("  Retained Files:         %d\n", og.retentionReport.RetainedFiles)
+		fmt.Printf("  Removed Files:          %d\n", og.retentionReport.RemovedFiles)
+		fmt.Printf("  Total Processed:        %d\n", og.retentionReport.TotalProcessed)
+	}
+
+	if trend := summary.AttributeTrend; trend != nil {
+		fmt.Println("\nAttribute Trend (feedback mode):")
+		fmt.Printf("  First Auction Mean:     %.4f\n", trend.FirstMean)
+		fmt.Printf("  Last Auction Mean:      %.4f\n", trend.LastMean)
+		fmt.Printf("  Slope per Auction:      %+.5f\n", trend.SlopePerAuction)
+	}
+
+	if o := summary.Ordering; o != nil {
+		fmt.Println("\nOrdered Delivery:")
+		fmt.Printf("  Peak Results Held:      %d\n", o.PeakHeld)
+		fmt.Printf("  Longest Hold:           %s\n", unit.FormatMs(o.MaxHeldMs))
+	}
+
+	if len(summary.StuckAuctions) > 0 {
+		fmt.Printf("\nStuck Auctions:           %v\n", summary.StuckAuctions)
+	}
+	if len(summary.FailedAuctions) > 0 {
+		fmt.Printf("\nFailed Auctions:          %v\n", summary.FailedAuctions)
+	}
+
+	if og.faults != nil {
+		fmt.Println("\nInjected Faults:")
+		counts := og.faults.Counts()
+		for _, site := range og.faults.Sites() {
+			fmt.Printf("  %-22s  %d\n", site+":", counts[site])
+		}
+	}
+
+	if summary.Webhook != nil {
+		fmt.Println("\nWebhook:")
+		fmt.Printf("  Delivered:              %d\n", summary.Webhook.Delivered)
+		fmt.Printf("  Failed:                 %d\n", summary.Webhook.Failed)
+	}
+
+	if k := summary.Kafka; k != nil {
+		fmt.Println("\nKafka:")
+		fmt.Printf("  Delivered:              %d\n", k.Delivered)
+		fmt.Printf("  Failed:                 %d\n", k.Failed)
+		fmt.Printf("  Retries:                %d\n", k.Retries)
+		fmt.Printf("  Blocked Sends:          %d\n", k.Blocked)
+	}
+
+	if og.bidCorrelation {
+		correlation := buildBidCorrelation(result.Auctions)
+		consistency := 0.0
+		for _, b := range correlation.Bidders {
+			consistency += b.ZScoreStdDev
+		}
+		if len(correlation.Bidders) > 0 {
+			consistency /= float64(len(correlation.Bidders))
+		}
+		fmt.Println("\nBid Correlation:")
+		fmt.Printf("  Mean Pairwise:          %.4f (%d auction pairs)\n", correlation.MeanPairwiseCorrelation, correlation.AuctionPairs)
+		fmt.Printf("  Avg Z-Score Spread:     %.4f (%d bidders)\n", consistency, len(correlation.Bidders))
+	}
+
+	if og.bidderStats {
+		stats := buildBidderStats(result.Auctions)
+		fmt.Printf("\nTop Bidders (%d bidders):\n", len(stats))
+		for _, s := range stats[:min(5, len(stats))] {
+			fmt.Printf("  Bidder %-5d %3d wins in %3d auctions (%.1f%%)  %4d bids  spent %10.2f\n",
+				s.BidderID, s.Wins, s.Auctions, s.WinRate*100, s.Bids, s.TotalSpent)
+		}
+	}
+
+	if og.attributeRegression {
+		if regression := buildAttributeRegression(result.Auctions); regression != nil {
+			fmt.Printf("\nAttribute Regression (%d auctions with a winner):\n", regression.Auctions)
+			if regression.Fitted {
+				fmt.Printf("  Intercept:              %.2f\n", regression.Intercept)
+				fmt.Printf("  R²:                     %.4f\n", regression.RSquared)
+			} else {
+				fmt.Println("  Fit:                    n/a (too few auctions)")
+			}
+			effects := slices.Clone(regression.Attributes)
+			sort.Slice(effects, func(i, j int) bool { return math.Abs(effects[i].Correlation) > math.Abs(effects[j].Correlation) })
+			for _, e := range effects[:min(3, len(effects))] {
+				fmt.Printf("  %-24sr=%.4f, coef=%.2f\n", fmt.Sprintf("Attribute %d:", e.Attribute), e.Correlation, e.Coefficient)
+			}
+		}
+	}
+
+	if bp := summary.Backpressure; bp != nil {
+		fmt.Printf("\nResult Backpressure (%s, buffer %d):\n", summary.Config.Backpressure, bp.BufferSize)
+		fmt.Printf("  Blocked Sends:          %d\n", bp.Blocked)
+		fmt.Printf("  Dropped Auctions:       %d\n", len(bp.Dropped))
+		fmt.Printf("  Rejected Auctions:      %d\n", len(bp.Rejected))
+	}
+
+	fmt.Println("\nShutdown:")
+	fmt.Printf("  Drain Timeout:          %d ms\n", summary.Shutdown.DrainTimeoutMs)
+	fmt.Printf("  Pending Bid Goroutines: %d\n", summary.Shutdown.PendingBidGoroutines)
+
+	if og.fingerprint {
+		fmt.Printf("\nRun Fingerprint:          %s\n", Fingerprint(result.Auctions).Root)
+	}
 
 	for range 60 {
 		fmt.Print("=")
 	}
 	fmt.Println()
 }
+
+// buildSummary assembles the execution summary for a completed run
+func buildSummary(result *models.RunResult) models.ExecutionSummary {
+	stats := buildStatistics(result.Auctions)
+	stats.LateBids = result.LateBids
+
+	return models.ExecutionSummary{
+		Status:               result.Status,
+		TotalAuctions:        len(result.Auctions),
+		FirstAuctionStart:    result.FirstStart,
+		LastAuctionEnd:       result.LastEnd,
+		TotalExecutionTimeMs: result.LastEnd.Sub(result.FirstStart).Milliseconds(),
+		TotalExecutionTimeNs: result.LastEnd.Sub(result.FirstStart).Nanoseconds(),
+		ResourceProfile:      result.ResourceProfile,
+		Statistics:           stats,
+		AvgPhaseTimings:      averagePhaseTimings(result.Auctions),
+		Shutdown:             result.Shutdown,
+		Config:               result.Config,
+		Groups:               buildGroupStats(result.Auctions),
+		Strategies:           buildStrategyStats(result.Auctions),
+		Welfare:              buildWelfare(result.Auctions),
+		WinnersCurse:         buildWinnersCurse(result.Auctions),
+		Aggressiveness:       buildAggressiveness(result.Bidders, result.Auctions),
+		Information:          buildInformation(result.Bidders, result.Auctions),
+		Bundles:              buildBundles(result.Auctions),
+		AttributeTemplates:   buildAttributeTemplates(result.Auctions),
+		FailedAuctions:       result.FailedAuctions,
+		AttributeTrend:       buildAttributeTrend(result),
+		Revisions:            buildRevisionStats(result.Auctions),
+		Webhook:              result.Webhook,
+		Kafka:                result.Kafka,
+		Participation:        result.Participation,
+		ClampedBids:          result.ClampedBids,
+		SlowBidders:          result.SlowBidders,
+		Attention:            result.Attention,
+		Backpressure:         result.Backpressure,
+		StuckAuctions:        result.StuckAuctions,
+		Ordering:             result.Ordering,
+		BidTiming:            buildBidTiming(result.Auctions),
+		Arrivals:             buildArrivals(result.Auctions),
+		Burst:                buildBurst(result.Burst, result.Auctions),
+		BidLimit:             buildBidLimit(result.BidLimit, result.Auctions),
+	}
+}
+
+// buildRevisionStats summarizes bid trajectories across every bidder that took
+// part in each auction
+func buildRevisionStats(auctions []*models.Auction) models.RevisionStats {
+	var stats models.RevisionStats
+	participants, revisions := 0, 0
+	totalIncrease := 0.0
+
+	for _, auction := range auctions {
+		seen := make(map[int]bool)
+		for _, bid := range auction.Bids {
+			if seen[bid.BidderID] {
+				continue
+			}
+			seen[bid.BidderID] = true
+			participants++
+
+			trajectory := models.BidderTrajectory(auction, bid.BidderID)
+			if len(trajectory) > 1 {
+				stats.BiddersWithRevisions++
+			}
+			for i := 1; i < len(trajectory); i++ {
+				revisions++
+				totalIncrease += trajectory[i].Amount - trajectory[i-1].Amount
+			}
+		}
+	}
+
+	if participants > 0 {
+		stats.AvgRevisionsPerBidder = float64(revisions) / float64(participants)
+	}
+	if revisions > 0 {
+		stats.AvgIncreasePerRevision = totalIncrease / float64(revisions)
+	}
+	return stats
+}
+
+// buildAttributeTrend fits a least-squares line through each auction's mean
+// attribute value, in auction ID order. It is only reported in feedback mode.
+func buildAttributeTrend(result *models.RunResult) *models.AttributeTrend {
+	if result.Config.FeedbackStrength <= 0 || len(result.Auctions) == 0 {
+		return nil
+	}
+
+	ordered := append([]*models.Auction(nil), result.Auctions...)
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].ID < ordered[j].ID })
+
+	means := make([]float64, len(ordered))
+	for i, a := range ordered {
+		for _, v := range a.Attributes {
+			means[i] += v
+		}
+		means[i] /= float64(len(a.Attributes))
+	}
+
+	n := float64(len(means))
+	var sumX, sumY, sumXY, sumXX float64
+	for i, y := range means {
+		x := float64(i)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+
+	trend := &models.AttributeTrend{
+		FirstMean: means[0],
+		LastMean:  means[len(means)-1],
+	}
+	if denom := n*sumXX - sumX*sumX; denom != 0 {
+		trend.SlopePerAuction = (n*sumXY - sumX*sumY) / denom
+	}
+	return trend
+}
+
+// buildGroupStats calculates bid and win statistics per affiliation group.
+// Win rate is the share of auctions with a winner that the group won.
+func buildGroupStats(auctions []*models.Auction) []models.GroupStats {
+	byGroup := make(map[int]*models.GroupStats)
+	members := make(map[int]map[int]bool)
+	totalBid := make(map[int]float64)
+	auctionsWithWinner := 0
+
+	for _, auction := range auctions {
+		for _, bid := range auction.Bids {
+			if bid.GroupID == 0 {
+				continue
+			}
+			gs, ok := byGroup[bid.GroupID]
+			if !ok {
+				gs = &models.GroupStats{GroupID: bid.GroupID}
+				byGroup[bid.GroupID] = gs
+				members[bid.GroupID] = make(map[int]bool)
+			}
+			gs.Bids++
+			totalBid[bid.GroupID] += bid.Amount
+			members[bid.GroupID][bid.BidderID] = true
+		}
+		if auction.Winner != nil {
+			auctionsWithWinner++
+			if gs, ok := byGroup[auction.Winner.GroupID]; ok {
+				gs.Wins++
+			}
+		}
+	}
+
+	stats := make([]models.GroupStats, 0, len(byGroup))
+	for id, gs := range byGroup {
+		gs.Bidders = len(members[id])
+		gs.AvgBid = totalBid[id] / float64(gs.Bids)
+		if auctionsWithWinner > 0 {
+			gs.WinRate = float64(gs.Wins) / float64(auctionsWithWinner)
+		}
+		stats = append(stats, *gs)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].GroupID < stats[j].GroupID })
+	return stats
+}
+
+// buildBidLimit completes the bid limit counts with the auctions cancelled
+// once the limit was reached, or returns nil if the run had no limit
+func buildBidLimit(counts *models.BidLimitReport, auctions []*models.Auction) *models.BidLimitReport {
+	if counts == nil {
+		return nil
+	}
+	report := *counts
+	if report.Reached {
+		for _, a := range auctions {
+			if a.Status == models.StatusCancelled {
+				report.CutShort = append(report.CutShort, a.ID)
+			}
+		}
+		sort.Ints(report.CutShort)
+	}
+	return &report
+}
+
+// buildAttributeTemplates lists each shared attribute vector with the auctions
+// that used it, in template order. It returns nil when every auction drew its
+// own attributes.
+func buildAttributeTemplates(auctions []*models.Auction) []models.AttributeTemplateUse {
+	byTemplate := make(map[int]*models.AttributeTemplateUse)
+	for _, auction := range auctions {
+		if auction.AttributeTemplate == 0 {
+			continue
+		}
+		use, ok := byTemplate[auction.AttributeTemplate]
+		if !ok {
+			use = &models.AttributeTemplateUse{Template: auction.AttributeTemplate, Attributes: auction.Attributes}
+			byTemplate[auction.AttributeTemplate] = use
+		}
+		use.AuctionIDs = append(use.AuctionIDs, auction.ID)
+	}
+	if len(byTemplate) == 0 {
+		return nil
+	}
+
+	uses := make([]models.AttributeTemplateUse, 0, len(byTemplate))
+	for _, use := range byTemplate {
+		sort.Ints(use.AuctionIDs)
+		uses = append(uses, *use)
+	}
+	sort.Slice(uses, func(i, j int) bool { return uses[i].Template < uses[j].Template })
+	return uses
+}
+
+// buildStatistics calculates aggregate bid statistics across all auctions
+func buildStatistics(auctions []*models.Auction) models.Statistics {
+	totalBids := 0
+	auctionsWithNoBids := 0
+	reserveNotMet := 0
+	totalHHI := 0.0
+	merged, rejected, discarded := 0, 0, 0
+	commitments, unrevealed, invalid := 0, 0, 0
+	floorHits := 0
+	graceExtensions, auctionsExtended := 0, 0
+	unitsSold, cleared := 0, 0
+	clearingTotal := 0.0
+	dutchSold, dutchTicks := 0, 0
+	proxyBids, proxyWins := 0, 0
+	revenue, fees := 0.0, 0.0
+	var revenueCents, feesCents int64
+	integerAmounts := false
+
+	// Under all-pay pricing, whatever the winner did not pay was lost by the
+	// other participants
+	lossTotal := 0.0
+	losers := 0
+
+	// Under second-price pricing, winners keep the gap to the runner-up
+	discountTotal := 0.0
+	sold := 0
+
+	for _, auction := range auctions {
+		totalBids += auction.TotalBids
+		totalHHI += auction.HHI
+		merged += auction.MergedBids
+		rejected += auction.RejectedBids
+		if auction.Discarded != nil {
+			discarded += auction.Discarded.Count
+		}
+		commitments += len(auction.Commitments)
+		unrevealed += auction.UnrevealedCommits
+		invalid += auction.InvalidReveals
+		if auction.MinDurationApplied {
+			floorHits++
+		}
+		if auction.GraceExtensions > 0 {
+			graceExtensions += auction.GraceExtensions
+			auctionsExtended++
+		}
+		for _, bid := range auction.Bids {
+			if bid.MaxBid > 0 {
+				proxyBids++
+			}
+		}
+		if auction.Winner != nil && auction.Winner.MaxBid > 0 {
+			proxyWins++
+		}
+		if auction.Dutch != nil && auction.Dutch.Sold {
+			dutchSold++
+			dutchTicks += auction.Dutch.SoldAtTick
+		}
+		revenue += auction.TotalPaid
+		revenueCents += auction.TotalPaidCents
+		fees += auction.FeesPaid
+		feesCents += auction.FeesPaidCents
+		integerAmounts = integerAmounts || auction.IntegerAmounts
+		if auction.TotalBids == 0 {
+			auctionsWithNoBids++
+		} else if auction.ReservePrice > 0 && !auction.ReserveMet {
+			reserveNotMet++
+		}
+		if len(auction.Allocations) > 0 {
+			for _, alloc := range auction.Allocations {
+				unitsSold += alloc.Units
+			}
+			clearingTotal += auction.ClearingPrice
+			cleared++
+		}
+
+		if auction.Pricing == models.PricingAllPay && auction.Winner != nil {
+			bidders := make(map[int]bool)
+			for _, bid := range auction.Bids {
+				bidders[bid.BidderID] = true
+			}
+			losers += len(bidders) - 1
+			lossTotal += auction.TotalPaid - auction.Winner.Amount
+		}
+		if auction.Pricing == models.PricingSecondPrice && auction.Winner != nil {
+			discountTotal += auction.Winner.Amount - auction.WinningPrice
+			sold++
+		}
+	}
+
+	netRevenue := revenue - fees
+	if integerAmounts {
+		revenue = models.FromCents(revenueCents)
+		fees = models.FromCents(feesCents)
+		netRevenue = models.FromCents(revenueCents - feesCents)
+	}
+
+	avgBidsPerAuction := 0.0
+	if len(auctions) > 0 {
+		avgBidsPerAuction = float64(totalBids) / float64(len(auctions))
+	}
+
+	// HHI is undefined for auctions without bids, so they are left out of the average
+	avgHHI := 0.0
+	if withBids := len(auctions) - auctionsWithNoBids; withBids > 0 {
+		avgHHI = totalHHI / float64(withBids)
+	}
+
+	avgBidderLoss := 0.0
+	if losers > 0 {
+		avgBidderLoss = lossTotal / float64(losers)
+	}
+
+	avgWinnerDiscount := 0.0
+	if sold > 0 {
+		avgWinnerDiscount = discountTotal / float64(sold)
+	}
+
+	avgClearingPrice := 0.0
+	if cleared > 0 {
+		avgClearingPrice = clearingTotal / float64(cleared)
+	}
+
+	avgDutchSaleTick := 0.0
+	if dutchSold > 0 {
+		avgDutchSaleTick = float64(dutchTicks) / float64(dutchSold)
+	}
+
+	return models.Statistics{
+		TotalBids:             totalBids,
+		AvgBidsPerAuction:     avgBidsPerAuction,
+		AuctionsWithNoBids:    auctionsWithNoBids,
+		AuctionsReserveNotMet: reserveNotMet,
+		ProxyBids:             proxyBids,
+		ProxyWins:             proxyWins,
+		DutchSold:             dutchSold,
+		AvgDutchSaleTick:      avgDutchSaleTick,
+		AvgHHI:                avgHHI,
+		MergedBids:            merged,
+		RejectedBids:          rejected,
+		DiscardedBids:         discarded,
+		Commitments:           commitments,
+		UnrevealedCommits:     unrevealed,
+		InvalidReveals:        invalid,
+		MinDurationFloorHits:  floorHits,
+		GraceExtensions:       graceExtensions,
+		AuctionsExtended:      auctionsExtended,
+		TotalRevenue:          revenue,
+		TotalFees:             fees,
+		NetRevenue:            netRevenue,
+		UnitsSold:             unitsSold,
+		AvgClearingPrice:      avgClearingPrice,
+		AvgBidderLoss:         avgBidderLoss,
+		AvgWinnerDiscount:     avgWinnerDiscount,
+	}
+}
+
+// averagePhaseTimings calculates the mean duration of each auction phase
+func averagePhaseTimings(auctions []*models.Auction) models.PhaseTimings {
+	var avg models.PhaseTimings
+	if len(auctions) == 0 {
+		return avg
+	}
+
+	for _, auction := range auctions {
+		avg.AttributeGenerationMs += auction.Phases.AttributeGenerationMs
+		avg.BidderNotificationMs += auction.Phases.BidderNotificationMs
+		avg.BidCollectionMs += auction.Phases.BidCollectionMs
+		avg.WinnerDeterminationMs += auction.Phases.WinnerDeterminationMs
+		avg.RevealMs += auction.Phases.RevealMs
+	}
+
+	n := float64(len(auctions))
+	avg.AttributeGenerationMs /= n
+	avg.BidderNotificationMs /= n
+	avg.BidCollectionMs /= n
+	avg.WinnerDeterminationMs /= n
+	avg.RevealMs /= n
+	return avg
+}
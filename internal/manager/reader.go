@@ -0,0 +1,55 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"auction-simulator/pkg/models"
+)
+
+// ReadAuctionResults loads the per-auction result files in dir that match the
+// configured result name pattern, or all_auctions.json if there are none,
+// sorted by auction ID. Fields that are not serialized, such as the timeout
+// duration, are restored from their recorded equivalents.
+func (og *OutputGenerator) ReadAuctionResults(dir string) ([]*models.Auction, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read results directory: %w", err)
+	}
+
+	var auctions []*models.Auction
+	for _, entry := range entries {
+		if entry.IsDir() || !og.isResultFilename(entry.Name()) {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+		}
+
+		auction := &models.Auction{}
+		if err := json.Unmarshal(data, auction); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", entry.Name(), err)
+		}
+		auction.RestoreTimeout()
+		auctions = append(auctions, auction)
+	}
+
+	if len(auctions) == 0 {
+		consolidated := filepath.Join(dir, ConsolidatedFileName)
+		if _, err := os.Stat(consolidated); err == nil {
+			if auctions, err = readConsolidated(consolidated); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(auctions) == 0 {
+		return nil, fmt.Errorf("no auction result files found in %s", dir)
+	}
+	sort.Slice(auctions, func(i, j int) bool { return auctions[i].ID < auctions[j].ID })
+	return auctions, nil
+}
@@ -0,0 +1,126 @@
+package manager
+
+import (
+	"math"
+
+	"auction-simulator/pkg/models"
+)
+
+// singularPivot is the smallest pivot the regression solver accepts; anything
+// smaller means the attributes are collinear over the sample
+const singularPivot = 1e-12
+
+// SetAttributeRegression enables the regression of winning prices on auction
+// attributes in the summary
+func (og *OutputGenerator) SetAttributeRegression(enabled bool) {
+	og.attributeRegression = enabled
+}
+
+// buildAttributeRegression fits winning amount = intercept + Σ coefficient ×
+// attribute by ordinary least squares over every auction with a winner, and
+// correlates each attribute with the winning amount. It returns nil when no
+// auction had a winner.
+func buildAttributeRegression(auctions []*models.Auction) *models.AttributeRegressionReport {
+	var rows [][]float64
+	var prices []float64
+	for _, auction := range auctions {
+		if auction.Winner == nil {
+			continue
+		}
+		row := make([]float64, 0, len(auction.Attributes)+1)
+		row = append(row, 1)
+		row = append(row, auction.Attributes[:]...)
+		rows = append(rows, row)
+		prices = append(prices, auction.Winner.Amount)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	report := &models.AttributeRegressionReport{Auctions: len(rows)}
+	attributes := make([]float64, len(rows))
+	for i := range len(rows[0]) - 1 {
+		for k, row := range rows {
+			attributes[k] = row[i+1]
+		}
+		r, _ := pearson(attributes, prices)
+		report.Attributes = append(report.Attributes, models.AttributeEffect{Attribute: i, Correlation: r})
+	}
+
+	// The fit needs more auctions than parameters, or the residuals are zero
+	// and the coefficients meaningless
+	if len(rows) <= len(rows[0]) {
+		return report
+	}
+	beta, ok := leastSquares(rows, prices)
+	if !ok {
+		return report
+	}
+	report.Fitted = true
+	report.Intercept = beta[0]
+	for i := range report.Attributes {
+		report.Attributes[i].Coefficient = beta[i+1]
+	}
+
+	mean, _ := meanStdDev(prices)
+	residual, total := 0.0, 0.0
+	for k, row := range rows {
+		predicted := 0.0
+		for j, x := range row {
+			predicted += beta[j] * x
+		}
+		residual += (prices[k] - predicted) * (prices[k] - predicted)
+		total += (prices[k] - mean) * (prices[k] - mean)
+	}
+	if total > 0 {
+		report.RSquared = 1 - residual/total
+	}
+	return report
+}
+
+// leastSquares solves the normal equations XᵀX β = Xᵀy by Gaussian
+// elimination with partial pivoting. It reports false if XᵀX is singular.
+func leastSquares(x [][]float64, y []float64) ([]float64, bool) {
+	n := len(x[0])
+
+	// a is the augmented matrix [XᵀX | Xᵀy]
+	a := make([][]float64, n)
+	for i := range a {
+		a[i] = make([]float64, n+1)
+		for k, row := range x {
+			for j := range n {
+				a[i][j] += row[i] * row[j]
+			}
+			a[i][n] += row[i] * y[k]
+		}
+	}
+
+	for col := range n {
+		pivot := col
+		for r := col + 1; r < n; r++ {
+			if math.Abs(a[r][col]) > math.Abs(a[pivot][col]) {
+				pivot = r
+			}
+		}
+		if math.Abs(a[pivot][col]) < singularPivot {
+			return nil, false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		for r := col + 1; r < n; r++ {
+			f := a[r][col] / a[col][col]
+			for c := col; c <= n; c++ {
+				a[r][c] -= f * a[col][c]
+			}
+		}
+	}
+
+	beta := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		sum := a[i][n]
+		for j := i + 1; j < n; j++ {
+			sum -= a[i][j] * beta[j]
+		}
+		beta[i] = sum / a[i][i]
+	}
+	return beta, true
+}
@@ -0,0 +1,215 @@
+package manager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"auction-simulator/pkg/models"
+)
+
+// reportTopWinners is how many bidders the report's winners table lists
+const reportTopWinners = 10
+
+// reportHistogramBuckets is the number of bars in the bid amount histogram
+const reportHistogramBuckets = 10
+
+// WriteMarkdownReport writes report.md, a human-readable summary of the run
+// for sharing in pull requests and wikis: key metrics, top winners, the bid
+// amount distribution, resource usage and the effective configuration
+func (og *OutputGenerator) WriteMarkdownReport(result *models.RunResult) error {
+	summary := buildSummary(result)
+	stats := summary.Statistics
+	profile := summary.ResourceProfile
+
+	var b bytes.Buffer
+	fmt.Fprintln(&b, "# Auction Simulation Report")
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "Run status: **%s**", summary.Status.Outcome)
+	if summary.Status.Detail != "" {
+		fmt.Fprintf(&b, " (%s)", summary.Status.Detail)
+	}
+	fmt.Fprintf(&b, ", seed `%d`", summary.Config.Seed)
+	if summary.TotalAuctions > 0 {
+		fmt.Fprintf(&b, ", %s to %s", summary.FirstAuctionStart.Format(time.RFC3339), summary.LastAuctionEnd.Format(time.RFC3339))
+	}
+	fmt.Fprint(&b, ".\n\n")
+
+	fmt.Fprintln(&b, "## Key Metrics")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "| Metric | Value |")
+	fmt.Fprintln(&b, "|---|---:|")
+	fmt.Fprintf(&b, "| Auctions | %d |\n", summary.TotalAuctions)
+	fmt.Fprintf(&b, "| Total bids | %d |\n", stats.TotalBids)
+	fmt.Fprintf(&b, "| Avg bids per auction | %.2f |\n", stats.AvgBidsPerAuction)
+	fmt.Fprintf(&b, "| Auctions with no bids | %d |\n", stats.AuctionsWithNoBids)
+	if summary.Config.ReserveMax > 0 {
+		fmt.Fprintf(&b, "| Auctions below reserve | %d |\n", stats.AuctionsReserveNotMet)
+	}
+	if summary.Config.ProxyRate > 0 {
+		fmt.Fprintf(&b, "| Proxy bids (auctions won) | %d (%d) |\n", stats.ProxyBids, stats.ProxyWins)
+	}
+	if c := summary.WinnersCurse; c != nil {
+		fmt.Fprintf(&b, "| Winner's curse (auctions overpaid) | %d of %d (%.1f%%) |\n", c.Overpaid, c.Auctions, c.OverpaidRate*100)
+	}
+	if summary.Config.DutchStart > 0 {
+		fmt.Fprintf(&b, "| Dutch clock sales (avg tick of %d) | %d (%.1f) |\n", summary.Config.DutchTicks, stats.DutchSold, stats.AvgDutchSaleTick)
+	}
+	if summary.Config.MinIncrement > 0 {
+		fmt.Fprintf(&b, "| Bids rejected (min increment %.2f) | %d |\n", summary.Config.MinIncrement, stats.RejectedBids)
+	}
+	fmt.Fprintf(&b, "| Total revenue (%s) | %.2f |\n", summary.Config.Pricing, stats.TotalRevenue)
+	if summary.Config.Fee != nil {
+		fmt.Fprintf(&b, "| Auction house fees | %.2f |\n", stats.TotalFees)
+		fmt.Fprintf(&b, "| Net revenue | %.2f |\n", stats.NetRevenue)
+	}
+	if summary.Config.Units > 1 {
+		fmt.Fprintf(&b, "| Units sold | %d |\n", stats.UnitsSold)
+		fmt.Fprintf(&b, "| Avg clearing price | %.2f |\n", stats.AvgClearingPrice)
+	}
+	fmt.Fprintf(&b, "| Avg HHI | %.4f |\n", stats.AvgHHI)
+	fmt.Fprintf(&b, "| Late bids | %d |\n", stats.LateBids)
+	fmt.Fprintf(&b, "| Execution time | %s |\n", summary.Config.DurationUnit.Format(time.Duration(summary.TotalExecutionTimeNs)))
+	fmt.Fprintln(&b)
+
+	writeTopWinners(&b, result.Auctions)
+	writeBidDistribution(&b, result.Auctions)
+
+	fmt.Fprintln(&b, "## Resource Usage")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "| Resource | Value |")
+	fmt.Fprintln(&b, "|---|---:|")
+	fmt.Fprintf(&b, "| CPUs (requested / used) | %d / %d |\n", profile.RequestedCPUs, profile.MaxCPUs)
+	fmt.Fprintf(&b, "| CPU utilization (avg / peak) | %.1f%% / %.1f%% |\n", profile.AvgCPUPercent, profile.PeakCPUPercent)
+	fmt.Fprintf(&b, "| Peak memory | %.2f MB |\n", profile.PeakMemoryMB)
+	fmt.Fprintf(&b, "| Memory p50 / p95 / p99 | %.2f / %.2f / %.2f MB |\n",
+		profile.MemoryPercentilesMB.P50, profile.MemoryPercentilesMB.P95, profile.MemoryPercentilesMB.P99)
+	fmt.Fprintf(&b, "| Avg goroutines | %d |\n", profile.AvgGoroutines)
+	fmt.Fprintf(&b, "| Peak bid goroutines | %d |\n", profile.PeakBidGoroutines)
+	fmt.Fprintf(&b, "| Peak active auctions | %d |\n", profile.PeakActiveAuctions)
+	fmt.Fprintln(&b)
+
+	config, err := json.MarshalIndent(summary.Config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	fmt.Fprintln(&b, "## Configuration")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "```json")
+	b.Write(config)
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "```")
+
+	filename := filepath.Join(og.outputDir, "report.md")
+	if err := og.writeFile(filename, b.Bytes()); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// writeTopWinners writes a table of the bidders with the most wins
+func writeTopWinners(b *bytes.Buffer, auctions []*models.Auction) {
+	type winner struct {
+		bidderID int
+		wins     int
+		total    float64
+	}
+	byBidder := make(map[int]*winner)
+	for _, auction := range auctions {
+		if auction.Winner == nil {
+			continue
+		}
+		w, ok := byBidder[auction.Winner.BidderID]
+		if !ok {
+			w = &winner{bidderID: auction.Winner.BidderID}
+			byBidder[w.bidderID] = w
+		}
+		w.wins++
+		w.total += auction.Winner.Amount
+	}
+
+	winners := make([]*winner, 0, len(byBidder))
+	for _, w := range byBidder {
+		winners = append(winners, w)
+	}
+	sort.Slice(winners, func(i, j int) bool {
+		if winners[i].wins != winners[j].wins {
+			return winners[i].wins > winners[j].wins
+		}
+		return winners[i].bidderID < winners[j].bidderID
+	})
+
+	fmt.Fprintln(b, "## Top Winners")
+	fmt.Fprintln(b)
+	if len(winners) == 0 {
+		fmt.Fprintln(b, "No auction had a winner.")
+		fmt.Fprintln(b)
+		return
+	}
+	fmt.Fprintln(b, "| Bidder | Wins | Total winning bids | Avg winning bid |")
+	fmt.Fprintln(b, "|---:|---:|---:|---:|")
+	for _, w := range winners[:min(len(winners), reportTopWinners)] {
+		fmt.Fprintf(b, "| %d | %d | %.2f | %.2f |\n", w.bidderID, w.wins, w.total, w.total/float64(w.wins))
+	}
+	fmt.Fprintln(b)
+}
+
+// writeBidDistribution writes summary statistics of every bid amount and an
+// ASCII histogram in a code block
+func writeBidDistribution(b *bytes.Buffer, auctions []*models.Auction) {
+	var amounts []float64
+	for _, auction := range auctions {
+		for _, bid := range auction.Bids {
+			amounts = append(amounts, bid.Amount)
+		}
+	}
+
+	fmt.Fprintln(b, "## Bid Distribution")
+	fmt.Fprintln(b)
+	if len(amounts) == 0 {
+		fmt.Fprintln(b, "No bids were placed.")
+		fmt.Fprintln(b)
+		return
+	}
+
+	sort.Float64s(amounts)
+	rank := func(p float64) float64 {
+		i := int(math.Ceil(p/100*float64(len(amounts)))) - 1
+		return amounts[max(i, 0)]
+	}
+	lo, hi := amounts[0], amounts[len(amounts)-1]
+
+	fmt.Fprintln(b, "| Min | p50 | p95 | Max |")
+	fmt.Fprintln(b, "|---:|---:|---:|---:|")
+	fmt.Fprintf(b, "| %.2f | %.2f | %.2f | %.2f |\n", lo, rank(50), rank(95), hi)
+	fmt.Fprintln(b)
+
+	counts := make([]int, reportHistogramBuckets)
+	width := (hi - lo) / reportHistogramBuckets
+	for _, amount := range amounts {
+		i := reportHistogramBuckets - 1
+		if width > 0 {
+			i = min(int((amount-lo)/width), reportHistogramBuckets-1)
+		}
+		counts[i]++
+	}
+	peak := 0
+	for _, n := range counts {
+		peak = max(peak, n)
+	}
+
+	const barWidth = 40
+	fmt.Fprintln(b, "```")
+	for i, n := range counts {
+		from := lo + float64(i)*width
+		bar := strings.Repeat("#", int(math.Round(float64(n)/float64(peak)*barWidth)))
+		fmt.Fprintf(b, "%10.2f - %10.2f | %-*s %d\n", from, from+width, barWidth, bar, n)
+	}
+	fmt.Fprintln(b, "```")
+	fmt.Fprintln(b)
+}
@@ -0,0 +1,94 @@
+package manager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"auction-simulator/pkg/models"
+)
+
+// RetentionPolicy bounds how many per-auction result files are kept in the
+// output directory. A zero field means that dimension is unlimited.
+type RetentionPolicy struct {
+	MaxFiles int
+	MaxAge   time.Duration
+}
+
+// enabled reports whether the policy removes anything at all
+func (p RetentionPolicy) enabled() bool {
+	return p.MaxFiles > 0 || p.MaxAge > 0
+}
+
+// SetRetentionPolicy configures result-file retention. Files beyond the policy
+// are deleted after each write; their auctions remain counted in the summary's
+// aggregate statistics.
+func (og *OutputGenerator) SetRetentionPolicy(policy RetentionPolicy) error {
+	if policy.MaxFiles < 0 {
+		return fmt.Errorf("retention max files must not be negative, got %d", policy.MaxFiles)
+	}
+	if policy.MaxAge < 0 {
+		return fmt.Errorf("retention max age must not be negative, got %v", policy.MaxAge)
+	}
+	og.retention = policy
+	return nil
+}
+
+// applyRetention removes the oldest result files that fall outside the policy.
+// Only files matching the result name pattern are ever considered.
+func (og *OutputGenerator) applyRetention() error {
+	if !og.retention.enabled() {
+		return nil
+	}
+
+	entries, err := os.ReadDir(og.outputDir)
+	if err != nil {
+		return fmt.Errorf("failed to list output directory: %w", err)
+	}
+
+	type resultFile struct {
+		name    string
+		modTime time.Time
+	}
+
+	var files []resultFile
+	for _, entry := range entries {
+		if entry.IsDir() || !og.isResultFilename(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue // Removed concurrently
+		}
+		files = append(files, resultFile{entry.Name(), info.ModTime()})
+	}
+
+	// Newest first so the files to keep form a prefix
+	sort.Slice(files, func(i, j int) bool {
+		if !files[i].modTime.Equal(files[j].modTime) {
+			return files[i].modTime.After(files[j].modTime)
+		}
+		return files[i].name > files[j].name
+	})
+
+	now := time.Now()
+	report := &models.RetentionReport{TotalProcessed: og.totalProcessed}
+	for i, f := range files {
+		tooMany := og.retention.MaxFiles > 0 && i >= og.retention.MaxFiles
+		tooOld := og.retention.MaxAge > 0 && now.Sub(f.modTime) > og.retention.MaxAge
+		if !tooMany && !tooOld {
+			report.RetainedFiles++
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(og.outputDir, f.name)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", f.name, err)
+		}
+		report.RemovedFiles++
+	}
+
+	og.retentionReport = report
+	return nil
+}
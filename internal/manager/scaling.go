@@ -0,0 +1,69 @@
+package manager
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"auction-simulator/pkg/models"
+)
+
+// ScalingFileName is the file a CPU scaling sweep is written to
+const ScalingFileName = "scaling.csv"
+
+// WriteScalingResults writes one CSV row per CPU count of a scaling sweep to
+// scaling.csv
+func (og *OutputGenerator) WriteScalingResults(rows []models.ScalingResult) error {
+	if err := os.MkdirAll(og.outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	filename := filepath.Join(og.outputDir, ScalingFileName)
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", ScalingFileName, err)
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+	w.Write([]string{
+		"cpus", "total_auctions", "total_bids", "wall_time_ms",
+		"auctions_per_sec", "bids_per_sec", "speedup", "efficiency",
+	})
+	for _, row := range rows {
+		w.Write([]string{
+			strconv.Itoa(row.CPUs),
+			strconv.Itoa(row.TotalAuctions),
+			strconv.Itoa(row.TotalBids),
+			strconv.FormatInt(row.WallTimeMs, 10),
+			strconv.FormatFloat(row.AuctionsPerSec, 'f', 2, 64),
+			strconv.FormatFloat(row.BidsPerSec, 'f', 2, 64),
+			strconv.FormatFloat(row.Speedup, 'f', 3, 64),
+			strconv.FormatFloat(row.Efficiency, 'f', 3, 64),
+		})
+	}
+	w.Flush()
+
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ScalingFileName, err)
+	}
+	return nil
+}
+
+// PrintScalingSummary prints the throughput at each CPU count and the knee of
+// the scaling curve
+func (og *OutputGenerator) PrintScalingSummary(rows []models.ScalingResult, knee int) {
+	if len(rows) == 0 {
+		return
+	}
+
+	fmt.Printf("\nCPU Scaling (%d runs):\n", len(rows))
+	fmt.Println("  CPUs   Wall Time     Bids/s   Speedup   Efficiency")
+	for _, row := range rows {
+		fmt.Printf("  %4d   %7d ms  %9.1f   %6.2fx   %9.1f%%\n",
+			row.CPUs, row.WallTimeMs, row.BidsPerSec, row.Speedup, row.Efficiency*100)
+	}
+	fmt.Printf("\n  Knee:                   %d CPUs (suggested -cpus)\n", knee)
+}
@@ -0,0 +1,48 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"auction-simulator/pkg/models"
+)
+
+// SequenceFileName is the file the recorded bid arrival order is written to
+const SequenceFileName = "bid_sequence.json"
+
+// WriteBidSequence writes bid_sequence.json, every bid in the order and at
+// the time its auction's collector received it. It does nothing if the run
+// did not record a sequence.
+func (og *OutputGenerator) WriteBidSequence(result *models.RunResult) error {
+	if result.Sequence == nil {
+		return nil
+	}
+	data, err := json.MarshalIndent(result.Sequence, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal bid sequence: %w", err)
+	}
+
+	filename := filepath.Join(og.outputDir, SequenceFileName)
+	if err := og.writeFile(filename, data); err != nil {
+		return fmt.Errorf("failed to write bid sequence: %w", err)
+	}
+	return nil
+}
+
+// ReadBidSequence loads a bid sequence written by WriteBidSequence
+func ReadBidSequence(path string) (*models.BidSequence, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	seq := &models.BidSequence{}
+	if err := json.Unmarshal(data, seq); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if seq.NumAuctions <= 0 {
+		return nil, fmt.Errorf("%s records no auctions", path)
+	}
+	return seq, nil
+}
@@ -0,0 +1,113 @@
+package manager
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"auction-simulator/internal/faults"
+	"auction-simulator/pkg/models"
+)
+
+// SingleFileName is the file WriteSingleFile writes in the output directory
+const SingleFileName = "simulation.json"
+
+// WriteSingleFile writes the whole run to simulation.json: the effective
+// config, the summary, the resource samples and every auction with its bids.
+// Auctions are encoded one at a time as the file is written, so memory use
+// stays close to that of the run itself rather than doubling for the output.
+func (og *OutputGenerator) WriteSingleFile(result *models.RunResult) error {
+	if err := og.faults.Fail(faults.SiteOutputWrite, faults.NameKey(SingleFileName)); err != nil {
+		return fmt.Errorf("failed to write %s: %w", SingleFileName, err)
+	}
+
+	filename := filepath.Join(og.outputDir, SingleFileName)
+	tmp := filename + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", SingleFileName, err)
+	}
+	defer os.Remove(tmp) // No-op once renamed into place
+
+	if err := og.encodeSingleFile(f, result); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", SingleFileName, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", SingleFileName, err)
+	}
+	return os.Rename(tmp, filename)
+}
+
+// encodeSingleFile streams a models.SimulationFile to f, field by field
+func (og *OutputGenerator) encodeSingleFile(f *os.File, result *models.RunResult) error {
+	w := bufio.NewWriter(f)
+	field := func(name string, v any, last bool) error {
+		data, err := json.MarshalIndent(v, "  ", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", name, err)
+		}
+		fmt.Fprintf(w, "  %q: %s", name, data)
+		if !last {
+			w.WriteString(",")
+		}
+		w.WriteString("\n")
+		return nil
+	}
+
+	samples := result.Samples
+	if samples == nil {
+		samples = []models.ResourceSample{}
+	}
+
+	w.WriteString("{\n")
+	if err := field("config", result.Config, false); err != nil {
+		return err
+	}
+	if err := field("summary", og.summary(result), false); err != nil {
+		return err
+	}
+	if err := field("resource_samples", samples, false); err != nil {
+		return err
+	}
+
+	w.WriteString(`  "auctions": [`)
+	for i, auction := range result.Auctions {
+		data, err := json.MarshalIndent(auction, "    ", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal auction %d: %w", auction.ID, err)
+		}
+		if i > 0 {
+			w.WriteString(",")
+		}
+		w.WriteString("\n    ")
+		w.Write(data)
+	}
+	if len(result.Auctions) > 0 {
+		w.WriteString("\n  ")
+	}
+	w.WriteString("]\n}\n")
+	return w.Flush()
+}
+
+// ReadSingleFile loads a run written by WriteSingleFile. As with
+// ReadAuctionResults, each auction's timeout is restored from its recorded
+// milliseconds.
+func ReadSingleFile(path string) (*models.SimulationFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	sim := &models.SimulationFile{}
+	if err := json.NewDecoder(bufio.NewReader(f)).Decode(sim); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for _, auction := range sim.Auctions {
+		auction.RestoreTimeout()
+	}
+	return sim, nil
+}
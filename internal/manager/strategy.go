@@ -0,0 +1,133 @@
+package manager
+
+import (
+	"sort"
+
+	"auction-simulator/pkg/models"
+)
+
+// buildStrategyStats calculates bid, win, spend and surplus statistics per
+// bidding strategy. A bidder's valuation of what it won is its valuation of
+// the first unit, scaled down the demand schedule for further units; what it
+// paid follows the auction's pricing. Bids without a strategy are skipped.
+func buildStrategyStats(auctions []*models.Auction) []models.StrategyStats {
+	byStrategy := make(map[string]*models.StrategyStats)
+	members := make(map[string]map[int]bool)
+	totalBid := make(map[string]float64)
+
+	for _, auction := range auctions {
+		best := highestBids(auction)
+		for _, bid := range auction.Bids {
+			if bid.Strategy == "" {
+				continue
+			}
+			ss, ok := byStrategy[bid.Strategy]
+			if !ok {
+				ss = &models.StrategyStats{Strategy: bid.Strategy}
+				byStrategy[bid.Strategy] = ss
+				members[bid.Strategy] = make(map[int]bool)
+			}
+			ss.Bids++
+			totalBid[bid.Strategy] += bid.Amount
+			members[bid.Strategy][bid.BidderID] = true
+		}
+
+		value, paid := settle(auction, best)
+		for id, bid := range best {
+			ss, ok := byStrategy[bid.Strategy]
+			if !ok {
+				continue
+			}
+			ss.Entries++
+			if v, won := value[id]; won {
+				ss.Wins++
+				ss.Surplus += v
+			}
+			ss.TotalSpend += paid[id]
+			ss.Surplus -= paid[id]
+		}
+	}
+
+	stats := make([]models.StrategyStats, 0, len(byStrategy))
+	for name, ss := range byStrategy {
+		ss.Bidders = len(members[name])
+		ss.AvgBid = totalBid[name] / float64(ss.Bids)
+		if ss.Entries > 0 {
+			ss.WinRate = float64(ss.Wins) / float64(ss.Entries)
+		}
+		stats = append(stats, *ss)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Strategy < stats[j].Strategy })
+	return stats
+}
+
+// settle returns, by bidder ID, the valuation of what each winner won and
+// what each bidder paid under the auction's pricing, given the bidders'
+// highest bids
+func settle(auction *models.Auction, best map[int]models.Bid) (value, paid map[int]float64) {
+	value = make(map[int]float64)
+	paid = make(map[int]float64)
+	switch {
+	case len(auction.Allocations) > 0:
+		for _, alloc := range auction.Allocations {
+			if bid, ok := best[alloc.BidderID]; ok {
+				value[alloc.BidderID] = allocationValue(auction, bid, alloc)
+			}
+			paid[alloc.BidderID] += alloc.Paid
+		}
+	case auction.Winner != nil:
+		value[auction.Winner.BidderID] = auction.Winner.Valuation
+		if auction.Pricing != models.PricingAllPay {
+			paid[auction.Winner.BidderID] += auction.WinningPrice
+		}
+	}
+	if auction.Pricing == models.PricingAllPay {
+		for id, bid := range best {
+			paid[id] += bid.Amount
+		}
+	}
+	return value, paid
+}
+
+// highestBids returns each bidder's highest bid in auction, the earliest on a
+// tie, leaving out bids without a strategy
+func highestBids(auction *models.Auction) map[int]models.Bid {
+	best := make(map[int]models.Bid)
+	for _, bid := range auction.Bids {
+		if bid.Strategy == "" {
+			continue
+		}
+		if prev, ok := best[bid.BidderID]; !ok || bid.Amount > prev.Amount {
+			best[bid.BidderID] = bid
+		}
+	}
+	return best
+}
+
+// allocationValue is the bidder's valuation of what alloc gave it from bid:
+// the won bundle in a combinatorial auction, or the won units otherwise
+func allocationValue(auction *models.Auction, bid models.Bid, alloc models.Allocation) float64 {
+	if auction.BundleItems <= 1 {
+		return unitsValue(bid, alloc.Units)
+	}
+	won := models.BundleBid{Items: alloc.Items}.Mask()
+	for _, bundle := range auction.BundlesOf(bid) {
+		if bundle.Mask() == won {
+			return bundle.Valuation
+		}
+	}
+	return 0
+}
+
+// unitsValue is the bidder's valuation of units won from bid: the first unit
+// at its valuation and each further unit decayed as its demand schedule is
+func unitsValue(bid models.Bid, units int) float64 {
+	if len(bid.Demand) == 0 || bid.Demand[0].Price <= 0 {
+		return bid.Valuation * float64(units)
+	}
+	total := 0.0
+	for k := range min(units, len(bid.Demand)) {
+		total += bid.Valuation * bid.Demand[k].Price / bid.Demand[0].Price
+	}
+	return total
+}
@@ -0,0 +1,72 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"auction-simulator/pkg/models"
+)
+
+// orderSubmissions restamps a serial auction's bids so the collector, which
+// takes them in timestamp order, receives them in the configured sequence.
+// Reordering modes hand the bids' own timestamps out again in the new order,
+// so arrival times keep their spread and only who arrives when changes.
+func (m *Manager) orderSubmissions(a *models.Auction, bids []models.Bid) []models.Bid {
+	switch m.config.SubmissionOrder {
+	case models.SubmitByBidderID:
+		slices.SortStableFunc(bids, func(x, y models.Bid) int { return x.BidderID - y.BidderID })
+	case models.SubmitShuffled:
+		slices.SortStableFunc(bids, func(x, y models.Bid) int { return x.BidderID - y.BidderID })
+		stream := m.source.New(m.config.Seed + int64(a.ID))
+		stream.Shuffle(len(bids), func(i, j int) { bids[i], bids[j] = bids[j], bids[i] })
+	case models.SubmitBySchedule:
+		for i, bid := range bids {
+			if delay, ok := m.config.SubmissionSchedule[bid.BidderID]; ok {
+				bids[i].Timestamp = a.StartTime.Add(delay)
+			}
+		}
+		return bids
+	default:
+		return bids
+	}
+
+	times := make([]time.Time, len(bids))
+	for i, bid := range bids {
+		times[i] = bid.Timestamp
+	}
+	slices.SortFunc(times, func(x, y time.Time) int { return x.Compare(y) })
+	for i := range bids {
+		bids[i].Timestamp = times[i]
+	}
+	return bids
+}
+
+// ReadSubmissionSchedule loads a submission schedule: a JSON object mapping
+// bidder IDs to the delay, such as "15ms", after which each one's bid reaches
+// the collector
+func ReadSubmissionSchedule(path string) (map[int]time.Duration, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var raw map[string]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	schedule := make(map[int]time.Duration, len(raw))
+	for id, delay := range raw {
+		bidderID, err := strconv.Atoi(id)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid bidder ID %q", path, id)
+		}
+		if schedule[bidderID], err = time.ParseDuration(delay); err != nil {
+			return nil, fmt.Errorf("%s: bidder %d: invalid delay %q", path, bidderID, delay)
+		}
+	}
+	return schedule, nil
+}
@@ -0,0 +1,106 @@
+package manager
+
+import (
+	"time"
+
+	"auction-simulator/internal/resource"
+	"auction-simulator/pkg/models"
+)
+
+// deltaWindows are the windows, in milliseconds, the bid timing report counts
+// following bids within
+var deltaWindows = []int{10, 50, 100, 250, 500}
+
+// buildBidTiming summarizes every bid's delay after the first bid of its
+// auction, leaving out that first bid. It returns nil when no auction had a
+// second bid.
+func buildBidTiming(auctions []*models.Auction) *models.BidTimingReport {
+	var deltas []float64
+	for _, auction := range auctions {
+		first := true
+		for _, bid := range auction.Bids {
+			if first && bid.Timestamp.Equal(auction.FirstBidTime) {
+				first = false
+				continue
+			}
+			deltas = append(deltas, bid.DeltaFromFirstBidMs)
+		}
+	}
+	if len(deltas) == 0 {
+		return nil
+	}
+
+	report := &models.BidTimingReport{Bids: len(deltas)}
+	for _, d := range deltas {
+		report.MeanMs += d
+	}
+	report.MeanMs /= float64(len(deltas))
+
+	for _, window := range deltaWindows {
+		within := 0
+		for _, d := range deltas {
+			if d <= float64(window) {
+				within++
+			}
+		}
+		report.Within = append(report.Within, models.DeltaWindow{
+			WindowMs: window,
+			Share:    float64(within) / float64(len(deltas)),
+		})
+	}
+
+	report.PercentilesMs = resource.Percentiles(deltas)
+	return report
+}
+
+// buildArrivals summarizes when bidders arrived at and left each auction.
+// Bidders that stayed until the close count as staying for the rest of the
+// auction. It returns nil when arrivals were not staggered.
+func buildArrivals(auctions []*models.Auction) *models.ArrivalReport {
+	var arrivals, departures []float64
+	report := &models.ArrivalReport{}
+	for _, auction := range auctions {
+		closeMs := float64(auction.EndTime.Sub(auction.StartTime)) / float64(time.Millisecond)
+		for _, p := range auction.Presence {
+			arrivals = append(arrivals, p.ArrivedMs)
+			report.MeanArrivalMs += p.ArrivedMs
+			if p.DepartedMs > 0 {
+				departures = append(departures, p.DepartedMs)
+				report.MeanStayMs += p.DepartedMs - p.ArrivedMs
+			} else {
+				report.MeanStayMs += max(closeMs-p.ArrivedMs, 0)
+			}
+			if p.LeftEarly {
+				report.LeftEarly++
+			}
+		}
+	}
+	if len(arrivals) == 0 {
+		return nil
+	}
+
+	report.Arrivals = len(arrivals)
+	report.Departures = len(departures)
+	report.MeanArrivalMs /= float64(len(arrivals))
+	report.MeanStayMs /= float64(len(arrivals))
+	report.ArrivalMs = resource.Percentiles(arrivals)
+	report.DepartureMs = resource.Percentiles(departures)
+	return report
+}
+
+// buildBurst completes the bidders' burst counts with the spread of every
+// auction's peak bid arrival rate. It returns nil when bidders placed single
+// bids.
+func buildBurst(counts *models.BurstReport, auctions []*models.Auction) *models.BurstReport {
+	if counts == nil {
+		return nil
+	}
+	report := *counts
+	rates := make([]float64, 0, len(auctions))
+	for _, auction := range auctions {
+		rates = append(rates, auction.PeakBidRate)
+		report.MaxBidRate = max(report.MaxBidRate, auction.PeakBidRate)
+	}
+	report.PeakBidRate = resource.Percentiles(rates)
+	return &report
+}
@@ -0,0 +1,116 @@
+package manager
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"auction-simulator/pkg/models"
+)
+
+// traceEvent is a single entry in the Chrome Trace Event format, which can be
+// loaded into chrome://tracing or https://ui.perfetto.dev
+type traceEvent struct {
+	Name  string         `json:"name"`
+	Cat   string         `json:"cat"`
+	Ph    string         `json:"ph"`
+	Ts    float64        `json:"ts"`
+	Dur   float64        `json:"dur,omitempty"`
+	Pid   int            `json:"pid"`
+	Tid   int            `json:"tid"`
+	Scope string         `json:"s,omitempty"`
+	Args  map[string]any `json:"args,omitempty"`
+}
+
+// WriteTrace writes trace.json, a timeline of every auction as a span (with a
+// child span per phase) and every bid as an instant event at its arrival time.
+// Each auction gets its own track; timestamps are relative to the first start.
+func (og *OutputGenerator) WriteTrace(result *models.RunResult) error {
+	if err := os.MkdirAll(og.outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	filename := filepath.Join(og.outputDir, "trace.json")
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create trace: %w", err)
+	}
+	defer file.Close()
+
+	origin := result.FirstStart
+	us := func(t time.Time) float64 { return float64(t.Sub(origin)) / float64(time.Microsecond) }
+	msToUs := func(ms float64) float64 { return ms * 1000 }
+
+	// Events are streamed one at a time so large runs are never buffered whole
+	w := bufio.NewWriter(file)
+	enc := json.NewEncoder(w)
+	first := true
+	emit := func(e traceEvent) error {
+		if !first {
+			w.WriteString(",")
+		}
+		first = false
+		return enc.Encode(e)
+	}
+
+	w.WriteString(`{"displayTimeUnit":"ms","traceEvents":[`)
+	for _, a := range result.Auctions {
+		start := us(a.StartTime)
+		phases := a.Phases
+
+		events := []traceEvent{
+			{
+				Name: fmt.Sprintf("auction %d", a.ID), Cat: "auction", Ph: "X",
+				Ts: start, Dur: us(a.EndTime) - start, Pid: 1, Tid: a.ID,
+				Args: map[string]any{"bids": a.TotalBids, "status": a.Status},
+			},
+			{
+				Name: "attribute generation", Cat: "phase", Ph: "X",
+				Ts: start - msToUs(phases.AttributeGenerationMs), Dur: msToUs(phases.AttributeGenerationMs), Pid: 1, Tid: a.ID,
+			},
+			{
+				Name: "bidder notification", Cat: "phase", Ph: "X",
+				Ts: start, Dur: msToUs(phases.BidderNotificationMs), Pid: 1, Tid: a.ID,
+			},
+			{
+				Name: "bid collection", Cat: "phase", Ph: "X",
+				Ts: start + msToUs(phases.BidderNotificationMs), Dur: msToUs(phases.BidCollectionMs), Pid: 1, Tid: a.ID,
+			},
+			{
+				Name: "winner determination", Cat: "phase", Ph: "X",
+				Ts: us(a.EndTime), Dur: msToUs(phases.WinnerDeterminationMs), Pid: 1, Tid: a.ID,
+			},
+		}
+		if phases.RevealMs > 0 {
+			events = append(events, traceEvent{
+				Name: "reveal", Cat: "phase", Ph: "X",
+				Ts: us(a.EndTime) - msToUs(phases.RevealMs), Dur: msToUs(phases.RevealMs), Pid: 1, Tid: a.ID,
+			})
+		}
+		for _, bid := range a.Bids {
+			events = append(events, traceEvent{
+				Name: fmt.Sprintf("bid from %d", bid.BidderID), Cat: "bid", Ph: "i", Scope: "t",
+				Ts: us(bid.Timestamp), Pid: 1, Tid: a.ID,
+				Args: map[string]any{
+					"amount":     bid.Amount,
+					"latency_ms": float64(bid.Timestamp.Sub(a.StartTime)) / float64(time.Millisecond),
+				},
+			})
+		}
+
+		for _, e := range events {
+			if err := emit(e); err != nil {
+				return fmt.Errorf("failed to encode trace event: %w", err)
+			}
+		}
+	}
+	w.WriteString("]}\n")
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write trace: %w", err)
+	}
+	return nil
+}
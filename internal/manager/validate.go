@@ -0,0 +1,202 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"auction-simulator/pkg/models"
+)
+
+// ValidateAuction checks the invariants every finished auction satisfies and
+// returns a description of each one broken
+func ValidateAuction(a *models.Auction) []string {
+	var problems []string
+	fail := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf("auction %d: ", a.ID)+fmt.Sprintf(format, args...))
+	}
+
+	if a.ID <= 0 {
+		fail("id must be positive")
+	}
+	if a.Status != models.StatusCompleted && a.Status != models.StatusCancelled {
+		fail("unknown status %q", a.Status)
+	}
+	if !a.StartTime.IsZero() && a.EndTime.Before(a.StartTime) {
+		fail("ends before it starts")
+	}
+
+	stored := len(a.Bids)
+	if a.Discarded != nil {
+		stored += a.Discarded.Count
+	}
+	if a.TotalBids != stored {
+		fail("total_bids is %d but %d bids are stored or discarded", a.TotalBids, stored)
+	}
+	for _, bid := range a.Bids {
+		if bid.MaxBid > 0 && bid.Amount > bid.MaxBid {
+			fail("proxy bid by bidder %d stands at %.2f, above its maximum %.2f", bid.BidderID, bid.Amount, bid.MaxBid)
+		}
+	}
+	if a.MinIncrement > 0 {
+		// The auction places proxy bids, and raises a winning one after the
+		// fact, so only open bids are checked against the earlier ones
+		high := 0.0
+		for i := 1; i < len(a.Bids); i++ {
+			if a.Bids[i-1].MaxBid <= 0 {
+				high = max(high, a.Bids[i-1].Amount)
+			}
+			if a.Bids[i].MaxBid <= 0 && a.Bids[i].Amount < high+a.MinIncrement-1e-9 {
+				fail("bid of %.2f by bidder %d does not beat the highest earlier bid %.2f by the minimum increment %.2f",
+					a.Bids[i].Amount, a.Bids[i].BidderID, high, a.MinIncrement)
+			}
+		}
+	}
+
+	if a.TrueValue > 0 {
+		if overpaid := a.Winner != nil && a.WinningPrice > a.TrueValue; a.Overpaid != overpaid {
+			fail("overpaid is %v but the winning price %.2f against the true value %.2f says %v", a.Overpaid, a.WinningPrice, a.TrueValue, overpaid)
+		}
+	} else if a.Overpaid {
+		fail("overpaid is set without a true value")
+	}
+
+	if d := a.Dutch; d != nil {
+		switch {
+		case d.Sold != (a.Winner != nil):
+			fail("Dutch clock sold is %v but the auction has winner %v", d.Sold, a.Winner != nil)
+		case d.Sold && (d.SoldAtTick < 0 || d.SoldAtTick >= d.Ticks):
+			fail("Dutch clock sold at tick %d outside its %d ticks", d.SoldAtTick, d.Ticks)
+		case d.Sold && a.Winner.Amount != d.ClearingPrice:
+			fail("winning bid %.2f is not the Dutch clearing price %.2f", a.Winner.Amount, d.ClearingPrice)
+		case d.Sold && (d.ClearingPrice > d.StartPrice || d.ClearingPrice < d.FloorPrice):
+			fail("Dutch clearing price %.2f is outside the clock's range [%.2f, %.2f]", d.ClearingPrice, d.FloorPrice, d.StartPrice)
+		}
+	}
+
+	if a.BundleItems > 1 || a.Units > 1 {
+		if len(a.Winners) != len(a.Allocations) {
+			fail("lists %d winners but %d allocations", len(a.Winners), len(a.Allocations))
+		} else {
+			for i, w := range a.Winners {
+				if w.BidderID != a.Allocations[i].BidderID {
+					fail("winner %d is bidder %d but allocation %d is bidder %d", i+1, w.BidderID, i+1, a.Allocations[i].BidderID)
+				}
+			}
+		}
+	}
+
+	if a.BundleItems > 1 {
+		var sold uint32
+		cleared := 0.0
+		for _, alloc := range a.Allocations {
+			if slices.ContainsFunc(alloc.Items, func(item int) bool { return item < 0 || item >= a.BundleItems }) {
+				fail("allocates items %v outside its %d items", alloc.Items, a.BundleItems)
+				continue
+			}
+			mask := models.BundleBid{Items: alloc.Items}.Mask()
+			if mask&sold != 0 {
+				fail("allocates item(s) %v to bidder %d more than once", alloc.Items, alloc.BidderID)
+			}
+			sold |= mask
+			cleared += alloc.Paid
+		}
+		if diff := cleared - a.ClearedValue; diff > 0.005 || diff < -0.005 {
+			fail("cleared value is %.2f but its allocations total %.2f", a.ClearedValue, cleared)
+		}
+		return problems
+	}
+
+	if a.Units > 1 {
+		units := 0
+		for _, alloc := range a.Allocations {
+			units += alloc.Units
+		}
+		if units > a.Units {
+			fail("allocates %d units but sells %d", units, a.Units)
+		}
+		return problems
+	}
+
+	switch {
+	case a.Winner == nil && len(a.Bids) > 0:
+		for _, bid := range a.Bids {
+			if a.ReservePrice <= 0 || bid.Ceiling() >= a.ReservePrice {
+				fail("has %d bids but no winner", len(a.Bids))
+				break
+			}
+		}
+	case a.Winner != nil:
+		if a.Winner.Amount < a.ReservePrice {
+			fail("winning bid %.2f is below the reserve %.2f", a.Winner.Amount, a.ReservePrice)
+		}
+		found := false
+		for _, bid := range a.Bids {
+			if bid.Ceiling() > a.Winner.Ceiling() {
+				fail("bid of %.2f by bidder %d beats the winning %.2f", bid.Ceiling(), bid.BidderID, a.Winner.Ceiling())
+			}
+			found = found || bid.BidderID == a.Winner.BidderID && bid.Amount == a.Winner.Amount
+		}
+		if !found {
+			fail("winning bid by bidder %d is not among its bids", a.Winner.BidderID)
+		}
+		if a.WinningPrice > a.Winner.Amount {
+			fail("winning price %.2f exceeds the winning bid %.2f", a.WinningPrice, a.Winner.Amount)
+		}
+	}
+	return problems
+}
+
+// Validate checks a run's output directory: every result file parses and
+// passes ValidateAuction, the execution summary parses and accounts for the
+// results, and, if the summary holds a fingerprint, every result still hashes
+// to its recorded value. It returns the number of results checked and a
+// description of each problem found.
+func (og *OutputGenerator) Validate(dir string) (int, []string) {
+	auctions, err := og.ReadAuctionResults(dir)
+	if err != nil {
+		return 0, []string{err.Error()}
+	}
+
+	var problems []string
+	for _, a := range auctions {
+		problems = append(problems, ValidateAuction(a)...)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "execution_summary.json"))
+	if err != nil {
+		return len(auctions), append(problems, fmt.Sprintf("summary: %v", err))
+	}
+	var summary models.ExecutionSummary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return len(auctions), append(problems, fmt.Sprintf("summary: failed to parse: %v", err))
+	}
+
+	// Retention may have removed result files, but never adds any
+	switch {
+	case summary.Retention == nil && len(auctions) != summary.TotalAuctions:
+		problems = append(problems, fmt.Sprintf("summary: lists %d auctions but %d result files were found",
+			summary.TotalAuctions, len(auctions)))
+	case summary.Retention != nil && len(auctions) > summary.Retention.RetainedFiles:
+		problems = append(problems, fmt.Sprintf("summary: retained %d result files but %d were found",
+			summary.Retention.RetainedFiles, len(auctions)))
+	}
+
+	if summary.Fingerprint != nil {
+		recorded := make(map[int]string, len(summary.Fingerprint.Auctions))
+		for _, h := range summary.Fingerprint.Auctions {
+			recorded[h.AuctionID] = h.Hash
+		}
+		for _, a := range auctions {
+			switch hash, ok := recorded[a.ID]; {
+			case !ok:
+				problems = append(problems, fmt.Sprintf("auction %d: not in the summary's fingerprint", a.ID))
+			case hash != a.CanonicalHash():
+				problems = append(problems, fmt.Sprintf("auction %d: content does not match the summary's fingerprint", a.ID))
+			}
+		}
+	}
+	return len(auctions), problems
+}
@@ -0,0 +1,78 @@
+package manager
+
+import (
+	"log"
+	"sort"
+	"time"
+
+	"auction-simulator/internal/resource"
+)
+
+// DefaultWatchdogMargin is how long past its expected close an auction may
+// run before the watchdog reports it as stuck
+const DefaultWatchdogMargin = 2 * time.Second
+
+// watch checks for stuck auctions at the resource monitor's sampling cadence
+// until done is closed
+func (m *Manager) watch(done <-chan struct{}) {
+	ticker := time.NewTicker(resource.SampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-ticker.C:
+			m.checkStuck(now)
+		}
+	}
+}
+
+// expectedRuntime is the longest an auction can legitimately take to close,
+// allowing for the longest timeout, every hammer-grace extension and any
+// reveal phase
+func (m *Manager) expectedRuntime() time.Duration {
+	return max(m.config.AuctionTimeout, m.config.AuctionTimeoutMax) + time.Duration(m.config.HammerMaxExtensions)*m.config.HammerGrace + m.config.RevealWindow
+}
+
+// checkStuck reports every auction that has gone more than the watchdog
+// margin past its expected close without its result being collected, and
+// cancels it if configured to. Each auction is reported once.
+func (m *Manager) checkStuck(now time.Time) {
+	limit := m.expectedRuntime() + m.config.WatchdogMargin
+
+	m.mu.Lock()
+	var stuck []int
+	var cancels []func()
+	for id, start := range m.outstanding {
+		if now.Sub(start) <= limit {
+			continue
+		}
+		stuck = append(stuck, id)
+		delete(m.outstanding, id)
+		if ra, ok := m.running[id]; ok && m.config.WatchdogCancel {
+			cancels = append(cancels, ra.cancel)
+		}
+	}
+	m.stuck = append(m.stuck, stuck...)
+	m.mu.Unlock()
+
+	if len(stuck) == 0 {
+		return
+	}
+	sort.Ints(stuck)
+	log.Printf("Warning: watchdog found %d auction(s) with no result %v after starting (expected close %v + margin %v): %v",
+		len(stuck), limit, m.expectedRuntime(), m.config.WatchdogMargin, stuck)
+	for _, cancel := range cancels {
+		cancel()
+	}
+}
+
+// StuckAuctions returns the IDs of auctions the watchdog reported as stuck
+func (m *Manager) StuckAuctions() []int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stuck := append([]int(nil), m.stuck...)
+	sort.Ints(stuck)
+	return stuck
+}
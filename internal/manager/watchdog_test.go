@@ -0,0 +1,62 @@
+package manager
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"auction-simulator/pkg/models"
+)
+
+// TestWatchdog simulates an auction that never sends its result by starting
+// it well over its expected runtime ago. The watchdog must report it once,
+// cancel it only if configured to, and leave an auction still on time alone.
+func TestWatchdog(t *testing.T) {
+	for _, cancelStuck := range []bool{false, true} {
+		m := &Manager{
+			config: models.SimConfig{
+				AuctionTimeout: 10 * time.Second,
+				WatchdogMargin: time.Second,
+				WatchdogCancel: cancelStuck,
+			},
+			running:     make(map[int]*runningAuction),
+			finished:    make(map[int]bool),
+			outstanding: make(map[int]time.Time),
+		}
+
+		stuckCtx, stuckCancel := context.WithCancel(context.Background())
+		defer stuckCancel()
+		m.trackAuction(1, stuckCancel)
+		m.outstanding[1] = time.Now().Add(-time.Minute)
+
+		onTimeCtx, onTimeCancel := context.WithCancel(context.Background())
+		defer onTimeCancel()
+		m.trackAuction(2, onTimeCancel)
+
+		// The watchdog ticks at the monitor's sampling interval
+		done := make(chan struct{})
+		go m.watch(done)
+		deadline := time.Now().Add(2 * time.Second)
+		for len(m.StuckAuctions()) == 0 && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		close(done)
+
+		if got := m.StuckAuctions(); !slices.Equal(got, []int{1}) {
+			t.Fatalf("cancel %v: stuck auctions %v, want [1]", cancelStuck, got)
+		}
+		if cancelled := stuckCtx.Err() != nil; cancelled != cancelStuck {
+			t.Errorf("cancel %v: stuck auction cancelled = %v", cancelStuck, cancelled)
+		}
+		if onTimeCtx.Err() != nil {
+			t.Errorf("cancel %v: auction still on time was cancelled", cancelStuck)
+		}
+
+		// A stuck auction is reported only once
+		m.checkStuck(time.Now())
+		if got := m.StuckAuctions(); !slices.Equal(got, []int{1}) {
+			t.Errorf("cancel %v: after a second check, stuck auctions %v, want [1]", cancelStuck, got)
+		}
+	}
+}
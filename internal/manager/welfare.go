@@ -0,0 +1,99 @@
+package manager
+
+import (
+	"slices"
+
+	"auction-simulator/pkg/models"
+)
+
+// welfareTolerance absorbs rounding when comparing achieved with optimal
+// welfare, so whole-cent amounts do not count as misallocations
+const welfareTolerance = 1e-6
+
+// buildWelfare totals, across auctions with a winner, the valuation of what
+// was allocated and the best valuation achievable. Valuations come from the
+// bids, so only bidders who bid count; auctions won by a bid without a
+// valuation, such as an external one, are skipped. It returns nil if no
+// auction qualifies.
+func buildWelfare(auctions []*models.Auction) *models.WelfareReport {
+	report := &models.WelfareReport{}
+	for _, auction := range auctions {
+		best := highestBids(auction)
+		achieved, ok := achievedWelfare(auction, best)
+		if !ok {
+			continue
+		}
+		optimal := optimalWelfare(auction, best)
+
+		report.Auctions++
+		report.AchievedWelfare += achieved
+		report.OptimalWelfare += optimal
+		if achieved < optimal-welfareTolerance {
+			report.Misallocated++
+		}
+	}
+	if report.Auctions == 0 {
+		return nil
+	}
+	if report.AchievedWelfare > 0 {
+		report.PriceOfAnarchy = report.OptimalWelfare / report.AchievedWelfare
+	}
+	if report.OptimalWelfare > 0 {
+		report.EfficiencyLoss = 1 - report.AchievedWelfare/report.OptimalWelfare
+	}
+	return report
+}
+
+// achievedWelfare is the winners' valuation of what they won, or false if the
+// auction had no winner or a winner without a recorded valuation
+func achievedWelfare(auction *models.Auction, best map[int]models.Bid) (float64, bool) {
+	if len(auction.Allocations) > 0 {
+		total := 0.0
+		for _, alloc := range auction.Allocations {
+			bid, ok := best[alloc.BidderID]
+			if !ok {
+				return 0, false
+			}
+			total += allocationValue(auction, bid, alloc)
+		}
+		return total, true
+	}
+	if auction.Winner == nil || auction.Winner.Strategy == "" {
+		return 0, false
+	}
+	return auction.Winner.Valuation, true
+}
+
+// optimalWelfare is the most the auction's units are worth to the bidders who
+// bid: the highest valuation for a single unit, the highest unit values
+// across every demand schedule for several, or the most valuable
+// non-overlapping bundles of a combinatorial auction
+func optimalWelfare(auction *models.Auction, best map[int]models.Bid) float64 {
+	if auction.BundleItems > 1 {
+		var sets [][]models.BundleBid
+		for _, bid := range best {
+			sets = append(sets, auction.BundlesOf(bid))
+		}
+		valuation := func(b models.BundleBid) float64 { return b.Valuation }
+		total := 0.0
+		for i, k := range models.SolveBundles(sets, auction.BundleItems, valuation) {
+			if k >= 0 {
+				total += sets[i][k].Valuation
+			}
+		}
+		return total
+	}
+	units := max(auction.Units, 1)
+	var values []float64
+	for _, bid := range best {
+		for k := range units {
+			values = append(values, unitsValue(bid, k+1)-unitsValue(bid, k))
+		}
+	}
+	slices.Sort(values)
+	total := 0.0
+	for _, v := range values[max(len(values)-units, 0):] {
+		total += v
+	}
+	return total
+}
@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"auction-simulator/pkg/models"
+)
+
+// MetricsPath is where the server exposes the metrics
+const MetricsPath = "/metrics"
+
+// DurationBuckets are the upper bounds, in seconds, of the auction duration
+// histogram's buckets
+var DurationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Metrics holds the live metrics of a run in the Prometheus text format's
+// terms: counters of finished auctions and their bids, gauges of the latest
+// resource sample and a histogram of auction durations
+type Metrics struct {
+	mu sync.Mutex
+
+	auctionsCompleted int64
+	bidsReceived      int64
+
+	goroutines int
+	memoryMB   float64
+
+	// buckets counts durations up to each of DurationBuckets; a duration
+	// longer than all of them is counted only in durationCount
+	buckets       []int64
+	durationSum   float64
+	durationCount int64
+}
+
+// New creates an empty set of metrics
+func New() *Metrics {
+	return &Metrics{buckets: make([]int64, len(DurationBuckets))}
+}
+
+// ObserveAuction counts a finished auction, its bids and its duration. It
+// has the signature of an auction completion hook.
+func (m *Metrics) ObserveAuction(auction *models.Auction) {
+	seconds := auction.EndTime.Sub(auction.StartTime).Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.auctionsCompleted++
+	m.bidsReceived += int64(auction.TotalBids)
+	for i, le := range DurationBuckets {
+		if seconds <= le {
+			m.buckets[i]++
+		}
+	}
+	m.durationSum += seconds
+	m.durationCount++
+}
+
+// ObserveSample sets the gauges from a resource monitor sample. It has the
+// signature of a resource sample callback.
+func (m *Metrics) ObserveSample(sample models.ResourceSample) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.goroutines = sample.NumGoroutines
+	m.memoryMB = sample.MemoryMB
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	metric(w, "auctions_completed_total", "counter", "Auctions finished so far.")
+	fmt.Fprintf(w, "auctions_completed_total %d\n", m.auctionsCompleted)
+	metric(w, "bids_received_total", "counter", "Bids received by finished auctions.")
+	fmt.Fprintf(w, "bids_received_total %d\n", m.bidsReceived)
+	metric(w, "active_goroutines", "gauge", "Goroutines at the latest resource sample.")
+	fmt.Fprintf(w, "active_goroutines %d\n", m.goroutines)
+	metric(w, "memory_mb", "gauge", "Allocated heap memory in MB at the latest resource sample.")
+	fmt.Fprintf(w, "memory_mb %s\n", formatFloat(m.memoryMB))
+
+	metric(w, "auction_duration_seconds", "histogram", "Time from each auction's start to its close.")
+	for i, le := range DurationBuckets {
+		fmt.Fprintf(w, "auction_duration_seconds_bucket{le=%q} %d\n", formatFloat(le), m.buckets[i])
+	}
+	fmt.Fprintf(w, "auction_duration_seconds_bucket{le=\"+Inf\"} %d\n", m.durationCount)
+	fmt.Fprintf(w, "auction_duration_seconds_sum %s\n", formatFloat(m.durationSum))
+	fmt.Fprintf(w, "auction_duration_seconds_count %d\n", m.durationCount)
+}
+
+// metric writes the HELP and TYPE lines introducing a metric
+func metric(w http.ResponseWriter, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// formatFloat renders v as the shortest decimal that parses back to it
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Server exposes Metrics over HTTP for Prometheus to scrape
+type Server struct {
+	httpServer *http.Server
+}
+
+// NewServer creates a metrics server for m listening on addr
+func NewServer(addr string, m *Metrics) *Server {
+	mux := http.NewServeMux()
+	mux.Handle("GET "+MetricsPath, m)
+	return &Server{httpServer: &http.Server{Addr: addr, Handler: mux}}
+}
+
+// Start begins serving requests in the background
+func (s *Server) Start() {
+	go func() {
+		if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Metrics server error: %v", err)
+		}
+	}()
+}
+
+// Stop shuts the server down, waiting briefly for in-flight scrapes
+func (s *Server) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	s.httpServer.Shutdown(ctx)
+}
@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"auction-simulator/pkg/models"
+)
+
+// TestHandler scrapes the metrics after two auctions and a resource sample;
+// every metric must appear with its type and the values observed
+func TestHandler(t *testing.T) {
+	m := New()
+	start := time.Now()
+	m.ObserveAuction(&models.Auction{TotalBids: 12, StartTime: start, EndTime: start.Add(200 * time.Millisecond)})
+	m.ObserveAuction(&models.Auction{TotalBids: 30, StartTime: start, EndTime: start.Add(3 * time.Second)})
+	m.ObserveSample(models.ResourceSample{NumGoroutines: 57, MemoryMB: 12.5})
+
+	server := httptest.NewServer(NewServer("", m).httpServer.Handler)
+	defer server.Close()
+	resp, err := http.Get(server.URL + MetricsPath)
+	if err != nil {
+		t.Fatalf("GET %s: %v", MetricsPath, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s: %s", MetricsPath, resp.Status)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q, want the Prometheus text format", ct)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{
+		"# TYPE auctions_completed_total counter",
+		"auctions_completed_total 2",
+		"# TYPE bids_received_total counter",
+		"bids_received_total 42",
+		"# TYPE active_goroutines gauge",
+		"active_goroutines 57",
+		"# TYPE memory_mb gauge",
+		"memory_mb 12.5",
+		"# TYPE auction_duration_seconds histogram",
+		`auction_duration_seconds_bucket{le="0.25"} 1`,
+		`auction_duration_seconds_bucket{le="2.5"} 1`,
+		`auction_duration_seconds_bucket{le="5"} 2`,
+		`auction_duration_seconds_bucket{le="+Inf"} 2`,
+		"auction_duration_seconds_sum 3.2",
+		"auction_duration_seconds_count 2",
+	} {
+		if !strings.Contains(string(body), line+"\n") {
+			t.Errorf("metrics lack %q:\n%s", line, body)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"auction-simulator/pkg/models"
+)
+
+// serviceName identifies the simulator in the exported resource attributes
+const serviceName = "auction-simulator"
+
+// Exporter sends auction spans and metrics to an OpenTelemetry collector using
+// OTLP over HTTP with the JSON encoding
+type Exporter struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewExporter creates an exporter for a collector's OTLP/HTTP base URL, e.g.
+// http://localhost:4318. A scheme-less host:port is assumed to be plain HTTP.
+func NewExporter(endpoint string) *Exporter {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "http://" + endpoint
+	}
+	return &Exporter{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		client:   &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Export sends one trace per auction and a gauge per auction metric. Trace
+// and span IDs are derived from the seed and auction ID so repeated runs with
+// the same seed produce the same IDs.
+func (e *Exporter) Export(ctx context.Context, result *models.RunResult) error {
+	if err := e.post(ctx, "/v1/traces", buildTraces(result)); err != nil {
+		return fmt.Errorf("failed to export traces: %w", err)
+	}
+	if err := e.post(ctx, "/v1/metrics", buildMetrics(result)); err != nil {
+		return fmt.Errorf("failed to export metrics: %w", err)
+	}
+	return nil
+}
+
+func (e *Exporter) post(ctx context.Context, path string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("collector returned %s", resp.Status)
+	}
+	return nil
+}
+
+// The types below mirror the subset of the OTLP JSON schema the simulator uses
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"` // int64 is encoded as a string
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type span struct {
+	TraceID           string      `json:"traceId"`
+	SpanID            string      `json:"spanId"`
+	ParentSpanID      string      `json:"parentSpanId,omitempty"`
+	Name              string      `json:"name"`
+	Kind              int         `json:"kind"`
+	StartTimeUnixNano string      `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string      `json:"endTimeUnixNano"`
+	Attributes        []keyValue  `json:"attributes,omitempty"`
+	Events            []spanEvent `json:"events,omitempty"`
+}
+
+type spanEvent struct {
+	TimeUnixNano string     `json:"timeUnixNano"`
+	Name         string     `json:"name"`
+	Attributes   []keyValue `json:"attributes,omitempty"`
+}
+
+type tracesRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type scopeSpans struct {
+	Scope scope  `json:"scope"`
+	Spans []span `json:"spans"`
+}
+
+type metricsRequest struct {
+	ResourceMetrics []resourceMetrics `json:"resourceMetrics"`
+}
+
+type resourceMetrics struct {
+	Resource     resource       `json:"resource"`
+	ScopeMetrics []scopeMetrics `json:"scopeMetrics"`
+}
+
+type scopeMetrics struct {
+	Scope   scope    `json:"scope"`
+	Metrics []metric `json:"metrics"`
+}
+
+type metric struct {
+	Name  string `json:"name"`
+	Unit  string `json:"unit,omitempty"`
+	Gauge gauge  `json:"gauge"`
+}
+
+type gauge struct {
+	DataPoints []dataPoint `json:"dataPoints"`
+}
+
+type dataPoint struct {
+	Attributes   []keyValue `json:"attributes"`
+	TimeUnixNano string     `json:"timeUnixNano"`
+	AsDouble     float64    `json:"asDouble"`
+}
+
+// spanKindInternal is the OTLP SpanKind for in-process operations
+const spanKindInternal = 1
+
+func str(key, v string) keyValue { return keyValue{Key: key, Value: anyValue{StringValue: &v}} }
+
+func integer(key string, v int64) keyValue {
+	s := strconv.FormatInt(v, 10)
+	return keyValue{Key: key, Value: anyValue{IntValue: &s}}
+}
+
+func double(key string, v float64) keyValue {
+	return keyValue{Key: key, Value: anyValue{DoubleValue: &v}}
+}
+
+func nanos(t time.Time) string { return strconv.FormatInt(t.UnixNano(), 10) }
+
+func msDuration(ms float64) time.Duration { return time.Duration(ms * float64(time.Millisecond)) }
+
+func runResource(result *models.RunResult) resource {
+	return resource{Attributes: []keyValue{
+		str("service.name", serviceName),
+		integer("simulator.seed", result.Config.Seed),
+	}}
+}
+
+func traceID(seed int64, auctionID int) string {
+	return fmt.Sprintf("%016x%016x", uint64(seed), uint64(auctionID)+1)
+}
+
+func spanID(auctionID, n int) string {
+	return fmt.Sprintf("%016x", uint64(auctionID+1)<<8|uint64(n+1))
+}
+
+// buildTraces turns each auction into a root span with a child span per phase;
+// bids become events on the root span
+func buildTraces(result *models.RunResult) tracesRequest {
+	var spans []span
+	for _, a := range result.Auctions {
+		tid := traceID(result.Config.Seed, a.ID)
+		root := spanID(a.ID, 0)
+
+		attrs := []keyValue{
+			integer("auction.id", int64(a.ID)),
+			integer("auction.bids", int64(a.TotalBids)),
+			str("auction.status", string(a.Status)),
+			double("auction.duration_ms", float64(a.EndTime.Sub(a.StartTime))/float64(time.Millisecond)),
+		}
+		if a.Winner != nil {
+			attrs = append(attrs,
+				integer("auction.winner.bidder_id", int64(a.Winner.BidderID)),
+				double("auction.winner.amount", a.Winner.Amount),
+			)
+		}
+
+		events := make([]spanEvent, 0, len(a.Bids))
+		for _, bid := range a.Bids {
+			events = append(events, spanEvent{
+				TimeUnixNano: nanos(bid.Timestamp),
+				Name:         "bid",
+				Attributes: []keyValue{
+					integer("bid.bidder_id", int64(bid.BidderID)),
+					double("bid.amount", bid.Amount),
+				},
+			})
+		}
+
+		spans = append(spans, span{
+			TraceID: tid, SpanID: root, Name: fmt.Sprintf("auction %d", a.ID), Kind: spanKindInternal,
+			StartTimeUnixNano: nanos(a.StartTime), EndTimeUnixNano: nanos(a.EndTime),
+			Attributes: attrs, Events: events,
+		})
+
+		// Phase spans are laid out the same way as in the trace.json timeline
+		p := a.Phases
+		notifyEnd := a.StartTime.Add(msDuration(p.BidderNotificationMs))
+		phases := []struct {
+			name       string
+			start, end time.Time
+		}{
+			{"attribute generation", a.StartTime.Add(-msDuration(p.AttributeGenerationMs)), a.StartTime},
+			{"bidder notification", a.StartTime, notifyEnd},
+			{"bid collection", notifyEnd, notifyEnd.Add(msDuration(p.BidCollectionMs))},
+			{"winner determination", a.EndTime, a.EndTime.Add(msDuration(p.WinnerDeterminationMs))},
+		}
+		if p.RevealMs > 0 {
+			phases = append(phases, struct {
+				name       string
+				start, end time.Time
+			}{"reveal", a.EndTime.Add(-msDuration(p.RevealMs)), a.EndTime})
+		}
+		for i, ph := range phases {
+			spans = append(spans, span{
+				TraceID: tid, SpanID: spanID(a.ID, i+1), ParentSpanID: root, Name: ph.name, Kind: spanKindInternal,
+				StartTimeUnixNano: nanos(ph.start), EndTimeUnixNano: nanos(ph.end),
+			})
+		}
+	}
+
+	return tracesRequest{ResourceSpans: []resourceSpans{{
+		Resource:   runResource(result),
+		ScopeSpans: []scopeSpans{{Scope: scope{Name: serviceName}, Spans: spans}},
+	}}}
+}
+
+// buildMetrics reports per-auction gauges tagged with the auction ID
+func buildMetrics(result *models.RunResult) metricsRequest {
+	bids := metric{Name: "auction.bids", Unit: "{bid}"}
+	duration := metric{Name: "auction.duration", Unit: "ms"}
+	winning := metric{Name: "auction.winning_bid"}
+
+	for _, a := range result.Auctions {
+		labels := []keyValue{integer("auction.id", int64(a.ID))}
+		ts := nanos(a.EndTime)
+
+		bids.Gauge.DataPoints = append(bids.Gauge.DataPoints, dataPoint{
+			Attributes: labels, TimeUnixNano: ts, AsDouble: float64(a.TotalBids),
+		})
+		duration.Gauge.DataPoints = append(duration.Gauge.DataPoints, dataPoint{
+			Attributes: labels, TimeUnixNano: ts, AsDouble: float64(a.EndTime.Sub(a.StartTime)) / float64(time.Millisecond),
+		})
+		if a.Winner != nil {
+			winning.Gauge.DataPoints = append(winning.Gauge.DataPoints, dataPoint{
+				Attributes: labels, TimeUnixNano: ts, AsDouble: a.Winner.Amount,
+			})
+		}
+	}
+
+	return metricsRequest{ResourceMetrics: []resourceMetrics{{
+		Resource:     runResource(result),
+		ScopeMetrics: []scopeMetrics{{Scope: scope{Name: serviceName}, Metrics: []metric{bids, duration, winning}}},
+	}}}
+}
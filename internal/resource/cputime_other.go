@@ -0,0 +1,11 @@
+//go:build !unix
+
+package resource
+
+import "time"
+
+// processCPUTime is unavailable on this platform, so CPU quotas are not
+// enforced and CPU usage is not measured
+func processCPUTime() (time.Duration, bool) {
+	return 0, false
+}
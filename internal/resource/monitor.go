@@ -1,26 +1,49 @@
 package resource
 
 import (
+	"fmt"
+	"math"
 	"runtime"
+	"sort"
 	"sync"
 	"time"
+
+	"auction-simulator/pkg/models"
 )
 
+// SampleInterval is how often a running simulation samples resource usage
+const SampleInterval = 100 * time.Millisecond
+
 // Monitor tracks resource usage during execution
 type Monitor struct {
-	startTime     time.Time
-	samples       []Sample
-	mu            sync.Mutex
-	stopChan      chan struct{}
-	sampleTicker  *time.Ticker
+	startTime    time.Time
+	samples      []Sample
+	mu           sync.Mutex
+	stopChan     chan struct{}
+	sampleTicker *time.Ticker
+
+	// cpuStart is the process CPU time when monitoring started, and cpuCores
+	// the average number of cores used between Start and Stop
+	cpuStart time.Duration
+	cpuCores float64
+
+	// procs is GOMAXPROCS when monitoring started, the capacity CPU percentages
+	// are measured against; lastCPU and lastSample are the process CPU time
+	// and clock at the previous sample
+	procs      int
+	lastCPU    time.Duration
+	lastSample time.Time
+
+	// limitMB is the allocated memory above which a sample calls onLimit
+	limitMB int64
+	onLimit func()
+
+	// onSample is called with every sample taken, if set
+	onSample func(Sample)
 }
 
 // Sample represents a single resource measurement
-type Sample struct {
-	Timestamp    time.Time
-	MemoryMB     float64
-	NumGoroutines int
-}
+type Sample = models.ResourceSample
 
 // NewMonitor creates a new resource monitor
 func NewMonitor() *Monitor {
@@ -30,9 +53,26 @@ func NewMonitor() *Monitor {
 	}
 }
 
+// SetMemoryLimitCallback makes every sample whose allocated memory exceeds
+// limitMB call fn, from the sampling goroutine, so fn must not block. The
+// limit is soft: Go cannot cap its heap, so fn can only slow new work down.
+// It must be called before Start.
+func (m *Monitor) SetMemoryLimitCallback(limitMB int64, fn func()) {
+	m.limitMB, m.onLimit = limitMB, fn
+}
+
+// SetSampleCallback makes every sample call fn, from the sampling goroutine,
+// so fn must not block. It must be called before Start.
+func (m *Monitor) SetSampleCallback(fn func(Sample)) {
+	m.onSample = fn
+}
+
 // Start begins monitoring resource usage
 func (m *Monitor) Start(interval time.Duration) {
 	m.startTime = time.Now()
+	m.cpuStart, _ = processCPUTime()
+	m.procs = runtime.GOMAXPROCS(0)
+	m.lastCPU, m.lastSample = m.cpuStart, m.startTime
 	m.sampleTicker = time.NewTicker(interval)
 
 	go func() {
@@ -56,6 +96,12 @@ func (m *Monitor) Stop() {
 
 	// Take one final sample
 	m.takeSample()
+
+	if cpu, ok := processCPUTime(); ok {
+		if wall := time.Since(m.startTime); wall > 0 {
+			m.cpuCores = float64(cpu-m.cpuStart) / float64(wall)
+		}
+	}
 }
 
 // takeSample captures current resource usage
@@ -64,14 +110,49 @@ func (m *Monitor) takeSample() {
 	runtime.ReadMemStats(&memStats)
 
 	sample := Sample{
-		Timestamp:    time.Now(),
-		MemoryMB:     float64(memStats.Alloc) / 1024 / 1024,
+		Timestamp:     time.Now(),
+		MemoryMB:      float64(memStats.Alloc) / 1024 / 1024,
 		NumGoroutines: runtime.NumGoroutine(),
 	}
+	cpu, cpuOK := processCPUTime()
 
+	m.mu.Lock()
+	// The CPU time spent since the previous sample, as a share of what
+	// GOMAXPROCS cores could have spent over the same wall time
+	if wall := sample.Timestamp.Sub(m.lastSample); cpuOK && wall > 0 {
+		sample.CPUPercent = 100 * float64(cpu-m.lastCPU) / (float64(wall) * float64(m.procs))
+		m.lastCPU, m.lastSample = cpu, sample.Timestamp
+	}
+	m.mu.Unlock()
+	m.record(sample)
+}
+
+// record stores a sample and reports it to the sample callback and, if it
+// exceeds the memory limit, the limit callback
+func (m *Monitor) record(sample Sample) {
 	m.mu.Lock()
 	m.samples = append(m.samples, sample)
 	m.mu.Unlock()
+
+	if m.onSample != nil {
+		m.onSample(sample)
+	}
+	if m.onLimit != nil && m.limitMB > 0 && sample.MemoryMB > float64(m.limitMB) {
+		m.onLimit()
+	}
+}
+
+// StartTime returns when monitoring started, the origin of the samples' time
+// series
+func (m *Monitor) StartTime() time.Time {
+	return m.startTime
+}
+
+// GetSamples returns a copy of every sample taken so far, in time order
+func (m *Monitor) GetSamples() []Sample {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Sample(nil), m.samples...)
 }
 
 // GetPeakMemoryMB returns the peak memory usage in MB
@@ -110,7 +191,94 @@ func (m *Monitor) GetAvgGoroutines() int {
 	return total / len(m.samples)
 }
 
-// GetMaxCPUs returns the maximum number of CPUs being used
+// GetMemoryPercentilesMB returns the p50/p95/p99 memory usage in MB
+func (m *Monitor) GetMemoryPercentilesMB() models.Percentiles {
+	m.mu.Lock()
+	values := make([]float64, len(m.samples))
+	for i, s := range m.samples {
+		values[i] = s.MemoryMB
+	}
+	m.mu.Unlock()
+
+	return Percentiles(values)
+}
+
+// GetGoroutinePercentiles returns the p50/p95/p99 goroutine counts
+func (m *Monitor) GetGoroutinePercentiles() models.Percentiles {
+	m.mu.Lock()
+	values := make([]float64, len(m.samples))
+	for i, s := range m.samples {
+		values[i] = float64(s.NumGoroutines)
+	}
+	m.mu.Unlock()
+
+	return Percentiles(values)
+}
+
+// Percentiles sorts values in place and returns their nearest-rank percentiles.
+// An empty slice yields all zeros.
+func Percentiles(values []float64) models.Percentiles {
+	if len(values) == 0 {
+		return models.Percentiles{}
+	}
+	sort.Float64s(values)
+
+	rank := func(p float64) float64 {
+		i := int(math.Ceil(p/100*float64(len(values)))) - 1
+		return values[max(i, 0)]
+	}
+	return models.Percentiles{
+		P50: rank(50),
+		P95: rank(95),
+		P99: rank(99),
+	}
+}
+
+// GetAvgCPUCores returns the average number of cores the process used while
+// monitored, or zero if CPU time cannot be measured. It is set by Stop.
+func (m *Monitor) GetAvgCPUCores() float64 {
+	return m.cpuCores
+}
+
+// GetAvgCPUPercent returns the process's CPU utilization over the whole
+// monitored period as a percentage of GOMAXPROCS cores, or zero if CPU time
+// cannot be measured. It is set by Stop.
+func (m *Monitor) GetAvgCPUPercent() float64 {
+	if m.procs == 0 {
+		return 0
+	}
+	return 100 * m.cpuCores / float64(m.procs)
+}
+
+// GetPeakCPUPercent returns the highest CPU utilization measured between two
+// consecutive samples, as a percentage of GOMAXPROCS cores
+func (m *Monitor) GetPeakCPUPercent() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	peak := 0.0
+	for _, s := range m.samples {
+		peak = max(peak, s.CPUPercent)
+	}
+	return peak
+}
+
+// GetMaxCPUs returns the maximum number of CPUs the run may use (GOMAXPROCS);
+// see GetAvgCPUPercent for how much of them it actually used
 func (m *Monitor) GetMaxCPUs() int {
 	return runtime.GOMAXPROCS(0)
 }
+
+// EffectiveCPUs validates a requested CPU count and clamps it to the number of
+// logical CPUs available. The boolean reports whether the request was clamped.
+func EffectiveCPUs(requested int) (int, bool, error) {
+	if requested <= 0 {
+		return 0, false, fmt.Errorf("CPU count must be positive, got %d", requested)
+	}
+
+	available := runtime.NumCPU()
+	if requested > available {
+		return available, true, nil
+	}
+	return requested, false, nil
+}
@@ -0,0 +1,40 @@
+package resource
+
+import (
+	"testing"
+	"time"
+)
+
+// TestMemoryLimitCallback records synthetic samples around the limit; the
+// callback must fire once for each sample strictly above it, and never
+// without a limit
+func TestMemoryLimitCallback(t *testing.T) {
+	tests := []struct {
+		name    string
+		limitMB int64
+		samples []float64
+		fired   int
+	}{
+		{"below the limit", 100, []float64{10, 50, 99.9}, 0},
+		{"at the limit", 100, []float64{100}, 0},
+		{"over the limit", 100, []float64{100.1}, 1},
+		{"each sample over", 100, []float64{50, 150, 80, 200, 300}, 3},
+		{"no limit", 0, []float64{1e6}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMonitor()
+			fired := 0
+			m.SetMemoryLimitCallback(tt.limitMB, func() { fired++ })
+			for _, mb := range tt.samples {
+				m.record(Sample{Timestamp: time.Now(), MemoryMB: mb})
+			}
+			if fired != tt.fired {
+				t.Errorf("callback fired %d times, want %d", fired, tt.fired)
+			}
+			if got := len(m.GetSamples()); got != len(tt.samples) {
+				t.Errorf("monitor kept %d samples, want %d", got, len(tt.samples))
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package resource
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ThrottlePeriod bounds how much unused quota a throttle saves up, like a
+// cgroup's CFS period: after an idle spell the process may use at most Quota
+// times this much CPU time at full speed before paced work is held back
+const ThrottlePeriod = 20 * time.Millisecond
+
+// throttleCheckInterval is how often a throttle measures process CPU time;
+// in between, paced work relies on the last measurement, so the pacing itself
+// stays cheap under heavy load
+const throttleCheckInterval = 250 * time.Microsecond
+
+// Throttle holds the process to a fractional CPU quota, which GOMAXPROCS
+// cannot express. Quota accrues as CPU time per wall-clock time; whatever the
+// process uses, paced or not, is charged against it, and work that calls Pace
+// is held back while the process is in debt. Held-back callers queue on the
+// throttle's lock rather than each sleeping, so thousands of them do not all
+// wake, and burn CPU, whenever quota comes free.
+type Throttle struct {
+	Quota float64
+
+	mu      sync.Mutex
+	checked time.Time
+	cpu     time.Duration
+	credit  float64 // unused quota in nanoseconds of CPU time; negative is debt
+
+	throttled atomic.Int64
+}
+
+// NewThrottle creates a throttle for quota cores. It reports false if process
+// CPU time cannot be measured on this platform, in which case the throttle
+// never holds work back.
+func NewThrottle(quota float64) (*Throttle, bool) {
+	_, ok := processCPUTime()
+	return &Throttle{Quota: quota}, ok
+}
+
+// Pace blocks while the process has used more CPU time than its quota allows.
+// A nil throttle never blocks.
+func (t *Throttle) Pace() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if wait := t.overrun(); wait > 0 {
+		t.throttled.Add(int64(wait))
+		time.Sleep(wait)
+	}
+}
+
+// overrun charges the CPU time used since the last check against the quota
+// and returns how long paying off any debt takes. t.mu must be held.
+func (t *Throttle) overrun() time.Duration {
+	now := time.Now()
+	if now.Sub(t.checked) < throttleCheckInterval {
+		return 0
+	}
+	cpu, ok := processCPUTime()
+	if !ok {
+		return 0
+	}
+
+	allowance := t.Quota * float64(ThrottlePeriod)
+	if t.checked.IsZero() {
+		t.credit = allowance
+	} else {
+		t.credit = min(t.credit+t.Quota*float64(now.Sub(t.checked)), allowance)
+		t.credit -= float64(cpu - t.cpu)
+	}
+	t.checked, t.cpu = now, cpu
+	if t.credit >= 0 {
+		return 0
+	}
+	return time.Duration(-t.credit / t.Quota)
+}
+
+// Throttled returns how long paced work was held back in total
+func (t *Throttle) Throttled() time.Duration {
+	return time.Duration(t.throttled.Load())
+}
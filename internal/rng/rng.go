@@ -0,0 +1,70 @@
+// Package rng is the simulator's single source of randomness. Every stream,
+// from the top-level stream seeded by the run seed to each bidder's own, comes
+// from the run's Factory. There is no shared global stream or generator:
+// callers own their streams and derive child streams from them.
+package rng
+
+import (
+	"fmt"
+	"math/rand"
+	randv2 "math/rand/v2"
+
+	"auction-simulator/pkg/models"
+)
+
+// Factory returns seeded sources for one generator. The zero Factory is
+// math/rand's.
+type Factory func(seed int64) rand.Source
+
+// Source returns the Factory of a built-in generator
+func Source(gen models.Generator) (Factory, error) {
+	switch gen {
+	case models.GeneratorMathRand:
+		return func(seed int64) rand.Source { return rand.NewSource(seed) }, nil
+	case models.GeneratorPCG:
+		return newPCG, nil
+	}
+	return nil, fmt.Errorf("unknown random number generator %q (want %q or %q)", gen,
+		models.GeneratorMathRand, models.GeneratorPCG)
+}
+
+// New returns an independent stream seeded with seed. It is not safe for
+// concurrent use.
+func (f Factory) New(seed int64) *rand.Rand {
+	if f == nil {
+		return rand.New(rand.NewSource(seed))
+	}
+	return rand.New(f(seed))
+}
+
+// pcgSource adapts math/rand/v2's PCG to a math/rand source
+type pcgSource struct {
+	*randv2.PCG
+}
+
+// newPCG returns a PCG source seeded with seed; see Seed
+func newPCG(seed int64) rand.Source {
+	s := pcgSource{&randv2.PCG{}}
+	s.Seed(seed)
+	return s
+}
+
+// Seed spreads seed over PCG's 128-bit state with SplitMix64, so the nearby
+// seeds streams are derived with (run seed + offset) give unrelated streams
+func (s pcgSource) Seed(seed int64) {
+	hi := splitMix64(uint64(seed))
+	s.PCG.Seed(hi, splitMix64(hi))
+}
+
+// Int63 returns a non-negative 63-bit value
+func (s pcgSource) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+// splitMix64 is the SplitMix64 finalizer, a bijective 64-bit mix
+func splitMix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ x>>30) * 0xbf58476d1ce4e5b9
+	x = (x ^ x>>27) * 0x94d049bb133111eb
+	return x ^ x>>31
+}
@@ -0,0 +1,97 @@
+package rng
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"auction-simulator/pkg/models"
+)
+
+// xorshiftSource is a minimal caller-supplied generator
+type xorshiftSource struct {
+	state uint64
+}
+
+func (s *xorshiftSource) Seed(seed int64) {
+	s.state = splitMix64(uint64(seed)) | 1 // A zero state would stay zero
+}
+
+func (s *xorshiftSource) Int63() int64 {
+	s.state ^= s.state << 13
+	s.state ^= s.state >> 7
+	s.state ^= s.state << 17
+	return int64(s.state >> 1)
+}
+
+func newXorshift(seed int64) rand.Source {
+	s := &xorshiftSource{}
+	s.Seed(seed)
+	return s
+}
+
+// draws takes a mix of the draws the simulator makes from a stream
+func draws(r *rand.Rand) []float64 {
+	out := make([]float64, 0, 30)
+	for range 10 {
+		out = append(out, r.Float64(), float64(r.Intn(490)), float64(r.Int63()))
+	}
+	return out
+}
+
+// TestDeterminism checks, for each supported generator, that streams created
+// with the same seed draw the same values, and that streams with nearby seeds,
+// as the simulator derives them, do not
+func TestDeterminism(t *testing.T) {
+	mathRand, _ := Source(models.GeneratorMathRand)
+	pcg, _ := Source(models.GeneratorPCG)
+	tests := []struct {
+		name   string
+		source Factory
+	}{
+		{string(models.GeneratorMathRand), mathRand},
+		{string(models.GeneratorPCG), pcg},
+		{string(models.GeneratorCustom), newXorshift},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, seed := range []int64{0, 1, 42, -7, 1 << 62} {
+				first, second := draws(tt.source.New(seed)), draws(tt.source.New(seed))
+				if !slices.Equal(first, second) {
+					t.Errorf("seed %d: two streams drew different values", seed)
+				}
+				if next := draws(tt.source.New(seed + 1)); slices.Equal(first, next) {
+					t.Errorf("seeds %d and %d drew the same values", seed, seed+1)
+				}
+			}
+
+			// Reseeding restarts the stream from the seed's first value
+			r := tt.source.New(42)
+			want := draws(r)
+			r.Seed(42)
+			if got := draws(r); !slices.Equal(got, want) {
+				t.Errorf("reseeding with 42 did not restart the stream")
+			}
+		})
+	}
+}
+
+// TestMathRandSeeding checks that the math-rand generator, like the zero
+// Factory, maps seeds straight onto math/rand's own source, so runs from
+// before -rng draw the same values
+func TestMathRandSeeding(t *testing.T) {
+	source, _ := Source(models.GeneratorMathRand)
+	want := draws(rand.New(rand.NewSource(42)))
+	if got := draws(source.New(42)); !slices.Equal(got, want) {
+		t.Errorf("math-rand stream for seed 42 differs from math/rand's")
+	}
+	if got := draws(Factory(nil).New(42)); !slices.Equal(got, want) {
+		t.Errorf("zero Factory's stream for seed 42 differs from math/rand's")
+	}
+}
+
+func TestSourceUnknown(t *testing.T) {
+	if _, err := Source("mersenne"); err == nil {
+		t.Error("unknown generator was accepted")
+	}
+}
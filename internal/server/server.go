@@ -0,0 +1,133 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"auction-simulator/internal/manager"
+	"auction-simulator/pkg/models"
+)
+
+// Controller is implemented by anything that can control a running simulation
+type Controller interface {
+	CancelAuction(id int) error
+	SubmitBid(auctionID int, bid models.Bid) error
+	CurrentLeader(auctionID int) (*models.Bid, error)
+	Snapshot(auctionID int) (models.AuctionView, error)
+}
+
+// Server exposes runtime control of a running simulation over HTTP
+type Server struct {
+	httpServer *http.Server
+}
+
+// NewServer creates a control server for the given simulation listening on addr
+func NewServer(addr string, mgr Controller) *Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("DELETE /auctions/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			http.Error(w, "invalid auction id", http.StatusBadRequest)
+			return
+		}
+
+		if err := mgr.CancelAuction(id); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	mux.HandleFunc("GET /auctions/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			http.Error(w, "invalid auction id", http.StatusBadRequest)
+			return
+		}
+
+		view, err := mgr.Snapshot(id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(view)
+	})
+
+	mux.HandleFunc("GET /auctions/{id}/leader", func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			http.Error(w, "invalid auction id", http.StatusBadRequest)
+			return
+		}
+
+		leader, err := mgr.CurrentLeader(id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		// The leader is provisional: it may change until the auction closes
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(struct {
+			AuctionID   int         `json:"auction_id"`
+			Provisional bool        `json:"provisional"`
+			Leader      *models.Bid `json:"leader"`
+		}{id, true, leader})
+	})
+
+	mux.HandleFunc("POST /auctions/{id}/bids", func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			http.Error(w, "invalid auction id", http.StatusBadRequest)
+			return
+		}
+
+		var bid models.Bid
+		if err := json.NewDecoder(r.Body).Decode(&bid); err != nil {
+			http.Error(w, "invalid bid: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if bid.Timestamp.IsZero() {
+			bid.Timestamp = time.Now()
+		}
+
+		switch err := mgr.SubmitBid(id, bid); {
+		case err == nil:
+			w.WriteHeader(http.StatusAccepted)
+		case errors.Is(err, manager.ErrInvalidBid):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, manager.ErrUnknownAuction):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, manager.ErrAuctionClosed):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		}
+	})
+
+	return &Server{
+		httpServer: &http.Server{Addr: addr, Handler: mux},
+	}
+}
+
+// Start begins serving requests in the background
+func (s *Server) Start() {
+	go func() {
+		if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Control server error: %v", err)
+		}
+	}()
+}
+
+// Stop shuts the server down, waiting briefly for in-flight requests
+func (s *Server) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	s.httpServer.Shutdown(ctx)
+}
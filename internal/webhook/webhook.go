@@ -0,0 +1,117 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"auction-simulator/pkg/models"
+)
+
+// Defaults for outbound delivery
+const (
+	DefaultConcurrency = 4
+	DefaultAttempts    = 3
+	DefaultTimeout     = 5 * time.Second
+)
+
+// Sender POSTs finished auctions to a webhook URL in the background. Requests
+// are retried with exponential backoff and at most a fixed number are in flight
+// at once; failures are logged and counted, never returned to the caller.
+type Sender struct {
+	url      string
+	client   *http.Client
+	attempts int
+	backoff  time.Duration
+
+	// slots bounds the number of concurrent outbound requests
+	slots chan struct{}
+	wg    sync.WaitGroup
+
+	delivered atomic.Int64
+	failed    atomic.Int64
+}
+
+// NewSender creates a sender for url allowing concurrency requests in flight;
+// a non-positive concurrency uses DefaultConcurrency
+func NewSender(url string, concurrency int) *Sender {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	return &Sender{
+		url:      url,
+		client:   &http.Client{Timeout: DefaultTimeout},
+		attempts: DefaultAttempts,
+		backoff:  200 * time.Millisecond,
+		slots:    make(chan struct{}, concurrency),
+	}
+}
+
+// Send queues the auction for delivery and returns immediately. The auction
+// is encoded before Send returns, so later changes to it are not sent.
+func (s *Sender) Send(auction *models.Auction) {
+	body, err := json.Marshal(auction)
+	if err != nil {
+		log.Printf("Webhook: failed to encode auction %d: %v", auction.ID, err)
+		s.failed.Add(1)
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		s.slots <- struct{}{}
+		defer func() { <-s.slots }()
+
+		if err := s.deliver(body); err != nil {
+			log.Printf("Webhook: giving up on auction %d: %v", auction.ID, err)
+			s.failed.Add(1)
+			return
+		}
+		s.delivered.Add(1)
+	}()
+}
+
+// deliver POSTs body, retrying on transport errors and non-2xx responses
+func (s *Sender) deliver(body []byte) error {
+	var err error
+	for attempt := 0; attempt < s.attempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(s.backoff << (attempt - 1))
+		}
+		if err = s.post(body); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("%d attempts failed, last error: %w", s.attempts, err)
+}
+
+func (s *Sender) post(body []byte) error {
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Close waits for every queued delivery to finish and reports the outcome
+func (s *Sender) Close() models.WebhookReport {
+	s.wg.Wait()
+	return models.WebhookReport{
+		Delivered: int(s.delivered.Load()),
+		Failed:    int(s.failed.Load()),
+	}
+}
@@ -0,0 +1,154 @@
+package models
+
+import (
+	"slices"
+	"sort"
+)
+
+// BundleBid is a bid on a bundle of a combinatorial auction's items, each
+// item covering a fixed block of attributes. Items lists them in ascending
+// order; Valuation is what the bidder thinks the bundle is worth.
+type BundleBid struct {
+	Items     []int   `json:"items"`
+	Amount    float64 `json:"amount"`
+	Cents     int64   `json:"amount_cents,omitempty"`
+	Valuation float64 `json:"valuation,omitempty"`
+}
+
+// Mask returns the bundle's items as a bit set; every item must be within
+// [0, MaxBundleItems)
+func (b BundleBid) Mask() uint32 {
+	var mask uint32
+	for _, item := range b.Items {
+		mask |= 1 << item
+	}
+	return mask
+}
+
+// MaxBundleItems bounds the items of a combinatorial auction, keeping winner
+// determination's 2^items state space small
+const MaxBundleItems = 10
+
+// ItemAttributes returns the half-open range of attribute indices that item
+// covers when the 20 attributes are split into items contiguous blocks
+func ItemAttributes(item, items int) (lo, hi int) {
+	return item * 20 / items, (item + 1) * 20 / items
+}
+
+// clearBundles determines a combinatorial auction's winners: the set of
+// non-overlapping bundle bids, at most one per bidder, with the greatest total
+// amount. Each bidder's highest bid carries its bundles; ties keep the earlier
+// bidders' allocation. Must be called with a.mu held.
+func (a *Auction) clearBundles() {
+	a.Winner, a.Allocations, a.Winners, a.ClearedValue = nil, nil, nil, 0
+
+	best := make(map[int]*Bid)
+	for i := range a.Bids {
+		bid := &a.Bids[i]
+		if prev, ok := best[bid.BidderID]; ok {
+			cmp := a.compareAmounts(bid, prev)
+			if cmp < 0 || cmp == 0 && !bid.Timestamp.Before(prev.Timestamp) {
+				continue
+			}
+		}
+		best[bid.BidderID] = bid
+	}
+	bidders := make([]*Bid, 0, len(best))
+	for _, bid := range best {
+		bidders = append(bidders, bid)
+	}
+	sort.Slice(bidders, func(i, j int) bool {
+		if !bidders[i].Timestamp.Equal(bidders[j].Timestamp) {
+			return bidders[i].Timestamp.Before(bidders[j].Timestamp)
+		}
+		return bidders[i].BidderID < bidders[j].BidderID
+	})
+
+	sets := make([][]BundleBid, len(bidders))
+	for i, bid := range bidders {
+		sets[i] = a.BundlesOf(*bid)
+	}
+	amount := func(b BundleBid) float64 {
+		if a.IntegerAmounts {
+			return float64(b.Cents)
+		}
+		return b.Amount
+	}
+
+	// The winner is the bidder whose winning bundle bid is highest
+	var top float64
+	var cents int64
+	for i, k := range SolveBundles(sets, a.BundleItems, amount) {
+		if k < 0 {
+			continue
+		}
+		b := sets[i][k]
+		a.Allocations = append(a.Allocations, Allocation{BidderID: bidders[i].BidderID, Units: len(b.Items), Items: b.Items, Paid: b.Amount, PaidCents: b.Cents})
+		a.Winners = append(a.Winners, *bidders[i])
+		a.ClearedValue += b.Amount
+		cents += b.Cents
+		if a.Winner == nil || amount(b) > top {
+			a.Winner, top = bidders[i], amount(b)
+		}
+	}
+	if a.IntegerAmounts {
+		a.ClearedValue = FromCents(cents)
+	}
+}
+
+// BundlesOf returns the bundles bid carries in the auction, leaving out any
+// that are empty or name an item the auction does not have. A bid without
+// bundles, such as an external one, bids its amount on every item together.
+func (a *Auction) BundlesOf(bid Bid) []BundleBid {
+	if len(bid.Bundles) > 0 {
+		return slices.DeleteFunc(slices.Clone(bid.Bundles), func(b BundleBid) bool {
+			return len(b.Items) == 0 || slices.ContainsFunc(b.Items, func(item int) bool { return item < 0 || item >= a.BundleItems })
+		})
+	}
+	whole := BundleBid{Items: make([]int, a.BundleItems), Amount: bid.Amount, Cents: bid.Cents, Valuation: bid.Valuation}
+	for i := range whole.Items {
+		whole.Items[i] = i
+	}
+	return []BundleBid{whole}
+}
+
+// SolveBundles picks at most one bundle from each of sets so that no two
+// picked bundles share an item and their total value is greatest. It returns
+// the index of the bundle picked from each set, or -1 for none; ties favour
+// earlier sets. The solver is a dynamic program over sets and subsets of the
+// items: best[s] is the most the sets seen so far are worth within items s.
+// With n sets of at most k bundles over m items it runs in O(n * k * 2^m)
+// time and keeps O(n * 2^m) choices to recover the picks; MaxBundleItems
+// bounds m.
+func SolveBundles(sets [][]BundleBid, items int, value func(BundleBid) float64) []int {
+	states := 1 << items
+	best := make([]float64, states)
+	choice := make([][]int32, len(sets)) // bundle taken at each state, or -1
+	for i, set := range sets {
+		choice[i] = make([]int32, states)
+		next := slices.Clone(best)
+		for s := range next {
+			choice[i][s] = -1
+			for k, b := range set {
+				mask := int(b.Mask())
+				if mask&s != mask {
+					continue
+				}
+				if v := best[s&^mask] + value(b); v > next[s] {
+					next[s], choice[i][s] = v, int32(k)
+				}
+			}
+		}
+		best = next
+	}
+
+	picks := make([]int, len(sets))
+	s := states - 1
+	for i := len(sets) - 1; i >= 0; i-- {
+		picks[i] = int(choice[i][s])
+		if picks[i] >= 0 {
+			s &^= int(sets[i][picks[i]].Mask())
+		}
+	}
+	return picks
+}
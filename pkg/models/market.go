@@ -0,0 +1,103 @@
+package models
+
+import "sort"
+
+// DemandPoint is one step of a demand schedule: the most a bidder will pay
+// for its Quantity-th unit. Marginal prices normally fall as Quantity rises.
+type DemandPoint struct {
+	Quantity int     `json:"quantity"`
+	Price    float64 `json:"price"`
+	Cents    int64   `json:"price_cents,omitempty"`
+}
+
+// Allocation is what one bidder won in a multi-unit or combinatorial auction
+type Allocation struct {
+	BidderID  int     `json:"bidder_id"`
+	Units     int     `json:"units"`
+	Items     []int   `json:"items,omitempty"`
+	Paid      float64 `json:"paid"`
+	PaidCents int64   `json:"paid_cents,omitempty"`
+}
+
+// clearMarket allocates a multi-unit auction's units to the highest marginal
+// bids across every bidder's demand schedule. Each bidder's highest bid is its
+// schedule; a bid without one demands a single unit at its amount. Ties go to
+// the earlier bid. Must be called with a.mu held.
+func (a *Auction) clearMarket() {
+	a.Winner, a.Allocations, a.Winners = nil, nil, nil
+	a.ClearingPrice, a.ClearingPriceCents = 0, 0
+
+	best := make(map[int]*Bid)
+	for i := range a.Bids {
+		bid := &a.Bids[i]
+		if prev, ok := best[bid.BidderID]; ok {
+			cmp := a.compareAmounts(bid, prev)
+			if cmp < 0 || cmp == 0 && !bid.Timestamp.Before(prev.Timestamp) {
+				continue
+			}
+		}
+		best[bid.BidderID] = bid
+	}
+
+	type marginal struct {
+		bid   *Bid
+		point DemandPoint
+	}
+	var marginals []marginal
+	for _, bid := range best {
+		schedule := bid.Demand
+		if len(schedule) == 0 {
+			schedule = []DemandPoint{{Quantity: 1, Price: bid.Amount, Cents: bid.Cents}}
+		}
+		for _, point := range schedule {
+			if a.meetsReserve(point.Price, point.Cents) {
+				marginals = append(marginals, marginal{bid, point})
+			}
+		}
+	}
+	sort.Slice(marginals, func(i, j int) bool {
+		x, y := marginals[i], marginals[j]
+		if cmp := a.compareAmounts(&Bid{Amount: x.point.Price, Cents: x.point.Cents}, &Bid{Amount: y.point.Price, Cents: y.point.Cents}); cmp != 0 {
+			return cmp > 0
+		}
+		if !x.bid.Timestamp.Equal(y.bid.Timestamp) {
+			return x.bid.Timestamp.Before(y.bid.Timestamp)
+		}
+		if x.bid.BidderID != y.bid.BidderID {
+			return x.bid.BidderID < y.bid.BidderID
+		}
+		return x.point.Quantity < y.point.Quantity
+	})
+
+	accepted := marginals[:min(a.Units, len(marginals))]
+	if len(accepted) == 0 {
+		return
+	}
+	a.Winner = accepted[0].bid
+	clearing := accepted[len(accepted)-1].point
+	a.ClearingPrice, a.ClearingPriceCents = clearing.Price, clearing.Cents
+
+	index := make(map[int]int)
+	for _, m := range accepted {
+		i, ok := index[m.bid.BidderID]
+		if !ok {
+			i = len(a.Allocations)
+			index[m.bid.BidderID] = i
+			a.Allocations = append(a.Allocations, Allocation{BidderID: m.bid.BidderID})
+			a.Winners = append(a.Winners, *m.bid)
+		}
+		price := m.point
+		if a.Pricing == PricingUniform {
+			price = clearing
+		}
+		alloc := &a.Allocations[i]
+		alloc.Units++
+		alloc.Paid += price.Price
+		alloc.PaidCents += price.Cents
+	}
+	if a.IntegerAmounts {
+		for i := range a.Allocations {
+			a.Allocations[i].Paid = FromCents(a.Allocations[i].PaidCents)
+		}
+	}
+}
@@ -1,6 +1,16 @@
 package models
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -9,63 +19,1288 @@ import (
 type Bid struct {
 	BidderID  int       `json:"bidder_id"`
 	Amount    float64   `json:"amount"`
+	Cents     int64     `json:"amount_cents,omitempty"`
+	GroupID   int       `json:"group_id,omitempty"`
 	Timestamp time.Time `json:"timestamp"`
+
+	// Strategy is the bidding strategy that produced the bid, and Valuation
+	// what the bidder thought the item was worth before the strategy turned
+	// it into a bid; both are empty for external bids
+	Strategy  string  `json:"strategy,omitempty"`
+	Valuation float64 `json:"valuation,omitempty"`
+
+	// MaxBid makes this a proxy bid: the auction places it at the lowest
+	// amount that takes the lead and bids on the bidder's behalf up to MaxBid,
+	// only as high as it needs to stay on top. Amount is where it stands.
+	MaxBid      float64 `json:"max_bid,omitempty"`
+	MaxBidCents int64   `json:"max_bid_cents,omitempty"`
+
+	// Demand is the bidder's demand schedule in a multi-unit auction, one
+	// point per unit; Amount is then the price of its first unit
+	Demand []DemandPoint `json:"demand,omitempty"`
+
+	// Bundles are the bidder's bids on bundles of a combinatorial auction's
+	// items, at most one of which can win; Amount is then the highest of them
+	Bundles []BundleBid `json:"bundles,omitempty"`
+
+	// DeltaFromFirstBidMs is how long after its auction's first bid this bid
+	// arrived, filled in once collection ends
+	DeltaFromFirstBidMs float64 `json:"delta_from_first_bid_ms,omitempty"`
+}
+
+// Ceiling returns the most the bid commits to: its maximum for a proxy bid,
+// otherwise its amount
+func (b *Bid) Ceiling() float64 {
+	if b.MaxBid > 0 {
+		return b.MaxBid
+	}
+	return b.Amount
+}
+
+// ceilingCents is Ceiling in whole cents, for auctions with integer amounts
+func (b *Bid) ceilingCents() int64 {
+	if b.MaxBid > 0 {
+		return b.MaxBidCents
+	}
+	return b.Cents
+}
+
+// CommonValue is the true value of an item with attributes attrs in a
+// common-value auction: every attribute weighted at the mean bidder weight,
+// scaled by importance if given, on the scale bidders value items on
+func CommonValue(attrs [20]float64, importance *[20]float64) float64 {
+	score := 0.0
+	for i, attr := range attrs {
+		weight := 0.5
+		if importance != nil {
+			weight *= importance[i]
+		}
+		score += attr * weight
+	}
+	return 100 + (score/20)*9900
+}
+
+// ToCents converts a decimal amount to integer minor units, rounding to the nearest cent
+func ToCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// FromCents converts integer minor units back to a decimal amount
+func FromCents(cents int64) float64 {
+	return float64(cents) / 100
+}
+
+// CommitStatus is where a sealed-bid commitment stands
+type CommitStatus string
+
+const (
+	// CommitPending is a commitment still waiting for its reveal
+	CommitPending CommitStatus = "pending"
+	// CommitRevealed is a commitment whose reveal matched its hash
+	CommitRevealed CommitStatus = "revealed"
+	// CommitUnrevealed is a commitment never revealed in time, voiding it
+	CommitUnrevealed CommitStatus = "unrevealed"
+	// CommitInvalid is a commitment whose reveal did not match its hash
+	CommitInvalid CommitStatus = "invalid"
+)
+
+// Commitment is a sealed bid: a hash binding the bidder to an amount it
+// discloses only in the reveal phase
+type Commitment struct {
+	BidderID    int          `json:"bidder_id"`
+	Hash        string       `json:"hash"`
+	CommittedAt time.Time    `json:"committed_at"`
+	RevealedAt  time.Time    `json:"revealed_at,omitzero"`
+	Status      CommitStatus `json:"status"`
+}
+
+// DutchClock records the descending price clock of a Dutch auction, which
+// fell from StartPrice to FloorPrice over Ticks ticks. If a bidder accepted,
+// Sold is set with the tick, counted from 0, and the price it accepted at.
+type DutchClock struct {
+	StartPrice    float64 `json:"start_price"`
+	FloorPrice    float64 `json:"floor_price"`
+	Ticks         int     `json:"ticks"`
+	Sold          bool    `json:"sold"`
+	SoldAtTick    int     `json:"sold_at_tick"`
+	ClearingPrice float64 `json:"clearing_price"`
+}
+
+// Reveal discloses a committed bid and the nonce it was hashed with
+type Reveal struct {
+	Bid       Bid
+	Nonce     string
+	Timestamp time.Time
+}
+
+// CommitHash returns the hex SHA-256 commitment to bid in the given auction.
+// The nonce keeps equal amounts from producing equal, guessable hashes.
+func CommitHash(auctionID int, bid Bid, nonce string) string {
+	sum := sha256.Sum256([]byte(strconv.Itoa(auctionID) + "|" + strconv.Itoa(bid.BidderID) + "|" +
+		strconv.FormatFloat(bid.Amount, 'f', -1, 64) + "|" + strconv.FormatInt(bid.Cents, 10) + "|" + nonce))
+	return hex.EncodeToString(sum[:])
+}
+
+// AuctionStatus describes how an auction was finalized
+type AuctionStatus string
+
+const (
+	// StatusCompleted means the auction ran until its timeout
+	StatusCompleted AuctionStatus = "completed"
+	// StatusCancelled means the auction was cancelled before its timeout
+	StatusCancelled AuctionStatus = "cancelled"
+)
+
+// PricingMode determines what bidders pay once an auction closes
+type PricingMode string
+
+const (
+	// PricingFirstPrice charges only the winner, who pays their own bid. In a
+	// multi-unit auction each winning unit pays its own marginal bid
+	// (discriminatory pricing).
+	PricingFirstPrice PricingMode = "first-price"
+	// PricingAllPay charges every bidder their highest bid, win or lose
+	PricingAllPay PricingMode = "all-pay"
+	// PricingUniform charges every winning unit of a multi-unit auction the
+	// clearing price; with a single unit it matches first-price
+	PricingUniform PricingMode = "uniform"
+	// PricingSecondPrice (Vickrey) charges only the winner, who pays the
+	// highest bid by any other bidder; single-unit only
+	PricingSecondPrice PricingMode = "second-price"
+)
+
+// Generator names the pseudo-random number generator behind every stream of
+// a run
+type Generator string
+
+const (
+	// GeneratorMathRand is math/rand's additive lagged Fibonacci source
+	GeneratorMathRand Generator = "math-rand"
+	// GeneratorPCG is math/rand/v2's PCG-DXSM generator
+	GeneratorPCG Generator = "pcg"
+	// GeneratorCustom marks a run using a caller-supplied SimConfig.RNGSource
+	GeneratorCustom Generator = "custom"
+)
+
+// SubmissionOrder decides the sequence in which a serial auction's bids reach
+// its collector
+type SubmissionOrder string
+
+const (
+	// SubmitByDelay delivers bids at their bidders' simulated processing delays
+	SubmitByDelay SubmissionOrder = "delay"
+	// SubmitByBidderID delivers bids in ascending bidder ID order
+	SubmitByBidderID SubmissionOrder = "bidder-id"
+	// SubmitShuffled delivers bids in a permutation seeded by the run seed
+	// and auction ID
+	SubmitShuffled SubmissionOrder = "shuffle"
+	// SubmitBySchedule delivers each scheduled bidder's bid at its scheduled
+	// delay; other bidders keep their simulated delays
+	SubmitBySchedule SubmissionOrder = "schedule"
+)
+
+// AttributeMode decides how auction attributes are generated: AttributesRandom,
+// AttributesIdentical, or "templates:N" for N vectors reused round-robin
+type AttributeMode string
+
+const (
+	// AttributesRandom draws a fresh attribute vector for every auction
+	AttributesRandom AttributeMode = "random"
+	// AttributesIdentical gives every auction the same generated vector
+	AttributesIdentical AttributeMode = "identical"
+)
+
+// attributeTemplatesPrefix starts a templates mode, e.g. "templates:4"
+const attributeTemplatesPrefix = "templates:"
+
+// Templates returns how many attribute vectors the mode reuses across
+// auctions, or zero if every auction draws its own
+func (m AttributeMode) Templates() (int, error) {
+	switch m {
+	case AttributesRandom:
+		return 0, nil
+	case AttributesIdentical:
+		return 1, nil
+	}
+	count, ok := strings.CutPrefix(string(m), attributeTemplatesPrefix)
+	if !ok {
+		return 0, fmt.Errorf("unknown attribute mode %q (want %q, %q or %q)", m,
+			AttributesRandom, AttributesIdentical, attributeTemplatesPrefix+"N")
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("attribute templates must be a positive count, got %q", count)
+	}
+	return n, nil
+}
+
+// DurationUnit is the unit the printed summary and report show durations in
+type DurationUnit string
+
+// Duration units; "us" stands in for µs so the flag is easy to type
+const (
+	UnitNanoseconds  DurationUnit = "ns"
+	UnitMicroseconds DurationUnit = "us"
+	UnitMilliseconds DurationUnit = "ms"
+	UnitSeconds      DurationUnit = "s"
+)
+
+// Size returns the length of one unit, or zero for an unknown unit
+func (u DurationUnit) Size() time.Duration {
+	switch u {
+	case UnitNanoseconds:
+		return time.Nanosecond
+	case UnitMicroseconds:
+		return time.Microsecond
+	case UnitMilliseconds:
+		return time.Millisecond
+	case UnitSeconds:
+		return time.Second
+	}
+	return 0
+}
+
+// Format writes d in u with three decimals; unknown units, such as the
+// empty unit of summaries written before units existed, use milliseconds
+func (u DurationUnit) Format(d time.Duration) string {
+	if u.Size() == 0 {
+		u = UnitMilliseconds
+	}
+	return fmt.Sprintf("%.3f %s", float64(d)/float64(u.Size()), u)
+}
+
+// FormatMs is Format for a measurement recorded in milliseconds
+func (u DurationUnit) FormatMs(ms float64) string {
+	return u.Format(time.Duration(ms * float64(time.Millisecond)))
+}
+
+// Auction represents a single auction with its attributes and state
+type Auction struct {
+	ID         int         `json:"auction_id"`
+	Attributes [20]float64 `json:"attributes"`
+	// AttributeTemplate is the 1-based template the attributes were copied
+	// from when auctions share attribute vectors; zero if drawn afresh
+	AttributeTemplate int `json:"attribute_template,omitempty"`
+	// AttributeImportance scales how much each attribute counts toward every
+	// bidder's valuation of this auction; nil weighs them equally
+	AttributeImportance *[20]float64  `json:"attribute_importance,omitempty"`
+	Timeout             time.Duration `json:"-"`
+	TimeoutMs           int64         `json:"timeout_ms"`
+	TimeoutNs           int64         `json:"timeout_ns"`
+	StartTime           time.Time     `json:"start_time"`
+	EndTime             time.Time     `json:"end_time"`
+	Bids                []Bid         `json:"bids"`
+	Winner              *Bid          `json:"winner"`
+	TotalBids           int           `json:"total_bids"`
+	HHI                 float64       `json:"hhi"`
+	Status              AuctionStatus `json:"status"`
+	Phases              PhaseTimings  `json:"phase_timings"`
+
+	// FirstBidTime is when the earliest bid arrived; zero if there were none
+	FirstBidTime time.Time `json:"first_bid_time,omitzero"`
+
+	// MinDurationApplied is set when the auction was asked to close early but
+	// stayed open until its minimum duration elapsed
+	MinDurationApplied bool `json:"min_duration_applied,omitempty"`
+
+	// MergedBids counts bids removed by coalescing bursts from the same bidder
+	MergedBids int `json:"merged_bids,omitempty"`
+
+	// RejectedBids counts bids the acceptance policy or the minimum increment
+	// turned away
+	RejectedBids int `json:"rejected_bids,omitempty"`
+
+	// MinIncrement is how much a bid must beat the current highest bid by to
+	// be accepted; the first bid is free of it. Zero accepts any bid.
+	MinIncrement      float64 `json:"min_increment,omitempty"`
+	MinIncrementCents int64   `json:"min_increment_cents,omitempty"`
+
+	// MaxStoredBids caps Bids at the highest MaxStoredBids bids; lower ones
+	// are summarized in Discarded. Zero stores every bid.
+	MaxStoredBids int            `json:"-"`
+	Discarded     *DiscardedBids `json:"discarded_bids,omitempty"`
+
+	// Commitments are the sealed bids of a commit-reveal auction. Only
+	// revealed commitments become bids; UnrevealedCommits and InvalidReveals
+	// count those voided for a missing or mismatched reveal.
+	Commitments       []Commitment `json:"commitments,omitempty"`
+	UnrevealedCommits int          `json:"unrevealed_commits,omitempty"`
+	InvalidReveals    int          `json:"invalid_reveals,omitempty"`
+
+	// Dutch is the price clock of a Dutch auction; nil for an ascending one
+	Dutch *DutchClock `json:"dutch,omitempty"`
+
+	// Presence records when each participating bidder arrived and left, if
+	// bidder arrivals are staggered over the auction window
+	Presence []Presence `json:"presence,omitempty"`
+
+	// PeakBidRate is the most bids per second the collector received over
+	// any rate window, measured only under burst load
+	PeakBidRate float64 `json:"peak_bid_rate,omitempty"`
+
+	// Units is how many identical units the auction sells; zero or one means
+	// a single item. With several, bidders submit demand schedules and the
+	// units go to the highest marginal bids.
+	Units int `json:"units,omitempty"`
+
+	// BundleItems is how many items a combinatorial auction splits the
+	// attributes into; above one, bidders bid on bundles of items and the
+	// auction clears to the most valuable set of non-overlapping bundles.
+	// ClearedValue is the total of the winning bundle bids.
+	BundleItems  int     `json:"bundle_items,omitempty"`
+	ClearedValue float64 `json:"cleared_value,omitempty"`
+
+	// ClearingPrice is the lowest accepted marginal bid of a multi-unit
+	// auction, and Allocations what each winning bidder got and paid. Winners
+	// holds each winning bidder's bid in the same order; Winner stays the
+	// highest of them.
+	ClearingPrice      float64      `json:"clearing_price,omitempty"`
+	ClearingPriceCents int64        `json:"clearing_price_cents,omitempty"`
+	Allocations        []Allocation `json:"allocations,omitempty"`
+	Winners            []Bid        `json:"winners,omitempty"`
+
+	// Pricing is the payment rule; empty means first-price
+	Pricing PricingMode `json:"pricing,omitempty"`
+
+	// ReservePrice is the lowest bid the seller accepts, per unit; zero means
+	// no reserve. ReserveMet reports whether the auction sold at or above it.
+	ReservePrice      float64 `json:"reserve_price,omitempty"`
+	ReservePriceCents int64   `json:"reserve_price_cents,omitempty"`
+	ReserveMet        bool    `json:"reserve_met"`
+
+	// WinningPrice is what the winner of a single-unit auction pays, apart
+	// from fees: its own bid, or the runner-up's under second-price pricing
+	WinningPrice      float64 `json:"winning_price,omitempty"`
+	WinningPriceCents int64   `json:"winning_price_cents,omitempty"`
+
+	// TotalPaid is the revenue the auction raised under its pricing mode
+	TotalPaid      float64 `json:"total_paid"`
+	TotalPaidCents int64   `json:"total_paid_cents,omitempty"`
+
+	// Fee is the auction house's commission schedule, charged on each payment;
+	// FeesPaid is what it collected, so the seller nets TotalPaid - FeesPaid
+	Fee           *FeeSchedule `json:"fee,omitempty"`
+	FeesPaid      float64      `json:"fees_paid,omitempty"`
+	FeesPaidCents int64        `json:"fees_paid_cents,omitempty"`
+
+	// IntegerAmounts makes comparisons and sums use Bid.Cents instead of Bid.Amount
+	IntegerAmounts bool `json:"integer_amounts,omitempty"`
+
+	// AttributeBias is the shift applied to generated attributes in feedback mode
+	AttributeBias float64 `json:"attribute_bias,omitempty"`
+
+	// GraceExtensions counts how often a final bid extended the deadline;
+	// EndTime then records the final close
+	GraceExtensions int `json:"grace_extensions,omitempty"`
+
+	// TrueValue is what the item is worth to every bidder in a common-value
+	// auction, hidden from them; zero means private values. Overpaid reports
+	// whether the winner paid more than it, the winner's curse.
+	TrueValue float64 `json:"true_value,omitempty"`
+	Overpaid  bool    `json:"overpaid,omitempty"`
+
+	closed   bool
+	cutoff   time.Time
+	extended time.Duration
+	mu       sync.Mutex
+}
+
+// PhaseTimings breaks an auction's lifetime down into its individual phases
+type PhaseTimings struct {
+	AttributeGenerationMs float64 `json:"attribute_generation_ms"`
+	BidderNotificationMs  float64 `json:"bidder_notification_ms"`
+	BidCollectionMs       float64 `json:"bid_collection_ms"`
+	WinnerDeterminationMs float64 `json:"winner_determination_ms"`
+
+	// RevealMs is the reveal phase of a commit-reveal auction, which follows
+	// bid collection (the commit phase)
+	RevealMs float64 `json:"reveal_ms,omitempty"`
+}
+
+// NewAuction creates a new auction. expectedBids sizes the bid slice up front
+// so AddBid does not reallocate as bids arrive; it is only a hint.
+func NewAuction(id int, timeout time.Duration, expectedBids int) *Auction {
+	return &Auction{
+		ID:        id,
+		Timeout:   timeout,
+		TimeoutMs: timeout.Milliseconds(),
+		TimeoutNs: timeout.Nanoseconds(),
+		Bids:      make([]Bid, 0, max(expectedBids, 0)),
+	}
+}
+
+// RestoreTimeout rebuilds Timeout after decoding, preferring the nanosecond
+// field and falling back to milliseconds for files written before it existed
+func (a *Auction) RestoreTimeout() {
+	if a.TimeoutNs > 0 {
+		a.Timeout = time.Duration(a.TimeoutNs)
+		return
+	}
+	a.Timeout = time.Duration(a.TimeoutMs) * time.Millisecond
+}
+
+// Deadline returns when the auction stops collecting bids, unless it is
+// cancelled earlier. Grace extensions push it back.
+func (a *Auction) Deadline() time.Time {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.StartTime.Add(a.Timeout + a.extended)
+}
+
+// Extend pushes the deadline back by d and counts a grace extension
+func (a *Auction) Extend(d time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.extended += d
+	a.GraceExtensions++
+}
+
+// AddBid adds a bid to the auction in a thread-safe manner. A bid that does
+// not beat the current highest by MinIncrement is counted in RejectedBids and
+// not added; AddBid reports whether the bid was accepted.
+func (a *Auction) AddBid(bid Bid) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// Bids from outside the bidder population may arrive without cents set
+	if a.IntegerAmounts {
+		if bid.Cents == 0 {
+			bid.Cents = ToCents(bid.Amount)
+		}
+		bid.Amount = FromCents(bid.Cents)
+		for i := range bid.Demand {
+			if bid.Demand[i].Cents == 0 {
+				bid.Demand[i].Cents = ToCents(bid.Demand[i].Price)
+			}
+			bid.Demand[i].Price = FromCents(bid.Demand[i].Cents)
+		}
+		if bid.MaxBid > 0 {
+			if bid.MaxBidCents == 0 {
+				bid.MaxBidCents = ToCents(bid.MaxBid)
+			}
+			bid.MaxBid = FromCents(bid.MaxBidCents)
+		}
+	}
+	if bid.MaxBid > 0 {
+		a.placeProxy(&bid)
+	}
+	if !a.meetsIncrement(bid) {
+		a.RejectedBids++
+		return false
+	}
+	if a.MaxStoredBids > 0 && len(a.Bids) >= a.MaxStoredBids {
+		a.keepTop(bid)
+		return true
+	}
+	a.Bids = append(a.Bids, bid)
+	return true
+}
+
+// placeProxy sets a proxy bid's amount to the lowest the auction accepts
+// next, or, as the first bid, to the reserve or one increment, whichever is
+// higher. A maximum short of that leaves the bid short, so it is rejected.
+// Must be called with a.mu held.
+func (a *Auction) placeProxy(bid *Bid) {
+	amount, cents, ok := a.minimumBid()
+	if !ok {
+		amount, cents = max(a.ReservePrice, a.MinIncrement), max(a.ReservePriceCents, a.MinIncrementCents)
+	}
+	if a.IntegerAmounts {
+		bid.Cents = min(cents, bid.MaxBidCents)
+		bid.Amount = FromCents(bid.Cents)
+		return
+	}
+	bid.Amount = min(amount, bid.MaxBid)
+}
+
+// price returns what leader stands at, and its cents: its amount, or for a
+// proxy bid one increment above the best rival's ceiling, capped at its
+// maximum and never below its amount or the reserve. Must be called with a.mu
+// held.
+func (a *Auction) price(leader *Bid) (float64, int64) {
+	if leader.MaxBid <= 0 {
+		return leader.Amount, leader.Cents
+	}
+	var rival *Bid
+	for i := range a.Bids {
+		bid := &a.Bids[i]
+		if bid.BidderID != leader.BidderID && (rival == nil || a.compareAmounts(bid, rival) > 0) {
+			rival = bid
+		}
+	}
+
+	if a.IntegerAmounts {
+		cents := max(leader.Cents, a.ReservePriceCents)
+		if rival != nil {
+			cents = max(cents, rival.ceilingCents()+a.MinIncrementCents)
+		}
+		cents = min(cents, leader.MaxBidCents)
+		return FromCents(cents), cents
+	}
+	amount := max(leader.Amount, a.ReservePrice)
+	if rival != nil {
+		amount = max(amount, rival.Ceiling()+a.MinIncrement)
+	}
+	return min(amount, leader.MaxBid), 0
+}
+
+// MinimumBid returns the lowest amount, and its cents, the auction accepts
+// next: the price the current leader stands at plus MinIncrement. ok is false while any bid
+// is accepted. It is safe to call while the auction is collecting bids.
+func (a *Auction) MinimumBid() (amount float64, cents int64, ok bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.minimumBid()
+}
+
+// minimumBid implements MinimumBid. Must be called with a.mu held.
+func (a *Auction) minimumBid() (amount float64, cents int64, ok bool) {
+	leader := a.highestBid()
+	if a.MinIncrement <= 0 || leader == nil {
+		return 0, 0, false
+	}
+	amount, cents = a.price(leader)
+	if a.IntegerAmounts {
+		cents += a.MinIncrementCents
+		return FromCents(cents), cents, true
+	}
+	return amount + a.MinIncrement, 0, true
+}
+
+// MeetsIncrement reports whether bid beats the current leader's price by at
+// least MinIncrement, so AddBid would accept it now. A proxy bid is judged by
+// its maximum.
+func (a *Auction) MeetsIncrement(bid Bid) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if bid.MaxBid > 0 {
+		bid.Amount, bid.Cents = bid.MaxBid, bid.MaxBidCents
+	}
+	if a.IntegerAmounts && bid.Cents == 0 {
+		bid.Cents = ToCents(bid.Amount)
+	}
+	return a.meetsIncrement(bid)
+}
+
+// meetsIncrement reports whether bid, with its cents already set, beats the
+// current highest bid by at least MinIncrement. Must be called with a.mu held.
+func (a *Auction) meetsIncrement(bid Bid) bool {
+	amount, cents, ok := a.minimumBid()
+	if !ok {
+		return true
+	}
+	if a.IntegerAmounts {
+		return bid.Cents >= cents
+	}
+	return bid.Amount >= amount
+}
+
+// DiscardedBids summarizes the bids an auction dropped to stay within
+// MaxStoredBids
+type DiscardedBids struct {
+	Count int     `json:"count"`
+	Total float64 `json:"total"`
+	// Highest is the highest discarded amount, which no retained bid is below
+	Highest  float64   `json:"highest"`
+	Earliest time.Time `json:"earliest"`
+}
+
+// keepTop adds bid to a full auction by discarding whichever of bid and the
+// lowest stored bid ranks lower, keeping the rest in arrival order. Among
+// equal amounts the later bid ranks lower, as it would lose a tie. Must be
+// called with a.mu held.
+func (a *Auction) keepTop(bid Bid) {
+	lowest := 0
+	for i := 1; i < len(a.Bids); i++ {
+		if a.ranksBelow(&a.Bids[i], &a.Bids[lowest]) {
+			lowest = i
+		}
+	}
+
+	discarded := bid
+	if a.ranksBelow(&a.Bids[lowest], &bid) {
+		discarded = a.Bids[lowest]
+		a.Bids = append(a.Bids[:lowest], a.Bids[lowest+1:]...)
+		a.Bids = append(a.Bids, bid)
+	}
+
+	if a.Discarded == nil {
+		a.Discarded = &DiscardedBids{Highest: discarded.Amount, Earliest: discarded.Timestamp}
+	}
+	d := a.Discarded
+	d.Count++
+	d.Total += discarded.Amount
+	d.Highest = max(d.Highest, discarded.Amount)
+	if discarded.Timestamp.Before(d.Earliest) {
+		d.Earliest = discarded.Timestamp
+	}
+}
+
+// ranksBelow reports whether x would lose to y: a lower amount, or an equal
+// amount placed later
+func (a *Auction) ranksBelow(x, y *Bid) bool {
+	cmp := a.compareAmounts(x, y)
+	return cmp < 0 || cmp == 0 && x.Timestamp.After(y.Timestamp)
+}
+
+// compareAmounts returns -1, 0 or +1 as x's ceiling is below, equal to or
+// above y's, using exact integer cents when the auction is in integer mode.
+// Bids rank by ceiling, so a proxy bid leads any bid below its maximum.
+func (a *Auction) compareAmounts(x, y *Bid) int {
+	if a.IntegerAmounts {
+		switch {
+		case x.ceilingCents() < y.ceilingCents():
+			return -1
+		case x.ceilingCents() > y.ceilingCents():
+			return 1
+		}
+		return 0
+	}
+
+	switch {
+	case x.Ceiling() < y.Ceiling():
+		return -1
+	case x.Ceiling() > y.Ceiling():
+		return 1
+	}
+	return 0
+}
+
+// Outbids reports whether bid is strictly higher than other, or other is nil,
+// comparing as the auction does
+func (a *Auction) Outbids(bid Bid, other *Bid) bool {
+	if other == nil {
+		return true
+	}
+	if a.IntegerAmounts && bid.Cents == 0 {
+		bid.Cents = ToCents(bid.Amount)
+	}
+	return a.compareAmounts(&bid, other) > 0
+}
+
+// BidderBest returns a copy of the bidder's highest bid so far, or nil if it
+// has not bid. It is safe to call while the auction is collecting bids.
+func (a *Auction) BidderBest(bidderID int) *Bid {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var best *Bid
+	for i := range a.Bids {
+		if a.Bids[i].BidderID == bidderID && (best == nil || a.compareAmounts(&a.Bids[i], best) > 0) {
+			best = &a.Bids[i]
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	bid := *best
+	return &bid
+}
+
+// Presence records when a bidder was at an auction, in milliseconds after it
+// opened. DepartedMs is zero for a bidder that stayed until the close.
+type Presence struct {
+	BidderID   int     `json:"bidder_id"`
+	ArrivedMs  float64 `json:"arrived_ms"`
+	DepartedMs float64 `json:"departed_ms,omitempty"`
+	// LeftEarly means the bidder departed before its bid was ready
+	LeftEarly bool `json:"left_early,omitempty"`
+}
+
+// AddPresence records a bidder's arrival and departure
+func (a *Auction) AddPresence(p Presence) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.Presence = append(a.Presence, p)
+}
+
+// MarkLeftEarly notes that a bidder departed before its bid was ready. It has
+// no effect once the auction is closed.
+func (a *Auction) MarkLeftEarly(bidderID int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return
+	}
+	for i := range a.Presence {
+		if a.Presence[i].BidderID == bidderID {
+			a.Presence[i].LeftEarly = true
+		}
+	}
+}
+
+// Commit records a sealed bid. A bidder that commits again replaces its
+// earlier commitment.
+func (a *Auction) Commit(c Commitment) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	c.Status = CommitPending
+	for i := range a.Commitments {
+		if a.Commitments[i].BidderID == c.BidderID {
+			a.Commitments[i] = c
+			return
+		}
+	}
+	a.Commitments = append(a.Commitments, c)
+}
+
+// Open checks a reveal against the bidder's pending commitment. A matching
+// reveal returns the bid, stamped with its commit time so ties still go to
+// the earlier commitment; a mismatch voids the commitment.
+func (a *Auction) Open(r Reveal) (Bid, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for i := range a.Commitments {
+		c := &a.Commitments[i]
+		if c.BidderID != r.Bid.BidderID || c.Status != CommitPending {
+			continue
+		}
+		c.RevealedAt = r.Timestamp
+		if CommitHash(a.ID, r.Bid, r.Nonce) != c.Hash {
+			c.Status = CommitInvalid
+			a.InvalidReveals++
+			return Bid{}, false
+		}
+		c.Status = CommitRevealed
+		bid := r.Bid
+		bid.Timestamp = c.CommittedAt
+		return bid, true
+	}
+	return Bid{}, false
+}
+
+// VoidUnrevealed ends the reveal phase, voiding every commitment still
+// pending, and returns how many were voided
+func (a *Auction) VoidUnrevealed() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for i := range a.Commitments {
+		if a.Commitments[i].Status == CommitPending {
+			a.Commitments[i].Status = CommitUnrevealed
+			a.UnrevealedCommits++
+		}
+	}
+	return a.UnrevealedCommits
+}
+
+// Reject counts a bid turned away by the auction's acceptance policy
+func (a *Auction) Reject() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.RejectedBids++
+}
+
+// Close marks the auction as no longer accepting bids
+func (a *Auction) Close() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.closed = true
+}
+
+// StopAt makes the auction refuse bids stamped after cutoff while it waits for
+// bids already in flight; Close then refuses every bid
+func (a *Auction) StopAt(cutoff time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.cutoff = cutoff
+}
+
+// IsClosed reports whether the auction has stopped accepting bids
+func (a *Auction) IsClosed() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.closed
+}
+
+// Send offers bid on bidChan without blocking, unless the auction has closed
+// or stopped before the bid's timestamp. The check and the send happen under
+// the auction's lock, so every bid sent is in the channel before Close returns
+// and a collector that drains the channel after closing the auction sees all
+// of them. open is false if the auction had closed or stopped; sent is false
+// if so or the channel was full.
+func (a *Auction) Send(bidChan chan<- Bid, bid Bid) (open, sent bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed || (!a.cutoff.IsZero() && bid.Timestamp.After(a.cutoff)) {
+		return false, false
+	}
+	select {
+	case bidChan <- bid:
+		return true, true
+	default:
+		return true, false
+	}
+}
+
+// DetermineWinner finds the highest bid and sets it as the winner, pricing
+// its win; a highest bid below the reserve leaves the auction unsold. A
+// multi-unit auction first clears the market, and its winner is the bidder
+// with the highest marginal bid.
+func (a *Auction) DetermineWinner() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.TotalBids = len(a.Bids)
+	if a.Discarded != nil {
+		a.TotalBids += a.Discarded.Count
+	}
+	a.HHI = a.computeHHI()
+
+	switch {
+	case a.BundleItems > 1:
+		a.clearBundles()
+	case a.Units > 1:
+		a.clearMarket()
+	default:
+		a.Winner = a.highestBid()
+		if a.Winner != nil && !a.meetsReserve(a.Winner.Ceiling(), a.Winner.ceilingCents()) {
+			a.Winner = nil
+		}
+		if a.Winner != nil && a.Winner.MaxBid > 0 {
+			// A winning proxy bid stands at the price it was pushed to
+			a.Winner.Amount, a.Winner.Cents = a.price(a.Winner)
+		}
+		a.setWinningPrice()
+		a.Overpaid = a.TrueValue > 0 && a.Winner != nil && a.WinningPrice > a.TrueValue
+	}
+	a.ReserveMet = a.Winner != nil
+	payments := a.payments()
+	a.TotalPaidCents, a.TotalPaid = a.sum(payments, func(b *Bid) (int64, float64) { return b.Cents, b.Amount })
+	a.FeesPaidCents, a.FeesPaid = a.sum(payments, a.Fee.charge)
+}
+
+// payments returns the bids that are paid under the auction's pricing mode.
+// Under all-pay pricing each bidder pays their highest bid, so revisions are
+// not charged twice. A multi-unit or combinatorial auction charges each
+// allocation as one payment. Must be called with a.mu held.
+func (a *Auction) payments() []*Bid {
+	if a.Units > 1 || a.BundleItems > 1 {
+		payments := make([]*Bid, len(a.Allocations))
+		for i, alloc := range a.Allocations {
+			payments[i] = &Bid{BidderID: alloc.BidderID, Amount: alloc.Paid, Cents: alloc.PaidCents}
+		}
+		return payments
+	}
+	if a.Pricing != PricingAllPay {
+		if a.Winner == nil {
+			return nil
+		}
+		return []*Bid{{BidderID: a.Winner.BidderID, Amount: a.WinningPrice, Cents: a.WinningPriceCents}}
+	}
+
+	highest := make(map[int]*Bid)
+	for i := range a.Bids {
+		bid := &a.Bids[i]
+		if prev, ok := highest[bid.BidderID]; !ok || a.compareAmounts(bid, prev) > 0 {
+			highest[bid.BidderID] = bid
+		}
+	}
+
+	payments := make([]*Bid, 0, len(highest))
+	for _, bid := range highest {
+		payments = append(payments, bid)
+	}
+	return payments
+}
+
+// sum totals value over payments in cents and as a decimal. Integer mode sums
+// exact cents rather than accumulating float error.
+func (a *Auction) sum(payments []*Bid, value func(*Bid) (int64, float64)) (int64, float64) {
+	var cents int64
+	total := 0.0
+	for _, bid := range payments {
+		c, v := value(bid)
+		cents += c
+		total += v
+	}
+	if a.IntegerAmounts {
+		total = FromCents(cents)
+	}
+	return cents, total
+}
+
+// FeeSchedule is the auction house's commission on each payment: Percent of
+// the amount plus Flat, raised to at least Min and never more than the
+// payment itself
+type FeeSchedule struct {
+	Percent float64 `json:"percent,omitempty"`
+	Flat    float64 `json:"flat,omitempty"`
+	Min     float64 `json:"min,omitempty"`
+}
+
+// charge returns the fee on a payment in cents and as a decimal; a nil
+// schedule charges nothing
+func (f *FeeSchedule) charge(payment *Bid) (int64, float64) {
+	if f == nil {
+		return 0, 0
+	}
+	fee := min(max(payment.Amount*f.Percent/100+f.Flat, f.Min), payment.Amount)
+	cents := int64(math.Round(float64(payment.Cents)*f.Percent/100)) + ToCents(f.Flat)
+	cents = min(max(cents, ToCents(f.Min)), payment.Cents)
+	return cents, fee
+}
+
+// CurrentLeader returns a copy of the highest bid received so far, at the
+// price it stands at, or nil if there are no bids yet. It is safe to call while the auction is still
+// collecting bids; the result is provisional until the auction closes and
+// DetermineWinner runs.
+func (a *Auction) CurrentLeader() *Bid {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.standing()
+}
+
+// standing returns a copy of the highest bid at the price it stands at, with
+// a proxy bid's maximum hidden as it would be from other bidders, or nil if
+// there are no bids. Must be called with a.mu held.
+func (a *Auction) standing() *Bid {
+	leader := a.highestBid()
+	if leader == nil {
+		return nil
+	}
+	copied := *leader
+	copied.Amount, copied.Cents = a.price(leader)
+	copied.MaxBid, copied.MaxBidCents = 0, 0
+	return &copied
+}
+
+// AuctionView is a consistent copy of an auction's live state, safe to read
+// while the auction is still collecting bids. Winner is set once the auction
+// has been decided.
+type AuctionView struct {
+	AuctionID       int       `json:"auction_id"`
+	Open            bool      `json:"open"`
+	StartTime       time.Time `json:"start_time"`
+	Deadline        time.Time `json:"deadline"`
+	TotalBids       int       `json:"total_bids"`
+	RejectedBids    int       `json:"rejected_bids"`
+	GraceExtensions int       `json:"grace_extensions"`
+	Leader          *Bid      `json:"leader"`
+	Winner          *Bid      `json:"winner,omitempty"`
+	Bids            []Bid     `json:"bids"`
+}
+
+// Snapshot copies the auction's live state under its lock. Readers outside
+// the collector, such as the control server, must use it rather than the
+// fields, which the collector writes concurrently.
+func (a *Auction) Snapshot() AuctionView {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	view := AuctionView{
+		AuctionID:       a.ID,
+		Open:            !a.closed,
+		StartTime:       a.StartTime,
+		Deadline:        a.StartTime.Add(a.Timeout + a.extended),
+		TotalBids:       len(a.Bids),
+		RejectedBids:    a.RejectedBids,
+		GraceExtensions: a.GraceExtensions,
+		Bids:            slices.Clone(a.Bids),
+	}
+	if a.Discarded != nil {
+		view.TotalBids += a.Discarded.Count
+	}
+	view.Leader = a.standing()
+	if a.Winner != nil {
+		copied := *a.Winner
+		view.Winner = &copied
+	}
+	return view
+}
+
+// highestBid returns the highest bid, with the earliest timestamp winning ties.
+// Must be called with a.mu held.
+func (a *Auction) highestBid() *Bid {
+	if len(a.Bids) == 0 {
+		return nil
+	}
+
+	best := &a.Bids[0]
+	for i := 1; i < len(a.Bids); i++ {
+		cmp := a.compareAmounts(&a.Bids[i], best)
+		if cmp > 0 {
+			best = &a.Bids[i]
+		} else if cmp == 0 && a.Bids[i].Timestamp.Before(best.Timestamp) {
+			// In case of tie, earlier timestamp wins
+			best = &a.Bids[i]
+		}
+	}
+	return best
+}
+
+// setWinningPrice records what the winner of a single-unit auction pays.
+// Under second-price pricing that is the highest bid by any other bidder, but
+// never less than the reserve; with no other bidder it falls back to the
+// reserve, or to the winner's own bid without one. Must be called with a.mu
+// held.
+func (a *Auction) setWinningPrice() {
+	a.WinningPrice, a.WinningPriceCents = 0, 0
+	if a.Winner == nil {
+		return
+	}
+	price := a.Winner
+	if a.Pricing == PricingSecondPrice {
+		var runnerUp *Bid
+		for i := range a.Bids {
+			bid := &a.Bids[i]
+			if bid.BidderID != a.Winner.BidderID && (runnerUp == nil || a.compareAmounts(bid, runnerUp) > 0) {
+				runnerUp = bid
+			}
+		}
+		reserve := &Bid{Amount: a.ReservePrice, Cents: a.ReservePriceCents}
+		switch {
+		case runnerUp != nil && a.compareAmounts(runnerUp, reserve) >= 0:
+			price = runnerUp
+		case a.ReservePrice > 0:
+			price = reserve
+		}
+	}
+	a.WinningPrice, a.WinningPriceCents = price.Amount, price.Cents
 }
 
-// Auction represents a single auction with its attributes and state
-type Auction struct {
-	ID         int         `json:"auction_id"`
-	Attributes [20]float64 `json:"attributes"`
-	Timeout    time.Duration `json:"-"`
-	TimeoutMs  int64       `json:"timeout_ms"`
-	StartTime  time.Time   `json:"start_time"`
-	EndTime    time.Time   `json:"end_time"`
-	Bids       []Bid       `json:"bids"`
-	Winner     *Bid        `json:"winner"`
-	TotalBids  int         `json:"total_bids"`
-	mu         sync.Mutex
-}
-
-// NewAuction creates a new auction with random attributes
-func NewAuction(id int, timeout time.Duration) *Auction {
-	return &Auction{
-		ID:        id,
-		Timeout:   timeout,
-		TimeoutMs: timeout.Milliseconds(),
-		Bids:      make([]Bid, 0),
+// meetsReserve reports whether a bid of amount, or cents with integer
+// amounts, reaches the auction's reserve, if it has one. Must be called with
+// a.mu held.
+func (a *Auction) meetsReserve(amount float64, cents int64) bool {
+	if a.ReservePrice <= 0 {
+		return true
+	}
+	if a.IntegerAmounts {
+		return cents >= a.ReservePriceCents
+	}
+	return amount >= a.ReservePrice
+}
+
+// computeHHI calculates the Herfindahl-Hirschman Index of bid volume, treating
+// each bidder's total bid amount as its share of all bid volume. The result is
+// in the range (0, 1]; an auction without bids has an HHI of zero. Only
+// stored bids count, so with MaxStoredBids the volume is that of the top bids.
+// Must be called with a.mu held.
+func (a *Auction) computeHHI() float64 {
+	if a.IntegerAmounts {
+		volume := make(map[int]int64)
+		var total int64
+		for _, bid := range a.Bids {
+			volume[bid.BidderID] += bid.Cents
+			total += bid.Cents
+		}
+		return herfindahl(volume, total)
+	}
+
+	volume := make(map[int]float64)
+	total := 0.0
+	for _, bid := range a.Bids {
+		volume[bid.BidderID] += bid.Amount
+		total += bid.Amount
 	}
+	return herfindahl(volume, total)
 }
 
-// AddBid adds a bid to the auction in a thread-safe manner
-func (a *Auction) AddBid(bid Bid) {
+// herfindahl sums the squared shares of each entry in volume
+func herfindahl[T int64 | float64](volume map[int]T, total T) float64 {
+	if total <= 0 {
+		return 0
+	}
+
+	hhi := 0.0
+	for _, v := range volume {
+		share := float64(v) / float64(total)
+		hhi += share * share
+	}
+	return hhi
+}
+
+// AcceptanceMode names a built-in bid acceptance policy
+type AcceptanceMode string
+
+const (
+	// AcceptAll keeps every bid received before the deadline
+	AcceptAll AcceptanceMode = "accept-all"
+	// AcceptHigher keeps only bids that beat the current leader, as in open outcry
+	AcceptHigher AcceptanceMode = "higher-than-current"
+	// AcceptFirstPerBidder keeps only each bidder's first bid
+	AcceptFirstPerBidder AcceptanceMode = "first-per-bidder"
+	// AcceptBestPerBidder keeps a bid only if it improves on the bidder's own best
+	AcceptBestPerBidder AcceptanceMode = "best-per-bidder"
+)
+
+// CoalesceKeep selects which bid survives when a burst of bids is merged
+type CoalesceKeep string
+
+const (
+	// CoalesceLatest keeps the last bid of each burst
+	CoalesceLatest CoalesceKeep = "latest"
+	// CoalesceHighest keeps the highest bid of each burst
+	CoalesceHighest CoalesceKeep = "highest"
+)
+
+// Coalesce merges bids from the same bidder that arrive within window of the
+// first bid in their burst, keeping one bid per burst. Surviving bids stay in
+// arrival order. It returns the number of bids removed and records it in
+// MergedBids. It must be called after the auction closes.
+func (a *Auction) Coalesce(window time.Duration, keep CoalesceKeep) int {
 	a.mu.Lock()
 	defer a.mu.Unlock()
-	a.Bids = append(a.Bids, bid)
+
+	if window <= 0 || len(a.Bids) < 2 {
+		return 0
+	}
+
+	// Walk each bidder's bids in time order, tracking the open burst
+	byBidder := make(map[int][]int)
+	for i, bid := range a.Bids {
+		byBidder[bid.BidderID] = append(byBidder[bid.BidderID], i)
+	}
+
+	drop := make([]bool, len(a.Bids))
+	merged := 0
+	for _, idx := range byBidder {
+		sort.SliceStable(idx, func(i, j int) bool {
+			return a.Bids[idx[i]].Timestamp.Before(a.Bids[idx[j]].Timestamp)
+		})
+
+		burstStart := a.Bids[idx[0]].Timestamp
+		kept := idx[0]
+		for _, i := range idx[1:] {
+			bid := &a.Bids[i]
+			if bid.Timestamp.Sub(burstStart) > window {
+				burstStart = bid.Timestamp
+				kept = i
+				continue
+			}
+
+			merged++
+			if keep == CoalesceHighest && a.compareAmounts(bid, &a.Bids[kept]) <= 0 {
+				drop[i] = true
+				continue
+			}
+			drop[kept] = true
+			kept = i
+		}
+	}
+
+	bids := a.Bids[:0]
+	for i, bid := range a.Bids {
+		if !drop[i] {
+			bids = append(bids, bid)
+		}
+	}
+	a.Bids = bids
+	a.MergedBids += merged
+	return merged
 }
 
-// DetermineWinner finds the highest bid and sets it as the winner
-func (a *Auction) DetermineWinner() {
+// MarkFirstBid records the earliest bid's arrival as FirstBidTime and each
+// bid's offset from it. It runs once collection has ended, when the first bid
+// is known.
+func (a *Auction) MarkFirstBid() {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	a.TotalBids = len(a.Bids)
+	a.FirstBidTime = time.Time{}
+	if a.Discarded != nil {
+		a.FirstBidTime = a.Discarded.Earliest
+	}
+	for _, bid := range a.Bids {
+		if a.FirstBidTime.IsZero() || bid.Timestamp.Before(a.FirstBidTime) {
+			a.FirstBidTime = bid.Timestamp
+		}
+	}
+	for i := range a.Bids {
+		a.Bids[i].DeltaFromFirstBidMs = float64(a.Bids[i].Timestamp.Sub(a.FirstBidTime)) / float64(time.Millisecond)
+	}
+}
 
-	if len(a.Bids) == 0 {
-		a.Winner = nil
-		return
+// canonicalBid is the part of a bid that a fixed seed and config determine:
+// its time is an offset from the auction start, not a wall-clock time
+type canonicalBid struct {
+	BidderID int           `json:"b"`
+	Amount   float64       `json:"a"`
+	Cents    int64         `json:"c"`
+	OffsetNs int64         `json:"t"`
+	Demand   []DemandPoint `json:"d,omitempty"`
+	Bundles  []BundleBid   `json:"u,omitempty"`
+}
+
+// CanonicalHash returns the hex SHA-256 of the auction's outcome: its
+// attributes, bids, winner and payments. Wall-clock times and measured phase
+// timings are left out, so a deterministic run hashes the same every time.
+func (a *Auction) CanonicalHash() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// Offsets use wall-clock readings, which survive a JSON round trip, so
+	// results read back from disk hash as they did in memory
+	start := a.StartTime.Round(0)
+	canonical := func(bid Bid) canonicalBid {
+		return canonicalBid{
+			BidderID: bid.BidderID,
+			Amount:   bid.Amount,
+			Cents:    bid.Cents,
+			OffsetNs: int64(bid.Timestamp.Round(0).Sub(start)),
+			Demand:   bid.Demand,
+			Bundles:  bid.Bundles,
+		}
+	}
+	outcome := struct {
+		ID          int            `json:"id"`
+		Attributes  [20]float64    `json:"attributes"`
+		Importance  *[20]float64   `json:"importance"`
+		Status      AuctionStatus  `json:"status"`
+		Bids        []canonicalBid `json:"bids"`
+		TotalBids   int            `json:"total_bids"`
+		Winner      *canonicalBid  `json:"winner"`
+		Clearing    float64        `json:"clearing_price"`
+		Allocations []Allocation   `json:"allocations"`
+		TotalPaid   float64        `json:"total_paid"`
+		FeesPaid    float64        `json:"fees_paid"`
+		Reserve     float64        `json:"reserve,omitempty"`
+	}{
+		ID:          a.ID,
+		Attributes:  a.Attributes,
+		Importance:  a.AttributeImportance,
+		Status:      a.Status,
+		TotalBids:   a.TotalBids,
+		Clearing:    a.ClearingPrice,
+		Allocations: a.Allocations,
+		TotalPaid:   a.TotalPaid,
+		FeesPaid:    a.FeesPaid,
+		Reserve:     a.ReservePrice,
+	}
+	for _, bid := range a.Bids {
+		outcome.Bids = append(outcome.Bids, canonical(bid))
+	}
+	if a.Winner != nil {
+		winner := canonical(*a.Winner)
+		outcome.Winner = &winner
 	}
 
-	// Find the highest bid (first one in case of tie)
-	winner := &a.Bids[0]
-	for i := 1; i < len(a.Bids); i++ {
-		if a.Bids[i].Amount > winner.Amount {
-			winner = &a.Bids[i]
-		} else if a.Bids[i].Amount == winner.Amount && a.Bids[i].Timestamp.Before(winner.Timestamp) {
-			// In case of tie, earlier timestamp wins
-			winner = &a.Bids[i]
+	// Every field is a number, string or slice of them, so encoding cannot fail
+	data, _ := json.Marshal(outcome)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// BidderTrajectory returns one bidder's bids within an auction in timestamp
+// order, showing how the bid evolved across revisions
+func BidderTrajectory(a *Auction, bidderID int) []Bid {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var trajectory []Bid
+	for _, bid := range a.Bids {
+		if bid.BidderID == bidderID {
+			trajectory = append(trajectory, bid)
 		}
 	}
-	a.Winner = winner
+	sort.SliceStable(trajectory, func(i, j int) bool {
+		return trajectory[i].Timestamp.Before(trajectory[j].Timestamp)
+	})
+	return trajectory
 }
 
 // AuctionResult represents the result of a single auction
@@ -76,34 +1311,1007 @@ type AuctionResult struct {
 	Winner     *Bid          `json:"winner"`
 	Duration   time.Duration `json:"-"`
 	DurationMs int64         `json:"duration_ms"`
+	DurationNs int64         `json:"duration_ns"`
 }
 
 // ExecutionSummary represents the overall execution summary
 type ExecutionSummary struct {
-	TotalAuctions        int              `json:"total_auctions"`
-	FirstAuctionStart    time.Time        `json:"first_auction_start"`
-	LastAuctionEnd       time.Time        `json:"last_auction_end"`
-	TotalExecutionTimeMs int64            `json:"total_execution_time_ms"`
-	ResourceProfile      ResourceProfile  `json:"resource_profile"`
-	Statistics           Statistics       `json:"statistics"`
+	Status               RunStatus             `json:"status"`
+	TotalAuctions        int                   `json:"total_auctions"`
+	FirstAuctionStart    time.Time             `json:"first_auction_start,omitzero"`
+	LastAuctionEnd       time.Time             `json:"last_auction_end,omitzero"`
+	TotalExecutionTimeMs int64                 `json:"total_execution_time_ms"`
+	TotalExecutionTimeNs int64                 `json:"total_execution_time_ns"`
+	ResourceProfile      ResourceProfile       `json:"resource_profile"`
+	Statistics           Statistics            `json:"statistics"`
+	AvgPhaseTimings      PhaseTimings          `json:"avg_phase_timings"`
+	Shutdown             ShutdownReport        `json:"shutdown"`
+	Config               SimConfig             `json:"config"`
+	Retention            *RetentionReport      `json:"retention,omitempty"`
+	Groups               []GroupStats          `json:"groups,omitempty"`
+	Strategies           []StrategyStats       `json:"strategies,omitempty"`
+	Welfare              *WelfareReport        `json:"welfare,omitempty"`
+	WinnersCurse         *WinnersCurseReport   `json:"winners_curse,omitempty"`
+	Aggressiveness       *AggressivenessReport `json:"aggressiveness,omitempty"`
+	Information          *InformationReport    `json:"information,omitempty"`
+	Bundles              *BundleReport         `json:"bundles,omitempty"`
+	FailedAuctions       []int                 `json:"failed_auctions,omitempty"`
+	InjectedFaults       map[string]int        `json:"injected_faults,omitempty"`
+	AttributeTrend       *AttributeTrend       `json:"attribute_trend,omitempty"`
+	Revisions            RevisionStats         `json:"revisions"`
+	Webhook              *WebhookReport        `json:"webhook,omitempty"`
+	Kafka                *KafkaReport          `json:"kafka,omitempty"`
+	Participation        ParticipationReport   `json:"participation"`
+	ClampedBids          *ClampReport          `json:"clamped_bids,omitempty"`
+	SlowBidders          *SlowBidderReport     `json:"slow_bidders,omitempty"`
+	Attention            *AttentionReport      `json:"attention,omitempty"`
+	Backpressure         *BackpressureReport   `json:"backpressure,omitempty"`
+	StuckAuctions        []int                 `json:"stuck_auctions,omitempty"`
+	Ordering             *OrderingReport       `json:"ordering,omitempty"`
+	BidTiming            *BidTimingReport      `json:"bid_timing,omitempty"`
+	Arrivals             *ArrivalReport        `json:"arrivals,omitempty"`
+	Burst                *BurstReport          `json:"burst,omitempty"`
+	BidLimit             *BidLimitReport       `json:"bid_limit,omitempty"`
+
+	BidCorrelation      *BidCorrelationReport      `json:"bid_correlation,omitempty"`
+	AttributeRegression *AttributeRegressionReport `json:"attribute_regression,omitempty"`
+	Fingerprint         *RunFingerprint            `json:"fingerprint,omitempty"`
+	BidderStats         []BidderStats              `json:"bidder_stats,omitempty"`
+
+	AttributeTemplates []AttributeTemplateUse `json:"attribute_templates,omitempty"`
+}
+
+// RunFingerprint digests a whole run's outcome for regression checks. Root
+// is the SHA-256 of every auction's canonical hash in auction ID order, so
+// a fixed seed and config in serial mode always produce the same root.
+type RunFingerprint struct {
+	Root     string        `json:"root"`
+	Auctions []AuctionHash `json:"auctions"`
+}
+
+// AuctionHash is one auction's canonical hash within a run fingerprint
+type AuctionHash struct {
+	AuctionID int    `json:"auction_id"`
+	Hash      string `json:"hash"`
+}
+
+// AttributeTemplateUse is one shared attribute vector and the auctions that
+// used it
+type AttributeTemplateUse struct {
+	Template   int         `json:"template"`
+	Attributes [20]float64 `json:"attributes"`
+	AuctionIDs []int       `json:"auction_ids"`
+}
+
+// AggressivenessReport relates bidders' aggressiveness to how often they won.
+// Correlation is Pearson's r between aggressiveness and win rate over bidders
+// that entered at least one auction; Bands split those bidders into quartiles
+// by aggressiveness.
+type AggressivenessReport struct {
+	Bidders     int                  `json:"bidders"`
+	Correlation float64              `json:"correlation"`
+	Bands       []AggressivenessBand `json:"bands"`
+}
+
+// AggressivenessBand is the record of the bidders whose aggressiveness lies
+// within [Min, Max]. WinRate is wins per auction entered.
+type AggressivenessBand struct {
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+	Bidders int     `json:"bidders"`
+	Entries int     `json:"entries"`
+	Wins    int     `json:"wins"`
+	WinRate float64 `json:"win_rate"`
+	AvgBid  float64 `json:"avg_bid"`
+}
+
+// InformationReport relates how many attributes bidders observed to how they
+// fared. The correlations are Pearson's r between a bidder's visible
+// attribute count and its win rate, and its surplus per auction entered, over
+// bidders that entered at least one auction.
+type InformationReport struct {
+	Bidders            int                `json:"bidders"`
+	WinRateCorrelation float64            `json:"win_rate_correlation"`
+	SurplusCorrelation float64            `json:"surplus_correlation"`
+	Levels             []InformationLevel `json:"levels"`
+}
+
+// InformationLevel is the record of the bidders observing Visible attributes.
+// Surplus is the full-information value of what they won less what they paid.
+type InformationLevel struct {
+	Visible         int     `json:"visible"`
+	Bidders         int     `json:"bidders"`
+	Entries         int     `json:"entries"`
+	Wins            int     `json:"wins"`
+	WinRate         float64 `json:"win_rate"`
+	Surplus         float64 `json:"surplus"`
+	SurplusPerEntry float64 `json:"surplus_per_entry"`
+}
+
+// BidTimingReport describes how soon bids follow the first bid of their
+// auction. Each auction's first bid is left out, so a run of herding bidders
+// shows up as a high share within the shorter windows.
+type BidTimingReport struct {
+	Bids          int           `json:"bids"`
+	MeanMs        float64       `json:"mean_ms"`
+	PercentilesMs Percentiles   `json:"percentiles_ms"`
+	Within        []DeltaWindow `json:"within"`
+}
+
+// DeltaWindow is the share of following bids that arrived within WindowMs of
+// their auction's first bid
+type DeltaWindow struct {
+	WindowMs int     `json:"window_ms"`
+	Share    float64 `json:"share"`
+}
+
+// SimulationFile is a whole run in one self-contained document: the effective
+// config, the summary, the resource samples and every auction with its bids
+type SimulationFile struct {
+	Config          SimConfig        `json:"config"`
+	Summary         ExecutionSummary `json:"summary"`
+	ResourceSamples []ResourceSample `json:"resource_samples"`
+	Auctions        []*Auction       `json:"auctions"`
+}
+
+// ParticipationReport compares each bidder's configured participation rate
+// with the share of notified auctions it actually joined
+type ParticipationReport struct {
+	Bidders          int     `json:"bidders"`
+	MeanAbsDeviation float64 `json:"mean_abs_deviation"`
+	MaxAbsDeviation  float64 `json:"max_abs_deviation"`
+	MaxDeviationID   int     `json:"max_deviation_bidder_id,omitempty"`
+}
+
+// BurstReport counts the bids burst-mode bidders sent and those dropped on a
+// full bid channel, and summarizes each auction's peak bid arrival rate in
+// bids per second
+type BurstReport struct {
+	Size        int         `json:"size"`
+	Count       int         `json:"count"`
+	Sent        int         `json:"sent"`
+	Dropped     int         `json:"dropped"`
+	PeakBidRate Percentiles `json:"peak_bid_rate"`
+	MaxBidRate  float64     `json:"max_bid_rate"`
+}
+
+// BidLimitReport records how a run bounded by MaxTotalBids ended: the bids
+// admitted, those refused once the limit was reached and the auctions
+// cancelled before their deadline
+type BidLimitReport struct {
+	Limit    int   `json:"limit"`
+	Bids     int   `json:"bids"`
+	Refused  int   `json:"refused"`
+	Reached  bool  `json:"reached"`
+	CutShort []int `json:"cut_short,omitempty"`
+}
+
+// SlowBidderReport counts bids abandoned because computing them exceeded the
+// bidder processing timeout, and which bidders were responsible
+type SlowBidderReport struct {
+	TimeoutMs     int64 `json:"timeout_ms"`
+	AbandonedBids int   `json:"abandoned_bids"`
+	Bidders       []int `json:"bidder_ids,omitempty"`
+}
+
+// AttentionReport counts auctions bidders declined for lack of attention:
+// Saturated because they were already in MaxActive auctions, CoolingDown
+// because they had won within CooldownMs. DeclineRate is the share of
+// auctions bidders chose to join that they declined, and Bidders lists those
+// who declined any.
+type AttentionReport struct {
+	MaxActive   int     `json:"max_active,omitempty"`
+	CooldownMs  int64   `json:"cooldown_ms,omitempty"`
+	Saturated   int     `json:"saturated"`
+	CoolingDown int     `json:"cooling_down"`
+	DeclineRate float64 `json:"decline_rate"`
+	Bidders     []int   `json:"bidder_ids,omitempty"`
+}
+
+// ArrivalReport summarizes when bidders arrived at and left auctions, in
+// milliseconds after each auction opened
+type ArrivalReport struct {
+	Arrivals      int         `json:"arrivals"`
+	Departures    int         `json:"departures"`
+	LeftEarly     int         `json:"left_early"`
+	ArrivalMs     Percentiles `json:"arrival_ms"`
+	DepartureMs   Percentiles `json:"departure_ms"`
+	MeanArrivalMs float64     `json:"mean_arrival_ms"`
+	MeanStayMs    float64     `json:"mean_stay_ms"`
+}
+
+// ClampReport counts calculated bids that were clamped to the amount bounds
+type ClampReport struct {
+	AtMin int `json:"at_min"`
+	AtMax int `json:"at_max"`
+}
+
+// Backpressure selects what happens when a finished auction cannot be queued
+// because the result buffer is full
+type Backpressure string
+
+const (
+	// BackpressureBlock waits for the collector to make room
+	BackpressureBlock Backpressure = "block"
+	// BackpressureDropOldest discards the oldest queued result to make room
+	BackpressureDropOldest Backpressure = "drop-oldest"
+	// BackpressureError fails the auction whose result does not fit
+	BackpressureError Backpressure = "error"
+)
+
+// BackpressureReport counts results that found the result buffer full
+type BackpressureReport struct {
+	BufferSize int   `json:"buffer_size"`
+	Blocked    int   `json:"blocked"`
+	Dropped    []int `json:"dropped_auctions,omitempty"`
+	Rejected   []int `json:"rejected_auctions,omitempty"`
+}
+
+// OrderingReport measures what delivering results in auction ID order cost:
+// the most results held back at once and the longest any was held
+type OrderingReport struct {
+	PeakHeld  int     `json:"peak_held"`
+	MaxHeldMs float64 `json:"max_held_ms"`
+}
+
+// KafkaReport counts auction results published to Kafka. Blocked counts
+// results that waited for room in the producer's queue.
+type KafkaReport struct {
+	Delivered int `json:"delivered"`
+	Failed    int `json:"failed"`
+	Retries   int `json:"retries"`
+	Blocked   int `json:"blocked"`
+}
+
+// WebhookReport counts auction results delivered to the outbound webhook
+type WebhookReport struct {
+	Delivered int `json:"delivered"`
+	Failed    int `json:"failed"`
+}
+
+// RevisionStats describes how bidders revised their bids within auctions.
+// Each bid after a bidder's first in the same auction counts as a revision.
+type RevisionStats struct {
+	BiddersWithRevisions   int     `json:"bidders_with_revisions"`
+	AvgRevisionsPerBidder  float64 `json:"avg_revisions_per_bidder"`
+	AvgIncreasePerRevision float64 `json:"avg_increase_per_revision"`
+}
+
+// AttributeTrend describes how mean attribute values drifted across a run in
+// feedback mode, ordered by auction ID
+type AttributeTrend struct {
+	FirstMean       float64 `json:"first_mean"`
+	LastMean        float64 `json:"last_mean"`
+	SlopePerAuction float64 `json:"slope_per_auction"`
+}
+
+// BidCorrelationReport measures how consistently bidders bid relative to each
+// other across auctions
+type BidCorrelationReport struct {
+	AuctionPairs            int                 `json:"auction_pairs"`
+	MeanPairwiseCorrelation float64             `json:"mean_pairwise_correlation"`
+	Bidders                 []BidderConsistency `json:"bidders"`
+}
+
+// BidderConsistency describes one bidder's standing across the auctions it
+// bid in. MeanZScore is its average bid relative to each auction's bids in
+// standard deviations; a low ZScoreStdDev means it keeps that standing.
+type BidderConsistency struct {
+	BidderID          int     `json:"bidder_id"`
+	Auctions          int     `json:"auctions"`
+	MeanZScore        float64 `json:"mean_z_score"`
+	ZScoreStdDev      float64 `json:"z_score_std_dev"`
+	MarketCorrelation float64 `json:"market_correlation"`
+}
+
+// AttributeRegressionReport relates winning prices to item attributes across
+// a run: a least-squares fit of the winning amount on all 20 attributes, plus
+// each attribute's correlation with the winning amount on its own. The fit is
+// omitted when there are too few auctions to determine it.
+type AttributeRegressionReport struct {
+	Auctions   int               `json:"auctions"`
+	Fitted     bool              `json:"fitted"`
+	Intercept  float64           `json:"intercept,omitempty"`
+	RSquared   float64           `json:"r_squared,omitempty"`
+	Attributes []AttributeEffect `json:"attributes"`
+}
+
+// AttributeEffect is one attribute's relationship with the winning amount.
+// Coefficient is the change in winning amount per unit of the attribute with
+// the others held fixed.
+type AttributeEffect struct {
+	Attribute   int     `json:"attribute"`
+	Coefficient float64 `json:"coefficient,omitempty"`
+	Correlation float64 `json:"correlation"`
+}
+
+// StrategyStats summarizes how the bidders using one strategy fared. Entries
+// counts the auctions each bidder bid in and WinRate is the share of entries
+// won. Surplus is the value of everything won less everything paid.
+type StrategyStats struct {
+	Strategy   string  `json:"strategy"`
+	Bidders    int     `json:"bidders"`
+	Bids       int     `json:"bids"`
+	Entries    int     `json:"entries"`
+	Wins       int     `json:"wins"`
+	WinRate    float64 `json:"win_rate"`
+	AvgBid     float64 `json:"avg_bid"`
+	TotalSpend float64 `json:"total_spend"`
+	Surplus    float64 `json:"surplus"`
+}
+
+// BidderStats summarizes how one bidder fared across the run. Auctions counts
+// the auctions it bid in and WinRate is the share of them it won; TotalSpent
+// is what it paid under each auction's pricing.
+type BidderStats struct {
+	BidderID   int     `json:"bidder_id"`
+	Auctions   int     `json:"auctions"`
+	Bids       int     `json:"bids"`
+	Wins       int     `json:"wins"`
+	WinRate    float64 `json:"win_rate"`
+	TotalSpent float64 `json:"total_spent"`
+}
+
+// BundleReport describes how combinatorial auctions split into Items items
+// cleared. ClearedValue totals the winning bundle bids; WholeSetValue totals
+// the highest bid on every item together, what selling the items as one lot
+// would have raised, and Gain is the share by which ClearedValue exceeds it.
+// WholeSetWins counts auctions won outright by a single bundle of every item.
+type BundleReport struct {
+	Items         int     `json:"items"`
+	Auctions      int     `json:"auctions"`
+	ItemsOffered  int     `json:"items_offered"`
+	ItemsSold     int     `json:"items_sold"`
+	AvgWinners    float64 `json:"avg_winners"`
+	WholeSetWins  int     `json:"whole_set_wins"`
+	ClearedValue  float64 `json:"cleared_value"`
+	WholeSetValue float64 `json:"whole_set_value"`
+	Gain          float64 `json:"gain"`
+}
+
+// WinnersCurseReport measures the winner's curse in common-value auctions.
+// Overpaid counts the auctions with a winner, of Auctions, whose winner paid
+// more than the item's true value, and AvgOverpayment is the mean excess
+// among them. AvgMargin is the mean of true value less price over every sold
+// auction, negative when winners lose money on average.
+type WinnersCurseReport struct {
+	Auctions       int     `json:"auctions"`
+	Overpaid       int     `json:"overpaid"`
+	OverpaidRate   float64 `json:"overpaid_rate"`
+	AvgOverpayment float64 `json:"avg_overpayment"`
+	AvgMargin      float64 `json:"avg_margin"`
+}
+
+// WelfareReport compares the total valuation of what auctions allocated with
+// the most any allocation of the same units to the same bidders could have
+// achieved. PriceOfAnarchy is optimal over achieved welfare, 1 when every
+// auction went to the bidders valuing it most; EfficiencyLoss is the share of
+// optimal welfare lost. Misallocated counts auctions that fell short.
+type WelfareReport struct {
+	Auctions        int     `json:"auctions"`
+	Misallocated    int     `json:"misallocated"`
+	OptimalWelfare  float64 `json:"optimal_welfare"`
+	AchievedWelfare float64 `json:"achieved_welfare"`
+	PriceOfAnarchy  float64 `json:"price_of_anarchy"`
+	EfficiencyLoss  float64 `json:"efficiency_loss"`
+}
+
+// GroupStats summarizes how one affiliation group fared across all auctions
+type GroupStats struct {
+	GroupID int     `json:"group_id"`
+	Bidders int     `json:"bidders"`
+	Bids    int     `json:"bids"`
+	AvgBid  float64 `json:"avg_bid"`
+	Wins    int     `json:"wins"`
+	WinRate float64 `json:"win_rate"`
+}
+
+// RetentionReport describes the state of result-file retention after writing
+type RetentionReport struct {
+	RetainedFiles  int `json:"retained_files"`
+	RemovedFiles   int `json:"removed_files"`
+	TotalProcessed int `json:"total_processed"`
 }
 
 // ResourceProfile contains resource usage information
 type ResourceProfile struct {
-	MaxCPUs        int     `json:"max_cpus"`
-	PeakMemoryMB   float64 `json:"peak_memory_mb"`
-	AvgGoroutines  int     `json:"avg_goroutines"`
+	RequestedCPUs int     `json:"requested_cpus"`
+	MaxCPUs       int     `json:"max_cpus"`
+	PeakMemoryMB  float64 `json:"peak_memory_mb"`
+	AvgGoroutines int     `json:"avg_goroutines"`
+
+	// AvgCPUPercent and PeakCPUPercent are the process's measured CPU use as a
+	// percentage of MaxCPUs cores, over the whole run and over the busiest
+	// interval between samples; both are zero where CPU time is unavailable
+	AvgCPUPercent  float64 `json:"avg_cpu_percent"`
+	PeakCPUPercent float64 `json:"peak_cpu_percent"`
+
+	PeakBidGoroutines  int `json:"peak_bid_goroutines"`
+	PeakActiveAuctions int `json:"peak_active_auctions"`
+
+	MemoryPercentilesMB  Percentiles `json:"memory_percentiles_mb"`
+	GoroutinePercentiles Percentiles `json:"goroutine_percentiles"`
+
+	CPUQuota    *CPUQuotaReport    `json:"cpu_quota,omitempty"`
+	MemoryLimit *MemoryLimitReport `json:"memory_limit,omitempty"`
+}
+
+// MemoryLimitReport records how a soft memory limit held the run back:
+// Triggers counts the samples that found allocated memory over LimitMB, and
+// DelayedAuctions the auctions whose launch waited, PausedMs in total
+type MemoryLimitReport struct {
+	LimitMB         int64   `json:"limit_mb"`
+	Triggers        int     `json:"triggers"`
+	DelayedAuctions int     `json:"delayed_auctions"`
+	PausedMs        float64 `json:"paused_ms"`
+}
+
+// CPUQuotaReport compares a fractional CPU quota with the average number of
+// cores the run actually used, and says how long bidder work was held back
+// to meet it
+type CPUQuotaReport struct {
+	Quota         float64 `json:"quota"`
+	AchievedCores float64 `json:"achieved_cores"`
+	ThrottledMs   float64 `json:"throttled_ms"`
+}
+
+// ResourceSample is a single resource measurement taken during a run
+type ResourceSample struct {
+	Timestamp     time.Time `json:"timestamp"`
+	MemoryMB      float64   `json:"memory_mb"`
+	NumGoroutines int       `json:"goroutines"`
+	// CPUPercent is the process's CPU use since the previous sample as a
+	// percentage of GOMAXPROCS cores
+	CPUPercent float64 `json:"cpu_percent"`
+}
+
+// Percentiles summarizes a distribution of sampled values
+type Percentiles struct {
+	P50 float64 `json:"p50"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
 }
 
 // Statistics contains aggregate statistics
 type Statistics struct {
-	TotalBids            int     `json:"total_bids"`
-	AvgBidsPerAuction    float64 `json:"avg_bids_per_auction"`
-	AuctionsWithNoBids   int     `json:"auctions_with_no_bids"`
+	TotalBids          int     `json:"total_bids"`
+	AvgBidsPerAuction  float64 `json:"avg_bids_per_auction"`
+	AuctionsWithNoBids int     `json:"auctions_with_no_bids"`
+	AvgHHI             float64 `json:"avg_hhi"`
+
+	// AuctionsReserveNotMet counts auctions that had bids but went unsold
+	// because none reached the reserve
+	AuctionsReserveNotMet int `json:"auctions_reserve_not_met,omitempty"`
+
+	// ProxyBids counts proxy bids, and ProxyWins the auctions one won
+	ProxyBids int `json:"proxy_bids,omitempty"`
+	ProxyWins int `json:"proxy_wins,omitempty"`
+
+	// DutchSold counts Dutch auctions a bidder stopped the clock in, and
+	// AvgDutchSaleTick is the average tick they stopped it at
+	DutchSold        int     `json:"dutch_sold,omitempty"`
+	AvgDutchSaleTick float64 `json:"avg_dutch_sale_tick,omitempty"`
+
+	// TotalRevenue sums what every auction raised under its pricing mode;
+	// NetRevenue is what remains after the auction house's fees
+	TotalRevenue float64 `json:"total_revenue"`
+	TotalFees    float64 `json:"total_fees,omitempty"`
+	NetRevenue   float64 `json:"net_revenue"`
+
+	// GraceExtensions totals hammer-grace extensions across auctions, and
+	// AuctionsExtended counts the auctions that had at least one
+	GraceExtensions  int `json:"grace_extensions,omitempty"`
+	AuctionsExtended int `json:"auctions_extended,omitempty"`
+
+	// MinDurationFloorHits counts auctions held open by the minimum duration
+	MinDurationFloorHits int `json:"min_duration_floor_hits,omitempty"`
+
+	// LateBids counts bids that were ready only after their auction's deadline
+	LateBids int `json:"late_bids"`
+
+	// MergedBids counts bids collapsed by time-window coalescing
+	MergedBids int `json:"merged_bids,omitempty"`
+
+	// RejectedBids counts bids turned away by the acceptance policy or the
+	// minimum increment
+	RejectedBids int `json:"rejected_bids,omitempty"`
+
+	// DiscardedBids counts bids dropped to keep only the top MaxStoredBids
+	DiscardedBids int `json:"discarded_bids,omitempty"`
+
+	// Commitments totals sealed bids in commit-reveal auctions, and
+	// UnrevealedCommits and InvalidReveals the ones voided
+	Commitments       int `json:"commitments,omitempty"`
+	UnrevealedCommits int `json:"unrevealed_commits,omitempty"`
+	InvalidReveals    int `json:"invalid_reveals,omitempty"`
+
+	// UnitsSold totals the units allocated by multi-unit auctions, and
+	// AvgClearingPrice averages their clearing prices
+	UnitsSold        int     `json:"units_sold,omitempty"`
+	AvgClearingPrice float64 `json:"avg_clearing_price,omitempty"`
+
+	// AvgBidderLoss is what a losing bidder paid per auction on average; it is
+	// only non-zero under all-pay pricing
+	AvgBidderLoss float64 `json:"avg_bidder_loss,omitempty"`
+
+	// AvgWinnerDiscount is how far the winning bid exceeded the price paid
+	// per auction on average; it is only non-zero under second-price pricing
+	AvgWinnerDiscount float64 `json:"avg_winner_discount,omitempty"`
+}
+
+// SweepResult summarizes the key metrics of one run in a seed sweep
+type SweepResult struct {
+	Seed             int64
+	TotalAuctions    int
+	TotalBids        int
+	TotalRevenue     float64
+	NoBidRate        float64
+	DistinctWinners  int
+	WinConcentration float64
+	ExecutionTimeMs  int64
+}
+
+// ScalingResult is the throughput of one run in a CPU scaling sweep. Speedup
+// is bid throughput relative to the single-CPU run; Efficiency is speedup per
+// CPU.
+type ScalingResult struct {
+	CPUs           int
+	TotalAuctions  int
+	TotalBids      int
+	WallTimeMs     int64
+	AuctionsPerSec float64
+	BidsPerSec     float64
+	Speedup        float64
+	Efficiency     float64
+}
+
+// BidderProfile defines one bidder of a hand-specified population. Zero values
+// fall back to the generated defaults: no budget cap, random weights on every
+// valuation and the default strategy.
+type BidderProfile struct {
+	ID                int       `json:"id"`
+	ParticipationRate float64   `json:"participation_rate"`
+	Weights           []float64 `json:"weights,omitempty"`
+	Budget            float64   `json:"budget,omitempty"`
+	Strategy          string    `json:"strategy,omitempty"`
+	Aggressiveness    float64   `json:"aggressiveness,omitempty"`
+	DeadlineAware     bool      `json:"deadline_aware,omitempty"`
+
+	// VisibleAttributes lists the indices of the attributes the bidder
+	// observes; empty means it observes all 20
+	VisibleAttributes []int `json:"visible_attributes,omitempty"`
+
+	// Seed fixes the bidder's random source; zero draws one from the run seed
+	Seed int64 `json:"seed,omitempty"`
+}
+
+// StrategyShare is one bidding strategy's share of a strategy mix
+type StrategyShare struct {
+	Strategy string  `json:"strategy"`
+	Share    float64 `json:"share"`
+}
+
+// SimConfig holds the parameters of a single simulation run. It is embedded in
+// the execution summary, so it deliberately excludes output locations and other
+// machine-specific settings that do not affect the simulated outcome.
+type SimConfig struct {
+	Seed           int64          `json:"seed"`
+	NumAuctions    int            `json:"num_auctions"`
+	NumBidders     int            `json:"num_bidders"`
+	AuctionTimeout time.Duration  `json:"-"`
+	Resources      ResourceConfig `json:"resources"`
+
+	// AuctionTimeoutMax, if above AuctionTimeout, makes each auction's
+	// timeout a uniform draw between the two, so auctions close at different
+	// times
+	AuctionTimeoutMax time.Duration `json:"-"`
+
+	// RNG is the generator every random stream is drawn from. RNGSource, if
+	// set, supplies the seeded sources instead and RNG is GeneratorCustom.
+	RNG       Generator                    `json:"rng,omitempty"`
+	RNGSource func(seed int64) rand.Source `json:"-"`
+
+	// Bidders replaces the generated bidder population when non-empty;
+	// NumBidders is then the number of profiles
+	Bidders []BidderProfile `json:"bidders,omitempty"`
+
+	// CommonValueNoise makes auctions common-value: each has a hidden true
+	// value derived from its attributes, and every bidder bids from an
+	// estimate of it off by up to this fraction either way. Zero keeps
+	// private values.
+	CommonValueNoise float64 `json:"common_value_noise,omitempty"`
+
+	// StrategyMix splits the generated bidders between bidding strategies in
+	// proportion to their shares, in bidder ID order; empty leaves every
+	// generated bidder on the default strategy
+	StrategyMix []StrategyShare `json:"strategy_mix,omitempty"`
+
+	// MinBidAmount and MaxBidAmount clamp every calculated bid; zero leaves
+	// that side unbounded
+	MinBidAmount float64 `json:"min_bid_amount,omitempty"`
+	MaxBidAmount float64 `json:"max_bid_amount,omitempty"`
+
+	// RevealWindow makes every auction a two-phase sealed-bid auction:
+	// bidders commit hashed bids until the timeout, then have this long to
+	// reveal them. Zero runs single-phase auctions.
+	RevealWindow time.Duration `json:"-"`
+
+	// BidderTimeout bounds how long a bidder may spend computing a bid before
+	// the bid is abandoned; zero waits indefinitely
+	BidderTimeout time.Duration `json:"-"`
+
+	// DeadlineAware makes every generated bidder shorten its processing delay
+	// to fit the time left before the auction deadline
+	DeadlineAware bool `json:"deadline_aware,omitempty"`
+
+	// AggressivenessMin and AggressivenessMax bound the uniform distribution
+	// each bidder's aggressiveness is drawn from, unless its profile sets
+	// one. Zero leaves every bidder neutral (1).
+	AggressivenessMin float64 `json:"aggressiveness_min,omitempty"`
+	AggressivenessMax float64 `json:"aggressiveness_max,omitempty"`
+
+	// VisibleMin and VisibleMax bound how many of the 20 attributes each
+	// bidder observes, a uniformly drawn count of randomly chosen ones,
+	// unless its profile lists its own. Zero lets every bidder see all.
+	VisibleMin int `json:"visible_attributes_min,omitempty"`
+	VisibleMax int `json:"visible_attributes_max,omitempty"`
+
+	// RecordSequence logs every bid in the order and at the time each
+	// auction's collector received it
+	RecordSequence bool `json:"record_sequence,omitempty"`
+	// ReplaySequence feeds auctions the bids of a recorded sequence, in
+	// order and on time, in place of the bidders
+	ReplaySequence *BidSequence `json:"-"`
+
+	// OrderedResults delivers finished auctions to the collector and its
+	// hooks in ascending ID order, holding back any that finish early
+	OrderedResults bool `json:"ordered_results,omitempty"`
+
+	// ShuffleBidders notifies bidders in a different order for every auction,
+	// a permutation seeded from Seed and the auction ID, instead of ID order
+	ShuffleBidders bool `json:"shuffle_bidders,omitempty"`
+
+	// Arrivals has each bidder arrive at a random time during every auction
+	// instead of being there when it opens. DepartureRate is the chance an
+	// arrived bidder also leaves before the close.
+	Arrivals      bool    `json:"arrivals,omitempty"`
+	DepartureRate float64 `json:"departure_rate,omitempty"`
+
+	// BurstSize, for load testing, has every bidder send bursts of this many
+	// bids back to back instead of a single bid; BurstCount bursts, each
+	// after its own processing delay. Zero sends single bids.
+	BurstSize  int `json:"burst_size,omitempty"`
+	BurstCount int `json:"burst_count,omitempty"`
+
+	// MaxActiveAuctions caps how many auctions each bidder takes part in at
+	// once, and WinCooldown makes a winner sit out auctions opening within it
+	// of its win's close. A bidder over either limit declines the auction;
+	// zero leaves them off.
+	MaxActiveAuctions int           `json:"max_active_auctions,omitempty"`
+	WinCooldown       time.Duration `json:"-"`
+
+	// IntegerAmounts represents bid amounts as whole cents to avoid float drift
+	IntegerAmounts bool `json:"integer_amounts"`
+
+	// AttributeMode decides whether auctions draw their own attributes or
+	// share identical or templated vectors
+	AttributeMode AttributeMode `json:"attribute_mode,omitempty"`
+
+	// AttributeImportance gives every auction a random importance vector that
+	// scales each attribute's contribution to bidders' valuations
+	AttributeImportance bool `json:"attribute_importance,omitempty"`
+
+	// Acceptance is the policy deciding which received bids an auction keeps
+	Acceptance AcceptanceMode `json:"acceptance,omitempty"`
+
+	// Pricing decides who pays what when an auction closes
+	Pricing PricingMode `json:"pricing"`
+
+	// MaxStoredBids keeps only each auction's highest MaxStoredBids bids,
+	// summarizing the rest; zero stores every bid
+	MaxStoredBids int `json:"max_stored_bids,omitempty"`
+
+	// Units is how many identical units each auction sells; above one,
+	// bidders submit demand schedules and the market clears at the highest
+	// marginal bids
+	Units int `json:"units,omitempty"`
+
+	// ReserveMin and ReserveMax bound the reserve price drawn uniformly for
+	// each auction; equal values give every auction the same reserve, and
+	// zero sets none
+	ReserveMin float64 `json:"reserve_min,omitempty"`
+	ReserveMax float64 `json:"reserve_max,omitempty"`
+
+	// MinIncrement is how much each bid must beat its auction's current
+	// highest bid by; zero accepts any bid
+	MinIncrement float64 `json:"min_increment,omitempty"`
+
+	// ProxyRate is the chance a bidder places each bid by proxy: it leaves
+	// its bid as a maximum and the auction bids up to it in MinIncrement steps
+	ProxyRate float64 `json:"proxy_rate,omitempty"`
+
+	// DutchStart, if positive, makes every auction a Dutch auction: its price
+	// falls from DutchStart to DutchFloor in DutchTicks even steps over the
+	// timeout, and the first bidder to accept a price wins at it
+	DutchStart float64 `json:"dutch_start,omitempty"`
+	DutchFloor float64 `json:"dutch_floor,omitempty"`
+	DutchTicks int     `json:"dutch_ticks,omitempty"`
+
+	// BundleItems splits each auction's attributes into this many items;
+	// above one, bidders bid on bundles of items and each auction clears to
+	// the most valuable non-overlapping bundles
+	BundleItems int `json:"bundle_items,omitempty"`
+
+	// DurationUnit is how the printed summary and report show durations; the
+	// JSON output always carries both millisecond and nanosecond fields
+	DurationUnit DurationUnit `json:"duration_unit,omitempty"`
+
+	// Fee is the auction house's commission on each payment; nil charges none
+	Fee *FeeSchedule `json:"fee,omitempty"`
+
+	// NumGroups splits bidders round-robin into affiliation groups that share
+	// valuation signals; zero means every bidder values items independently
+	NumGroups int `json:"num_groups"`
+
+	// FeedbackStrength enables sequential feedback mode when positive: each
+	// auction's competition shifts the attributes of the next
+	FeedbackStrength float64 `json:"feedback_strength,omitempty"`
+
+	// FaultRate is the probability of injecting a failure at each fault site;
+	// zero disables fault injection
+	FaultRate float64 `json:"fault_rate,omitempty"`
+
+	// DrainTimeout bounds how long an auction, once it stops taking new bids,
+	// waits for its in-flight bid goroutines; bids they stamped in time are
+	// still counted
+	DrainTimeout time.Duration `json:"-"`
+
+	// HammerGrace is the final window in which a bid extends the auction by
+	// the same amount, at most HammerMaxExtensions times; zero disables it
+	HammerGrace         time.Duration `json:"-"`
+	HammerMaxExtensions int           `json:"hammer_max_extensions,omitempty"`
+
+	// MinDuration keeps every auction open, and its bidders bidding, at least
+	// this long, deferring early closes such as cancellation; zero disables
+	// the floor
+	MinDuration time.Duration `json:"-"`
+
+	// CoalesceWindow merges bids from the same bidder arriving within this
+	// window of each other before the winner is determined; zero disables it
+	CoalesceWindow time.Duration `json:"-"`
+	// CoalesceKeep selects which bid of a merged burst survives
+	CoalesceKeep CoalesceKeep `json:"coalesce_keep,omitempty"`
+
+	// MaxDuration stops the run once it has lasted this long, finalizing
+	// in-flight auctions with the bids collected so far; zero means no limit
+	MaxDuration time.Duration `json:"-"`
+
+	// MaxTotalBids stops the run once auctions have admitted this many bids
+	// between them, finalizing in-flight auctions and refusing any further
+	// bids; zero means no limit
+	MaxTotalBids int `json:"max_total_bids,omitempty"`
+
+	// WatchdogMargin is how long past its expected close an auction may go
+	// without delivering a result before it is reported as stuck; with
+	// WatchdogCancel the stuck auction's context is also cancelled
+	WatchdogMargin time.Duration `json:"-"`
+	WatchdogCancel bool          `json:"watchdog_cancel,omitempty"`
+
+	// Serial runs auctions one after another on a single goroutine with a
+	// simulated clock, so results depend only on the seed
+	Serial bool `json:"serial,omitempty"`
+
+	// ExternalBids keeps auctions open to bids submitted while they run, as
+	// the control server does, so the run is never switched to serial mode
+	ExternalBids bool `json:"external_bids,omitempty"`
+
+	// SubmissionOrder decides the sequence in which a serial auction's bids
+	// reach its collector. SubmissionSchedule holds the delay, by bidder ID,
+	// of each scheduled bidder for SubmitBySchedule. Neither affects
+	// concurrent runs.
+	SubmissionOrder    SubmissionOrder       `json:"submission_order,omitempty"`
+	SubmissionSchedule map[int]time.Duration `json:"-"`
+
+	// ResultBuffer is the capacity of the queue between finished auctions and
+	// the collector; zero sizes it to NumAuctions. Backpressure decides what
+	// happens when it is full.
+	ResultBuffer int          `json:"result_buffer,omitempty"`
+	Backpressure Backpressure `json:"backpressure,omitempty"`
+
+	// MaxBidGoroutines caps concurrent bid goroutines across all auctions;
+	// zero means unlimited
+	MaxBidGoroutines int `json:"max_bid_goroutines"`
+
+	// MaxConcurrentAuctions caps how many auctions run at once, queuing the
+	// rest in ID order; zero runs them all at once
+	MaxConcurrentAuctions int `json:"max_concurrent_auctions,omitempty"`
+}
+
+// MarshalJSON renders durations as strings such as "500ms" so a recorded
+// config can be passed straight back to the command-line flags
+func (c SimConfig) MarshalJSON() ([]byte, error) {
+	type plain SimConfig
+	return json.Marshal(struct {
+		plain
+		AuctionTimeout    string `json:"auction_timeout"`
+		AuctionTimeoutMax string `json:"auction_timeout_max,omitempty"`
+		DrainTimeout      string `json:"drain_timeout"`
+		CoalesceWindow    string `json:"coalesce_window,omitempty"`
+		MinDuration       string `json:"min_duration,omitempty"`
+		HammerGrace       string `json:"hammer_grace,omitempty"`
+		WatchdogMargin    string `json:"watchdog_margin,omitempty"`
+		MaxDuration       string `json:"max_duration,omitempty"`
+		BidderTimeout     string `json:"bidder_timeout,omitempty"`
+		RevealWindow      string `json:"reveal_window,omitempty"`
+		WinCooldown       string `json:"win_cooldown,omitempty"`
+	}{
+		plain:             plain(c),
+		AuctionTimeout:    c.AuctionTimeout.String(),
+		AuctionTimeoutMax: durationOrEmpty(c.AuctionTimeoutMax),
+		DrainTimeout:      c.DrainTimeout.String(),
+		CoalesceWindow:    durationOrEmpty(c.CoalesceWindow),
+		MinDuration:       durationOrEmpty(c.MinDuration),
+		HammerGrace:       durationOrEmpty(c.HammerGrace),
+		WatchdogMargin:    durationOrEmpty(c.WatchdogMargin),
+		MaxDuration:       durationOrEmpty(c.MaxDuration),
+		BidderTimeout:     durationOrEmpty(c.BidderTimeout),
+		RevealWindow:      durationOrEmpty(c.RevealWindow),
+		WinCooldown:       durationOrEmpty(c.WinCooldown),
+	})
+}
+
+// UnmarshalJSON reads a config written by MarshalJSON, parsing its durations
+// back from strings
+func (c *SimConfig) UnmarshalJSON(data []byte) error {
+	type plain SimConfig
+	aux := struct {
+		*plain
+		AuctionTimeout    string `json:"auction_timeout"`
+		AuctionTimeoutMax string `json:"auction_timeout_max"`
+		DrainTimeout      string `json:"drain_timeout"`
+		CoalesceWindow    string `json:"coalesce_window"`
+		MinDuration       string `json:"min_duration"`
+		HammerGrace       string `json:"hammer_grace"`
+		WatchdogMargin    string `json:"watchdog_margin"`
+		MaxDuration       string `json:"max_duration"`
+		BidderTimeout     string `json:"bidder_timeout"`
+		RevealWindow      string `json:"reveal_window"`
+		WinCooldown       string `json:"win_cooldown"`
+	}{plain: (*plain)(c)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	durations := []struct {
+		name  string
+		value string
+		dst   *time.Duration
+	}{
+		{"auction_timeout", aux.AuctionTimeout, &c.AuctionTimeout},
+		{"auction_timeout_max", aux.AuctionTimeoutMax, &c.AuctionTimeoutMax},
+		{"drain_timeout", aux.DrainTimeout, &c.DrainTimeout},
+		{"coalesce_window", aux.CoalesceWindow, &c.CoalesceWindow},
+		{"min_duration", aux.MinDuration, &c.MinDuration},
+		{"hammer_grace", aux.HammerGrace, &c.HammerGrace},
+		{"watchdog_margin", aux.WatchdogMargin, &c.WatchdogMargin},
+		{"max_duration", aux.MaxDuration, &c.MaxDuration},
+		{"bidder_timeout", aux.BidderTimeout, &c.BidderTimeout},
+		{"reveal_window", aux.RevealWindow, &c.RevealWindow},
+		{"win_cooldown", aux.WinCooldown, &c.WinCooldown},
+	}
+	for _, d := range durations {
+		if d.value == "" {
+			continue
+		}
+		parsed, err := time.ParseDuration(d.value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", d.name, err)
+		}
+		*d.dst = parsed
+	}
+	return nil
+}
+
+// durationOrEmpty formats d, or returns "" for zero so omitempty drops it
+func durationOrEmpty(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
+
+// RunOutcome names how a simulation run ended
+type RunOutcome string
+
+const (
+	// RunCompleted means every auction ran its full course
+	RunCompleted RunOutcome = "completed"
+	// RunInterrupted means the run was cancelled from outside, e.g. by a signal
+	RunInterrupted RunOutcome = "interrupted"
+	// RunTimedOut means the run hit its maximum duration
+	RunTimedOut RunOutcome = "timed_out"
+	// RunBidLimit means the run stopped once it admitted MaxTotalBids bids
+	RunBidLimit RunOutcome = "bid_limit"
+)
+
+// RunStatus records how a run ended and what triggered it
+type RunStatus struct {
+	Outcome RunOutcome `json:"outcome"`
+	Detail  string     `json:"detail,omitempty"`
+}
+
+// RunResult holds everything produced by a single simulation run
+type RunResult struct {
+	Status          RunStatus
+	Config          SimConfig
+	Auctions        []*Auction
+	FirstStart      time.Time
+	LastEnd         time.Time
+	ResourceProfile ResourceProfile
+	Shutdown        ShutdownReport
+	FailedAuctions  []int
+	LateBids        int
+	Participation   ParticipationReport
+	ClampedBids     *ClampReport
+	SlowBidders     *SlowBidderReport
+	Attention       *AttentionReport
+	Burst           *BurstReport
+	BidLimit        *BidLimitReport
+	Backpressure    *BackpressureReport
+	Ordering        *OrderingReport
+	StuckAuctions   []int
+
+	// Bidders describes the population that took part, with each bidder's seed
+	Bidders []BidderProfile
+
+	// Samples are the resource measurements taken while the run was active,
+	// from SamplesStart, when monitoring began
+	Samples      []ResourceSample
+	SamplesStart time.Time
+
+	// Sequence is the recorded bid arrival order, if RecordSequence was set
+	Sequence *BidSequence
+
+	// Webhook and Kafka are filled in by the caller once their sinks have
+	// flushed
+	Webhook *WebhookReport
+	Kafka   *KafkaReport
+}
+
+// SequencedBid is one bid as an auction's collector received it. Seq orders
+// receipt across the whole run. OffsetNs is when the bid was received and
+// BidOffsetNs when it was placed, both counted from its auction's start.
+type SequencedBid struct {
+	Seq         int   `json:"seq"`
+	AuctionID   int   `json:"auction_id"`
+	OffsetNs    int64 `json:"offset_ns"`
+	BidOffsetNs int64 `json:"bid_offset_ns"`
+	Bid         Bid   `json:"bid"`
+}
+
+// BidSequence is the exact order and timing in which a run's auctions
+// received their bids, for replaying a concurrent interleaving
+type BidSequence struct {
+	Seed        int64          `json:"seed"`
+	NumAuctions int            `json:"num_auctions"`
+	Bids        []SequencedBid `json:"bids"`
+}
+
+// ShutdownReport describes how cleanly in-flight work drained by shutdown
+type ShutdownReport struct {
+	DrainTimeoutMs       int64 `json:"drain_timeout_ms"`
+	PendingBidGoroutines int   `json:"pending_bid_goroutines"`
 }
 
 // ResourceConfig defines resource constraints
 type ResourceConfig struct {
-	MaxCPUs     int
-	MaxMemoryMB int64
+	MaxCPUs int `json:"max_cpus"`
+
+	// MaxMemoryMB is a soft limit on allocated memory: while samples exceed
+	// it, no new auction is launched. Zero sets no limit.
+	MaxMemoryMB int64 `json:"max_memory_mb"`
+
+	// CPUQuota holds bidder work to this many cores, which may be fractional;
+	// GOMAXPROCS is rounded up to cover it. Zero applies no quota.
+	CPUQuota float64 `json:"cpu_quota,omitempty"`
 }
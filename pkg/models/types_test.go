@@ -0,0 +1,149 @@
+package models
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestSnapshotWhileBidding reads snapshots while bids are added; run it with
+// -race. Every snapshot must be internally consistent: its bid count, bids
+// and leader all describe the same moment.
+func TestSnapshotWhileBidding(t *testing.T) {
+	const bids = 2000
+	auction := NewAuction(1, time.Second, 0)
+	auction.StartTime = time.Now()
+	start := auction.StartTime
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := range bids {
+			auction.AddBid(Bid{BidderID: i%50 + 1, Amount: float64(i + 1), Timestamp: start.Add(time.Duration(i))})
+		}
+		auction.Close()
+	}()
+
+	last := 0
+	for {
+		view := auction.Snapshot()
+		if view.TotalBids != len(view.Bids) {
+			t.Fatalf("snapshot counts %d bids but holds %d", view.TotalBids, len(view.Bids))
+		}
+		if view.TotalBids < last {
+			t.Fatalf("bid count went back from %d to %d", last, view.TotalBids)
+		}
+		last = view.TotalBids
+		if n := len(view.Bids); n > 0 && (view.Leader == nil || view.Leader.Amount != view.Bids[n-1].Amount) {
+			t.Fatalf("leader %v is not the highest of %d rising bids", view.Leader, n)
+		}
+		if !view.Open {
+			break
+		}
+	}
+	wg.Wait()
+
+	if view := auction.Snapshot(); view.TotalBids != bids {
+		t.Errorf("final snapshot has %d bids, want %d", view.TotalBids, bids)
+	}
+}
+
+// TestIntegerAmountTies bids amounts that are equal in cents but not as
+// floats: 0.1+0.2 is a hair above 0.3. In integer mode they must tie, so the
+// earlier bid wins; in float mode the drift decides.
+func TestIntegerAmountTies(t *testing.T) {
+	start := time.Now()
+	tenth, fifth := 0.1, 0.2
+	drifted := tenth + fifth // Variables, so the sum is not an exact constant
+	tests := []struct {
+		name    string
+		integer bool
+		amounts []float64
+		want    int // Winning bidder
+	}{
+		{"integer tie, earlier first", true, []float64{0.3, drifted}, 1},
+		{"integer tie, earlier second", true, []float64{drifted, 0.3}, 1},
+		{"integer higher cents wins", true, []float64{0.3, 0.31}, 2},
+		{"float drift breaks the tie", false, []float64{0.3, drifted}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auction := NewAuction(1, time.Second, 0)
+			auction.IntegerAmounts = tt.integer
+			for i, amount := range tt.amounts {
+				auction.Bids = append(auction.Bids, Bid{
+					BidderID:  i + 1,
+					Amount:    amount,
+					Cents:     ToCents(amount),
+					Timestamp: start.Add(time.Duration(i) * time.Millisecond),
+				})
+			}
+
+			x, y := &auction.Bids[0], &auction.Bids[1]
+			wantCmp := 0
+			if !tt.integer || x.Cents != y.Cents {
+				wantCmp = -1
+			}
+			if got := auction.compareAmounts(x, y); got != wantCmp {
+				t.Errorf("compareAmounts = %d, want %d", got, wantCmp)
+			}
+			if got := auction.highestBid(); got.BidderID != tt.want {
+				t.Errorf("highestBid is bidder %d, want %d", got.BidderID, tt.want)
+			}
+		})
+	}
+}
+
+// TestProxyBidding covers the canonical two-proxy case, in both arrival
+// orders and with float and integer amounts: maximums of 100 and 150 with a
+// 1.00 increment leave the 150 proxy winning at 101. A single proxy must also
+// top a run of live bids by one increment.
+func TestProxyBidding(t *testing.T) {
+	type bid struct {
+		bidder      int
+		amount, max float64
+	}
+	tests := []struct {
+		name    string
+		bids    []bid
+		winner  int
+		price   float64
+		rejects int
+	}{
+		{"lower proxy first", []bid{{1, 0, 100}, {2, 0, 150}}, 2, 101, 0},
+		{"higher proxy first", []bid{{2, 0, 150}, {1, 0, 100}}, 2, 101, 0},
+		{"equal maximums, earlier wins", []bid{{1, 0, 150}, {2, 0, 150}}, 1, 150, 0},
+		{"proxy over live bids", []bid{{1, 10, 0}, {2, 40, 0}, {3, 90, 0}, {4, 0, 500}}, 4, 91, 0},
+		{"live bid short of the proxy price", []bid{{1, 0, 150}, {2, 100, 0}, {3, 120, 0}}, 1, 121, 0},
+		{"proxy maximum below the next bid", []bid{{1, 200, 0}, {2, 0, 150}}, 1, 200, 1},
+	}
+	for _, integer := range []bool{false, true} {
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("%s/integer=%v", tt.name, integer), func(t *testing.T) {
+				start := time.Now()
+				auction := NewAuction(1, time.Second, 0)
+				auction.MinIncrement, auction.MinIncrementCents = 1, 100
+				auction.IntegerAmounts = integer
+				for i, b := range tt.bids {
+					auction.AddBid(Bid{BidderID: b.bidder, Amount: b.amount, MaxBid: b.max, Timestamp: start.Add(time.Duration(i) * time.Millisecond)})
+				}
+				auction.DetermineWinner()
+
+				if auction.Winner == nil || auction.Winner.BidderID != tt.winner {
+					t.Fatalf("winner %+v, want bidder %d", auction.Winner, tt.winner)
+				}
+				if auction.WinningPrice != tt.price {
+					t.Errorf("winning price %v, want %v", auction.WinningPrice, tt.price)
+				}
+				if integer && auction.WinningPriceCents != ToCents(tt.price) {
+					t.Errorf("winning price %d cents, want %d", auction.WinningPriceCents, ToCents(tt.price))
+				}
+				if auction.RejectedBids != tt.rejects {
+					t.Errorf("%d bids rejected, want %d", auction.RejectedBids, tt.rejects)
+				}
+			})
+		}
+	}
+}
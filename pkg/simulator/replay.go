@@ -0,0 +1,67 @@
+package simulator
+
+import (
+	"time"
+
+	"auction-simulator/pkg/models"
+)
+
+// Replay re-decides recorded auctions under the rules in config, without
+// re-simulating any bidding. Each auction keeps its attributes, timing and
+// bids; only bid normalization, coalescing and winner determination run
+// again, using config's pricing, fee, coalescing and integer-amount settings.
+// The recorded auctions are not modified.
+func Replay(recorded []*models.Auction, config models.SimConfig) (*Result, error) {
+	config.NumAuctions = len(recorded)
+	config = withDefaults(config)
+	if err := validate(config); err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Status: models.RunStatus{Outcome: models.RunCompleted},
+		Config: config,
+	}
+
+	for _, rec := range recorded {
+		auction := models.NewAuction(rec.ID, rec.Timeout, len(rec.Bids))
+		auction.Attributes = rec.Attributes
+		auction.AttributeTemplate = rec.AttributeTemplate
+		auction.AttributeImportance = rec.AttributeImportance
+		auction.StartTime = rec.StartTime
+		auction.EndTime = rec.EndTime
+		auction.FirstBidTime = rec.FirstBidTime
+		auction.Status = rec.Status
+		auction.Phases = rec.Phases
+		auction.Commitments = rec.Commitments
+		auction.UnrevealedCommits = rec.UnrevealedCommits
+		auction.InvalidReveals = rec.InvalidReveals
+		auction.Presence = rec.Presence
+		auction.Discarded = rec.Discarded
+		auction.PeakBidRate = rec.PeakBidRate
+		auction.AttributeBias = rec.AttributeBias
+		auction.IntegerAmounts = config.IntegerAmounts
+		auction.Pricing = config.Pricing
+		auction.Units = rec.Units
+		auction.Fee = config.Fee
+
+		for _, bid := range rec.Bids {
+			auction.AddBid(bid)
+		}
+		auction.Close()
+
+		start := time.Now()
+		auction.Coalesce(config.CoalesceWindow, config.CoalesceKeep)
+		auction.DetermineWinner()
+		auction.Phases.WinnerDeterminationMs = float64(time.Since(start)) / float64(time.Millisecond)
+
+		result.Auctions = append(result.Auctions, auction)
+		if result.FirstStart.IsZero() || auction.StartTime.Before(result.FirstStart) {
+			result.FirstStart = auction.StartTime
+		}
+		if auction.EndTime.After(result.LastEnd) {
+			result.LastEnd = auction.EndTime
+		}
+	}
+	return result, nil
+}
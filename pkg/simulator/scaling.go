@@ -0,0 +1,69 @@
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auction-simulator/pkg/models"
+)
+
+// ScalingGain is the smallest rise in bid throughput from one more CPU that
+// still counts as scaling; the knee of the curve is where gains fall below it
+const ScalingGain = 0.10
+
+// SweepCPUs runs the base configuration once per CPU count from 1 to maxCPUs,
+// varying only the CPU limit, and returns the throughput of each run. Once ctx
+// is done it stops after the interrupted run, returning the rows so far.
+func SweepCPUs(ctx context.Context, base models.SimConfig, maxCPUs int) ([]models.ScalingResult, error) {
+	rows := make([]models.ScalingResult, 0, maxCPUs)
+	for cpus := 1; cpus <= maxCPUs; cpus++ {
+		config := base
+		config.Resources.MaxCPUs = cpus
+
+		start := time.Now()
+		result, err := RunSimulation(ctx, config)
+		if err != nil {
+			return rows, fmt.Errorf("%d CPUs: %w", cpus, err)
+		}
+		wall := time.Since(start)
+
+		row := models.ScalingResult{
+			CPUs:          cpus,
+			TotalAuctions: len(result.Auctions),
+			WallTimeMs:    wall.Milliseconds(),
+		}
+		for _, auction := range result.Auctions {
+			row.TotalBids += auction.TotalBids
+		}
+		if seconds := wall.Seconds(); seconds > 0 {
+			row.AuctionsPerSec = float64(row.TotalAuctions) / seconds
+			row.BidsPerSec = float64(row.TotalBids) / seconds
+		}
+		if len(rows) > 0 && rows[0].BidsPerSec > 0 {
+			row.Speedup = row.BidsPerSec / rows[0].BidsPerSec
+		} else {
+			row.Speedup = 1
+		}
+		row.Efficiency = row.Speedup / float64(cpus)
+		rows = append(rows, row)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return rows, nil
+}
+
+// ScalingKnee returns the CPU count after which one more CPU raises bid
+// throughput by less than ScalingGain, or zero for an empty sweep
+func ScalingKnee(rows []models.ScalingResult) int {
+	if len(rows) == 0 {
+		return 0
+	}
+	for i := 1; i < len(rows); i++ {
+		if rows[i].BidsPerSec < rows[i-1].BidsPerSec*(1+ScalingGain) {
+			return rows[i-1].CPUs
+		}
+	}
+	return rows[len(rows)-1].CPUs
+}
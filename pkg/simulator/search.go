@@ -0,0 +1,92 @@
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"auction-simulator/pkg/models"
+)
+
+// DefaultSearchCount is how many seeds a search tries when the spec omits it
+const DefaultSearchCount = 20
+
+// SearchMetrics maps each metric a seed search can optimize to its value in a
+// sweep row
+var SearchMetrics = map[string]func(models.SweepResult) float64{
+	"total_bids":        func(r models.SweepResult) float64 { return float64(r.TotalBids) },
+	"total_revenue":     func(r models.SweepResult) float64 { return r.TotalRevenue },
+	"no_bid_rate":       func(r models.SweepResult) float64 { return r.NoBidRate },
+	"distinct_winners":  func(r models.SweepResult) float64 { return float64(r.DistinctWinners) },
+	"win_concentration": func(r models.SweepResult) float64 { return r.WinConcentration },
+}
+
+// SeedSearch describes a search for the seed that maximizes or minimizes a metric
+type SeedSearch struct {
+	Metric   string
+	Maximize bool
+	Count    int
+}
+
+// ParseSeedSearch parses a "metric:max|min[:count]" specification, e.g.
+// "total_revenue:max:50"
+func ParseSeedSearch(spec string) (SeedSearch, error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return SeedSearch{}, fmt.Errorf("seed search %q must be metric:max|min[:count]", spec)
+	}
+
+	search := SeedSearch{Metric: parts[0], Count: DefaultSearchCount}
+	if _, ok := SearchMetrics[search.Metric]; !ok {
+		names := make([]string, 0, len(SearchMetrics))
+		for name := range SearchMetrics {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		return SeedSearch{}, fmt.Errorf("seed search %q: unknown metric %q (want one of %s)", spec, search.Metric, strings.Join(names, ", "))
+	}
+
+	switch parts[1] {
+	case "max":
+		search.Maximize = true
+	case "min":
+	default:
+		return SeedSearch{}, fmt.Errorf("seed search %q: target must be max or min, got %q", spec, parts[1])
+	}
+
+	if len(parts) == 3 {
+		count, err := strconv.Atoi(parts[2])
+		if err != nil || count <= 0 {
+			return SeedSearch{}, fmt.Errorf("seed search %q: count must be a positive integer", spec)
+		}
+		search.Count = count
+	}
+	return search, nil
+}
+
+// SearchSeeds runs the base configuration with seeds 1 through Count and
+// returns the rows ranked best first. The seed list is fixed and ties keep the
+// lower seed first, so a search is as repeatable as the runs it compares.
+func SearchSeeds(ctx context.Context, base models.SimConfig, search SeedSearch) ([]models.SweepResult, error) {
+	seeds := make([]int64, search.Count)
+	for i := range seeds {
+		seeds[i] = int64(i + 1)
+	}
+
+	rows, err := SweepSeeds(ctx, base, seeds)
+	if err != nil {
+		return nil, err
+	}
+
+	value := SearchMetrics[search.Metric]
+	sort.SliceStable(rows, func(i, j int) bool {
+		if search.Maximize {
+			return value(rows[i]) > value(rows[j])
+		}
+		return value(rows[i]) < value(rows[j])
+	})
+	return rows, nil
+}
@@ -0,0 +1,725 @@
+package simulator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"auction-simulator/internal/auction"
+	"auction-simulator/internal/bidder"
+	"auction-simulator/internal/faults"
+	"auction-simulator/internal/manager"
+	"auction-simulator/internal/resource"
+	"auction-simulator/internal/rng"
+	"auction-simulator/pkg/models"
+)
+
+// Result holds everything produced by a single simulation run
+type Result = models.RunResult
+
+// BidStrategy turns a bidder's valuation into its bid; see RegisterStrategy
+type BidStrategy = bidder.BidStrategy
+
+// Noisy gives an embedding BidStrategy the built-in strategies' valuation noise
+type Noisy = bidder.Noisy
+
+// RegisterStrategy makes s available under name to bidder profiles and the
+// strategy mix of every simulation created afterwards. It fails if name is
+// empty or taken.
+func RegisterStrategy(name string, s BidStrategy) error {
+	return bidder.RegisterStrategy(bidder.Strategy(name), s)
+}
+
+// Simulation is a configured, runnable simulation
+type Simulation struct {
+	config models.SimConfig
+	mgr    *manager.Manager
+
+	// onSample is called with each resource monitor sample, if set
+	onSample func(models.ResourceSample)
+}
+
+// DefaultHammerMaxExtensions caps grace extensions when HammerGrace is set
+// without an explicit cap
+const DefaultHammerMaxExtensions = 3
+
+// DefaultDutchTicks is how many prices a Dutch clock announces when
+// DutchStart is set without a tick count
+const DefaultDutchTicks = 50
+
+// SerialMaxBidders is the largest population for which a single-auction run
+// is switched to serial mode automatically
+const SerialMaxBidders = 10
+
+// DefaultConfig returns the configuration used when no options are given
+func DefaultConfig() models.SimConfig {
+	return models.SimConfig{
+		Seed:           time.Now().UnixNano(),
+		NumAuctions:    manager.NumAuctions,
+		NumBidders:     manager.NumBidders,
+		AuctionTimeout: manager.AuctionTimeout,
+		Pricing:        models.PricingFirstPrice,
+		Resources: models.ResourceConfig{
+			MaxCPUs: runtime.NumCPU(),
+		},
+	}
+}
+
+// withDefaults fills any unset fields of config from DefaultConfig
+func withDefaults(config models.SimConfig) models.SimConfig {
+	defaults := DefaultConfig()
+	if len(config.Bidders) > 0 {
+		config.NumBidders = len(config.Bidders)
+	}
+	if config.NumAuctions == 0 {
+		config.NumAuctions = defaults.NumAuctions
+	}
+	if config.NumBidders == 0 {
+		config.NumBidders = defaults.NumBidders
+	}
+	if config.AuctionTimeout == 0 {
+		config.AuctionTimeout = defaults.AuctionTimeout
+	}
+	if config.Resources.MaxCPUs == 0 {
+		config.Resources.MaxCPUs = defaults.Resources.MaxCPUs
+	}
+	if config.HammerGrace > 0 && config.HammerMaxExtensions == 0 {
+		config.HammerMaxExtensions = DefaultHammerMaxExtensions
+	}
+	if config.CoalesceKeep == "" {
+		config.CoalesceKeep = models.CoalesceLatest
+	}
+	if config.Pricing == "" {
+		config.Pricing = defaults.Pricing
+	}
+	if config.Acceptance == "" {
+		config.Acceptance = models.AcceptAll
+	}
+	if config.RNGSource != nil {
+		config.RNG = models.GeneratorCustom
+	} else if config.RNG == "" {
+		config.RNG = models.GeneratorMathRand
+	}
+	if config.SubmissionOrder == "" {
+		config.SubmissionOrder = models.SubmitByDelay
+	}
+	if config.AttributeMode == "" {
+		config.AttributeMode = models.AttributesRandom
+	}
+	if config.DurationUnit == "" {
+		config.DurationUnit = models.UnitMilliseconds
+	}
+	if config.WatchdogMargin == 0 {
+		config.WatchdogMargin = manager.DefaultWatchdogMargin
+	}
+	if config.Backpressure == "" {
+		config.Backpressure = models.BackpressureBlock
+	}
+	if config.BurstSize > 0 && config.BurstCount == 0 {
+		config.BurstCount = 1
+	}
+	if config.DutchStart > 0 && config.DutchTicks == 0 {
+		config.DutchTicks = DefaultDutchTicks
+	}
+	return config
+}
+
+// validate rejects configurations that cannot be simulated
+func validate(config models.SimConfig) error {
+	if err := bidder.ValidateProfiles(config.Bidders); err != nil {
+		return err
+	}
+	if config.NumAuctions <= 0 || config.NumBidders <= 0 {
+		return fmt.Errorf("number of auctions and bidders must be positive, got %d and %d", config.NumAuctions, config.NumBidders)
+	}
+	if config.FaultRate < 0 || config.FaultRate > 1 {
+		return fmt.Errorf("fault rate must be within [0, 1], got %v", config.FaultRate)
+	}
+	if config.RNG != models.GeneratorCustom {
+		if _, err := rng.Source(config.RNG); err != nil {
+			return err
+		}
+	} else if config.RNGSource == nil {
+		return fmt.Errorf("the %q generator needs an RNGSource", models.GeneratorCustom)
+	}
+	switch config.SubmissionOrder {
+	case models.SubmitByDelay, models.SubmitByBidderID, models.SubmitShuffled:
+		if len(config.SubmissionSchedule) > 0 {
+			return fmt.Errorf("a submission schedule needs the %q submission order", models.SubmitBySchedule)
+		}
+	case models.SubmitBySchedule:
+		if len(config.SubmissionSchedule) == 0 {
+			return fmt.Errorf("the %q submission order needs a schedule", models.SubmitBySchedule)
+		}
+		for id, delay := range config.SubmissionSchedule {
+			if delay < 0 {
+				return fmt.Errorf("submission schedule: bidder %d has a negative delay %v", id, delay)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown submission order %q (want %q, %q, %q or %q)", config.SubmissionOrder,
+			models.SubmitByDelay, models.SubmitByBidderID, models.SubmitShuffled, models.SubmitBySchedule)
+	}
+	switch config.Pricing {
+	case models.PricingFirstPrice, models.PricingSecondPrice, models.PricingAllPay, models.PricingUniform:
+	default:
+		return fmt.Errorf("unknown pricing mode %q (want %q, %q, %q or %q)", config.Pricing,
+			models.PricingFirstPrice, models.PricingSecondPrice, models.PricingAllPay, models.PricingUniform)
+	}
+	if config.Units < 0 {
+		return fmt.Errorf("units must not be negative, got %d", config.Units)
+	}
+	if config.Units > 1 && (config.Pricing == models.PricingAllPay || config.Pricing == models.PricingSecondPrice) {
+		return fmt.Errorf("%q pricing supports single-unit auctions only, got %d units", config.Pricing, config.Units)
+	}
+	if config.ReserveMin < 0 || config.ReserveMin > config.ReserveMax {
+		return fmt.Errorf("reserve range [%v, %v] must be non-negative and ordered", config.ReserveMin, config.ReserveMax)
+	}
+	if config.ReserveMax > 0 {
+		switch {
+		case config.Pricing == models.PricingAllPay:
+			return fmt.Errorf("%q pricing charges every bid whether or not the auction sells, so it takes no reserve", models.PricingAllPay)
+		case config.BundleItems > 1:
+			return fmt.Errorf("combinatorial auctions take no reserve price")
+		}
+	}
+	if config.MinIncrement < 0 {
+		return fmt.Errorf("minimum increment must not be negative, got %v", config.MinIncrement)
+	}
+	if config.MinIncrement > 0 {
+		// The increment is measured against a single visible leading bid
+		switch {
+		case config.Units > 1 || config.BundleItems > 1:
+			return fmt.Errorf("a minimum increment applies to single-item auctions only")
+		case config.RevealWindow > 0:
+			return fmt.Errorf("commit-reveal auctions hide the leading bid, so they take no minimum increment")
+		}
+	}
+	if config.ProxyRate < 0 || config.ProxyRate > 1 {
+		return fmt.Errorf("proxy rate must be within [0, 1], got %v", config.ProxyRate)
+	}
+	if config.ProxyRate > 0 {
+		// A proxy bids up in increments and pays what it was pushed to
+		switch {
+		case config.MinIncrement <= 0:
+			return fmt.Errorf("proxy bids step by the minimum increment, so they need -min-increment")
+		case config.Pricing != models.PricingFirstPrice:
+			return fmt.Errorf("a proxy pays the price it was pushed to, so proxy bids take %q pricing only, got %q", models.PricingFirstPrice, config.Pricing)
+		case config.MaxStoredBids > 0:
+			return fmt.Errorf("a proxy's price depends on every rival bid, so proxy bids need every bid stored")
+		case config.BurstSize > 0:
+			return fmt.Errorf("bid bursts do not place proxy bids")
+		}
+	}
+	if config.CommonValueNoise < 0 || config.CommonValueNoise >= 1 {
+		return fmt.Errorf("common value noise must be within [0, 1), got %v", config.CommonValueNoise)
+	}
+	if config.CommonValueNoise > 0 {
+		// Only a single item bid on directly has one true value to estimate
+		switch {
+		case config.Units > 1 || config.BundleItems > 1:
+			return fmt.Errorf("common-value auctions sell a single item")
+		case config.DutchStart > 0:
+			return fmt.Errorf("Dutch clock bidders do not estimate a common value")
+		case config.NumGroups > 0:
+			return fmt.Errorf("affiliation groups correlate private values; common-value bidders share the true value instead")
+		}
+	}
+	if config.BundleItems < 0 || config.BundleItems > models.MaxBundleItems {
+		return fmt.Errorf("bundle items must be within [0, %d], got %d", models.MaxBundleItems, config.BundleItems)
+	}
+	if config.BundleItems > 1 {
+		// Bundles are paid as bid and cleared on every bidder's bundle set
+		switch {
+		case config.Units > 1:
+			return fmt.Errorf("combinatorial auctions sell bundles of items, not %d identical units", config.Units)
+		case config.Pricing != models.PricingFirstPrice:
+			return fmt.Errorf("combinatorial auctions support %q pricing only, got %q", models.PricingFirstPrice, config.Pricing)
+		case config.RevealWindow > 0:
+			return fmt.Errorf("commit-reveal auctions do not take bundle bids")
+		case config.MaxStoredBids > 0:
+			return fmt.Errorf("combinatorial auctions clear on every bundle bid, so they need every bid stored")
+		}
+	}
+	if config.DurationUnit.Size() == 0 {
+		return fmt.Errorf("unknown duration unit %q (want %q, %q, %q or %q)", config.DurationUnit,
+			models.UnitNanoseconds, models.UnitMicroseconds, models.UnitMilliseconds, models.UnitSeconds)
+	}
+	if fee := config.Fee; fee != nil {
+		if fee.Percent < 0 || fee.Percent > 100 {
+			return fmt.Errorf("fee percent must be within [0, 100], got %v", fee.Percent)
+		}
+		if fee.Flat < 0 || fee.Min < 0 {
+			return fmt.Errorf("flat and minimum fees must not be negative, got %v and %v", fee.Flat, fee.Min)
+		}
+	}
+	if _, err := auction.AcceptancePolicy(config.Acceptance); err != nil {
+		return err
+	}
+	if config.AuctionTimeout < 0 {
+		return fmt.Errorf("auction timeout must not be negative, got %v", config.AuctionTimeout)
+	}
+	if config.AuctionTimeoutMax != 0 && config.AuctionTimeoutMax < config.AuctionTimeout {
+		return fmt.Errorf("auction timeout range [%v, %v] must be ordered", config.AuctionTimeout, config.AuctionTimeoutMax)
+	}
+	if config.MinDuration < 0 || config.MinDuration > config.AuctionTimeout {
+		return fmt.Errorf("min duration must be within [0, %v], got %v", config.AuctionTimeout, config.MinDuration)
+	}
+	if config.HammerGrace < 0 || config.HammerGrace > config.AuctionTimeout {
+		return fmt.Errorf("hammer grace must be within [0, %v], got %v", config.AuctionTimeout, config.HammerGrace)
+	}
+	if config.HammerMaxExtensions < 0 {
+		return fmt.Errorf("hammer max extensions must not be negative, got %d", config.HammerMaxExtensions)
+	}
+	if config.CoalesceWindow < 0 {
+		return fmt.Errorf("coalesce window must not be negative, got %v", config.CoalesceWindow)
+	}
+	if config.CoalesceKeep != models.CoalesceLatest && config.CoalesceKeep != models.CoalesceHighest {
+		return fmt.Errorf("unknown coalesce keep rule %q (want %q or %q)", config.CoalesceKeep, models.CoalesceLatest, models.CoalesceHighest)
+	}
+	if config.MaxDuration < 0 {
+		return fmt.Errorf("max duration must not be negative, got %v", config.MaxDuration)
+	}
+	if config.MinBidAmount < 0 || config.MaxBidAmount < 0 {
+		return fmt.Errorf("bid amount bounds must not be negative, got [%v, %v]", config.MinBidAmount, config.MaxBidAmount)
+	}
+	if config.MaxBidAmount > 0 && config.MinBidAmount > config.MaxBidAmount {
+		return fmt.Errorf("min bid amount %v exceeds max bid amount %v", config.MinBidAmount, config.MaxBidAmount)
+	}
+	if config.RevealWindow < 0 {
+		return fmt.Errorf("reveal window must not be negative, got %v", config.RevealWindow)
+	}
+	if config.RevealWindow > 0 {
+		// Sealed bids have no open deadline to extend and no serial counterpart
+		switch {
+		case config.Serial:
+			return fmt.Errorf("commit-reveal auctions cannot run in serial mode")
+		case config.HammerGrace > 0:
+			return fmt.Errorf("hammer grace does not apply to commit-reveal auctions")
+		case config.MinDuration > 0:
+			return fmt.Errorf("min duration does not apply to commit-reveal auctions")
+		case config.Units > 1:
+			return fmt.Errorf("commit-reveal auctions sell a single unit, got %d units", config.Units)
+		}
+	}
+	if config.DutchStart < 0 || config.DutchFloor < 0 || config.DutchTicks < 0 {
+		return fmt.Errorf("Dutch start, floor and ticks must not be negative, got %v, %v and %d", config.DutchStart, config.DutchFloor, config.DutchTicks)
+	}
+	if config.DutchStart > 0 {
+		// The clock sells one item at an announced price to whoever stops it
+		// first, so nothing that bids against other bids applies
+		switch {
+		case config.DutchFloor >= config.DutchStart:
+			return fmt.Errorf("Dutch floor %v must be below the start price %v", config.DutchFloor, config.DutchStart)
+		case config.DutchTicks < 2:
+			return fmt.Errorf("a Dutch clock needs at least 2 ticks, got %d", config.DutchTicks)
+		case config.Serial:
+			return fmt.Errorf("Dutch auctions cannot run in serial mode")
+		case config.RevealWindow > 0:
+			return fmt.Errorf("an auction cannot be both Dutch and commit-reveal")
+		case config.Pricing != models.PricingFirstPrice:
+			return fmt.Errorf("Dutch auctions sell at the accepted price, so they take %q pricing only, got %q", models.PricingFirstPrice, config.Pricing)
+		case config.Units > 1 || config.BundleItems > 1:
+			return fmt.Errorf("Dutch auctions sell a single item")
+		case config.ReserveMax > 0:
+			return fmt.Errorf("the Dutch floor is the reserve, so Dutch auctions take no -reserve")
+		case config.MinIncrement > 0:
+			return fmt.Errorf("a minimum increment does not apply to Dutch auctions")
+		case config.HammerGrace > 0 || config.MinDuration > 0:
+			return fmt.Errorf("hammer grace and min duration do not apply to Dutch auctions")
+		case config.Arrivals || config.BurstSize > 0:
+			return fmt.Errorf("staggered arrivals and bid bursts do not apply to Dutch auctions")
+		case config.MaxActiveAuctions > 0 || config.WinCooldown > 0:
+			return fmt.Errorf("attention limits do not apply to Dutch auctions")
+		case config.RecordSequence || config.ReplaySequence != nil:
+			return fmt.Errorf("bid sequences cannot be recorded or replayed for Dutch auctions")
+		}
+	}
+	if config.RecordSequence || config.ReplaySequence != nil {
+		// Only the concurrent collector has an arrival order worth recording
+		switch {
+		case config.Serial:
+			return fmt.Errorf("bid sequences cannot be recorded or replayed in serial mode, which is deterministic already")
+		case config.RevealWindow > 0:
+			return fmt.Errorf("bid sequences cannot be recorded or replayed for commit-reveal auctions")
+		}
+	}
+	if config.MaxStoredBids < 0 {
+		return fmt.Errorf("max stored bids must not be negative, got %d", config.MaxStoredBids)
+	}
+	if config.MaxStoredBids > 0 {
+		// Each of these needs bids other than the top ones to settle correctly
+		switch {
+		case config.Pricing == models.PricingAllPay:
+			return fmt.Errorf("%q pricing charges every bidder, so it needs every bid stored", models.PricingAllPay)
+		case config.Pricing == models.PricingSecondPrice:
+			return fmt.Errorf("%q pricing charges the runner-up's bid, so it needs every bid stored", models.PricingSecondPrice)
+		case config.Units > 1:
+			return fmt.Errorf("multi-unit auctions clear on every demand schedule, so they need every bid stored")
+		case config.CoalesceWindow > 0:
+			return fmt.Errorf("coalescing bursts needs every bid stored")
+		}
+	}
+	if config.DepartureRate < 0 || config.DepartureRate > 1 {
+		return fmt.Errorf("departure rate must be within [0, 1], got %v", config.DepartureRate)
+	}
+	if config.DepartureRate > 0 && !config.Arrivals {
+		return fmt.Errorf("a departure rate needs staggered arrivals")
+	}
+	if config.Arrivals && config.RevealWindow > 0 {
+		return fmt.Errorf("staggered arrivals do not apply to commit-reveal auctions")
+	}
+	if config.AggressivenessMin < 0 || config.AggressivenessMin > config.AggressivenessMax {
+		return fmt.Errorf("aggressiveness range [%v, %v] must be non-negative and ordered", config.AggressivenessMin, config.AggressivenessMax)
+	}
+	if config.AggressivenessMax > 0 && config.AggressivenessMin == 0 {
+		return fmt.Errorf("aggressiveness must be positive, got a minimum of 0")
+	}
+	if len(config.StrategyMix) > 0 && len(config.Bidders) > 0 {
+		return fmt.Errorf("a strategy mix applies to generated bidders; set strategies in the bidders file instead")
+	}
+	for i, s := range config.StrategyMix {
+		if _, err := bidder.ParseStrategy(s.Strategy); err != nil {
+			return fmt.Errorf("strategy mix: %w", err)
+		}
+		if s.Share <= 0 {
+			return fmt.Errorf("strategy mix: %s share must be positive, got %v", s.Strategy, s.Share)
+		}
+		if slices.ContainsFunc(config.StrategyMix[:i], func(prev models.StrategyShare) bool { return prev.Strategy == s.Strategy }) {
+			return fmt.Errorf("strategy mix lists %s more than once", s.Strategy)
+		}
+	}
+	visibility := config.VisibleMin != 0 || config.VisibleMax != 0
+	if visibility && (config.VisibleMin < 1 || config.VisibleMin > config.VisibleMax || config.VisibleMax > 20) {
+		return fmt.Errorf("visible attribute range [%d, %d] must be ordered within [1, 20]", config.VisibleMin, config.VisibleMax)
+	}
+	if config.BurstSize < 0 || config.BurstCount < 0 {
+		return fmt.Errorf("burst size and count must not be negative, got %d and %d", config.BurstSize, config.BurstCount)
+	}
+	if config.BurstSize > 0 {
+		// Bursts load the concurrent collector; the other modes bypass it
+		switch {
+		case config.Serial:
+			return fmt.Errorf("bid bursts cannot run in serial mode")
+		case config.RevealWindow > 0:
+			return fmt.Errorf("bid bursts do not apply to commit-reveal auctions")
+		case config.ReplaySequence != nil:
+			return fmt.Errorf("bid bursts cannot be combined with a replayed bid sequence")
+		}
+	}
+	if config.MaxActiveAuctions < 0 || config.WinCooldown < 0 {
+		return fmt.Errorf("max active auctions and win cooldown must not be negative, got %d and %v", config.MaxActiveAuctions, config.WinCooldown)
+	}
+	if config.MaxActiveAuctions > 0 || config.WinCooldown > 0 {
+		// Attention is spent across overlapping auctions on the shared clock;
+		// serial mode simulates each auction's clock on its own
+		switch {
+		case config.Serial:
+			return fmt.Errorf("attention limits cannot apply in serial mode, which runs one auction at a time")
+		case config.RevealWindow > 0:
+			return fmt.Errorf("attention limits do not apply to commit-reveal auctions")
+		}
+	}
+	if config.MaxTotalBids < 0 {
+		return fmt.Errorf("max total bids must not be negative, got %d", config.MaxTotalBids)
+	}
+	if config.MaxTotalBids > 0 && config.RevealWindow > 0 {
+		return fmt.Errorf("a bid limit does not apply to commit-reveal auctions")
+	}
+	if config.Resources.MaxMemoryMB < 0 {
+		return fmt.Errorf("memory limit must not be negative, got %d MB", config.Resources.MaxMemoryMB)
+	}
+	if config.Resources.CPUQuota < 0 {
+		return fmt.Errorf("CPU quota must not be negative, got %v", config.Resources.CPUQuota)
+	}
+	if config.Resources.CPUQuota > float64(config.Resources.MaxCPUs) {
+		return fmt.Errorf("CPU quota %v exceeds the %d CPUs allowed", config.Resources.CPUQuota, config.Resources.MaxCPUs)
+	}
+	if config.BidderTimeout < 0 {
+		return fmt.Errorf("bidder timeout must not be negative, got %v", config.BidderTimeout)
+	}
+	if config.WatchdogMargin < 0 {
+		return fmt.Errorf("watchdog margin must not be negative, got %v", config.WatchdogMargin)
+	}
+	if config.ResultBuffer < 0 {
+		return fmt.Errorf("result buffer must not be negative, got %d", config.ResultBuffer)
+	}
+	switch config.Backpressure {
+	case models.BackpressureBlock, models.BackpressureDropOldest, models.BackpressureError:
+	default:
+		return fmt.Errorf("unknown backpressure strategy %q (want %q, %q or %q)", config.Backpressure,
+			models.BackpressureBlock, models.BackpressureDropOldest, models.BackpressureError)
+	}
+	if config.MaxBidGoroutines < 0 {
+		return fmt.Errorf("max bid goroutines must not be negative, got %d", config.MaxBidGoroutines)
+	}
+	if config.MaxConcurrentAuctions < 0 {
+		return fmt.Errorf("max concurrent auctions must not be negative, got %d", config.MaxConcurrentAuctions)
+	}
+	templates, err := config.AttributeMode.Templates()
+	if err != nil {
+		return err
+	}
+	if templates > 0 && config.FeedbackStrength > 0 {
+		return fmt.Errorf("feedback mode shifts each auction's attributes, so it needs %q attributes", models.AttributesRandom)
+	}
+	if config.FeedbackStrength < 0 {
+		return fmt.Errorf("feedback strength must not be negative, got %v", config.FeedbackStrength)
+	}
+	if config.NumGroups < 0 {
+		return fmt.Errorf("number of groups must not be negative, got %d", config.NumGroups)
+	}
+	return nil
+}
+
+// autoSerial reports whether a run should switch to serial mode on its own:
+// a single auction with at most SerialMaxBidders bidders, taking no external
+// bids and neither recording nor replaying a bid sequence, whose options all
+// hold in serial mode
+func autoSerial(config models.SimConfig) bool {
+	if config.Serial || config.NumAuctions != 1 || config.NumBidders > SerialMaxBidders ||
+		config.ExternalBids || config.RecordSequence || config.ReplaySequence != nil {
+		return false
+	}
+	config.Serial = true
+	return validate(config) == nil
+}
+
+// New creates a simulation for the given configuration. Unset fields take their
+// defaults. A CPU count above the number of available cores is clamped with a
+// warning; a non-positive one is an error.
+func New(config models.SimConfig) (*Simulation, error) {
+	config = withDefaults(config)
+	if autoSerial(config) {
+		config.Serial = true
+		log.Printf("Running in serial mode: a single auction with %d bidders needs no concurrency", config.NumBidders)
+	}
+	if err := validate(config); err != nil {
+		return nil, err
+	}
+
+	// A fractional quota runs on enough whole CPUs to cover it
+	cpus := config.Resources.MaxCPUs
+	if config.Resources.CPUQuota > 0 {
+		cpus = min(cpus, int(math.Ceil(config.Resources.CPUQuota)))
+	}
+	effectiveCPUs, clamped, err := resource.EffectiveCPUs(cpus)
+	if err != nil {
+		return nil, err
+	}
+	if clamped {
+		log.Printf("Warning: requested %d CPUs but only %d are available; using %d",
+			cpus, effectiveCPUs, effectiveCPUs)
+	}
+
+	// Configure resource constraints before any goroutines are started
+	runtime.GOMAXPROCS(effectiveCPUs)
+
+	// Resolve the generator once; the manager creates every stream of this
+	// simulation from it, so other simulations cannot change it
+	source := rng.Factory(config.RNGSource)
+	if source == nil {
+		source, _ = rng.Source(config.RNG) // Validated above
+	}
+
+	return &Simulation{
+		config: config,
+		mgr:    manager.NewManager(config, source),
+	}, nil
+}
+
+// CancelAuction cancels a single in-progress auction by ID
+func (s *Simulation) CancelAuction(id int) error {
+	return s.mgr.CancelAuction(id)
+}
+
+// Faults returns the simulation's fault injector, or nil if injection is disabled
+func (s *Simulation) Faults() *faults.Injector {
+	return s.mgr.Faults()
+}
+
+// OnAuctionComplete registers fn to be called as each auction finishes; see
+// manager.Manager.OnAuctionComplete
+func (s *Simulation) OnAuctionComplete(fn func(*models.Auction)) {
+	s.mgr.OnAuctionComplete(fn)
+}
+
+// OnResourceSample registers fn to be called with each resource monitor
+// sample as it is taken, from the monitor's goroutine; it must be called
+// before Run, and fn must not block
+func (s *Simulation) OnResourceSample(fn func(models.ResourceSample)) {
+	s.onSample = fn
+}
+
+// ResultSink receives each auction as it completes, alongside the file output
+// written at the end of the run. Send is called from the collector, so a sink
+// that cannot keep up slows collection down.
+type ResultSink interface {
+	Send(auction *models.Auction)
+}
+
+// AddSink streams every finished auction to sink; it must be called before Run
+func (s *Simulation) AddSink(sink ResultSink) {
+	s.mgr.OnAuctionComplete(sink.Send)
+}
+
+// SubmitBid feeds an externally produced bid into a running auction
+func (s *Simulation) SubmitBid(auctionID int, bid models.Bid) error {
+	return s.mgr.SubmitBid(auctionID, bid)
+}
+
+// Snapshot returns a consistent copy of a running auction's state
+func (s *Simulation) Snapshot(auctionID int) (models.AuctionView, error) {
+	return s.mgr.Snapshot(auctionID)
+}
+
+// CurrentLeader returns the provisional highest bid of a running auction
+func (s *Simulation) CurrentLeader(auctionID int) (*models.Bid, error) {
+	return s.mgr.CurrentLeader(auctionID)
+}
+
+var (
+	// errMaxDuration is the cancellation cause when a run reaches MaxDuration
+	errMaxDuration = errors.New("maximum run duration reached")
+	// errMaxTotalBids is the cancellation cause when a run admits MaxTotalBids bids
+	errMaxTotalBids = errors.New("maximum total bids reached")
+)
+
+// Run executes all auctions while monitoring resource usage. Cancelling ctx,
+// reaching MaxDuration or admitting MaxTotalBids bids finalizes in-flight
+// auctions early; the result's Status records which of these ended the run.
+func (s *Simulation) Run(ctx context.Context) (*Result, error) {
+	if s.config.MaxDuration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, s.config.MaxDuration, errMaxDuration)
+		defer cancel()
+	}
+	if s.config.MaxTotalBids > 0 {
+		var cancel context.CancelCauseFunc
+		ctx, cancel = context.WithCancelCause(ctx)
+		defer cancel(nil)
+		s.mgr.OnBidLimit(func() { cancel(errMaxTotalBids) })
+	}
+
+	// Create resource monitor
+	monitor := resource.NewMonitor()
+	if limit := s.config.Resources.MaxMemoryMB; limit > 0 {
+		monitor.SetMemoryLimitCallback(limit, s.mgr.OverMemoryLimit)
+	}
+	if s.onSample != nil {
+		monitor.SetSampleCallback(s.onSample)
+	}
+	monitor.Start(resource.SampleInterval)
+
+	auctions, firstStart, lastEnd, err := s.mgr.Run(ctx)
+
+	// Stop monitoring
+	monitor.Stop()
+	if err != nil {
+		return nil, err
+	}
+
+	// A run that produced nothing says so, however it ended
+	status := runStatus(s.mgr.StopCause(), s.config)
+	if len(auctions) == 0 {
+		status.Detail = strings.TrimPrefix(status.Detail+"; "+noAuctionsDetail, "; ")
+	}
+
+	return &Result{
+		Status:     status,
+		Config:     s.config,
+		Auctions:   auctions,
+		FirstStart: firstStart,
+		LastEnd:    lastEnd,
+		ResourceProfile: models.ResourceProfile{
+			RequestedCPUs: s.config.Resources.MaxCPUs,
+			MaxCPUs:       monitor.GetMaxCPUs(),
+			PeakMemoryMB:  monitor.GetPeakMemoryMB(),
+			AvgGoroutines: monitor.GetAvgGoroutines(),
+
+			AvgCPUPercent:  monitor.GetAvgCPUPercent(),
+			PeakCPUPercent: monitor.GetPeakCPUPercent(),
+
+			PeakBidGoroutines:  s.mgr.PeakBidGoroutines(),
+			PeakActiveAuctions: s.mgr.PeakActiveAuctions(),
+
+			MemoryPercentilesMB:  monitor.GetMemoryPercentilesMB(),
+			GoroutinePercentiles: monitor.GetGoroutinePercentiles(),
+
+			CPUQuota:    s.cpuQuota(monitor),
+			MemoryLimit: s.mgr.MemoryLimit(),
+		},
+		Shutdown: models.ShutdownReport{
+			DrainTimeoutMs:       s.config.DrainTimeout.Milliseconds(),
+			PendingBidGoroutines: s.mgr.PendingBidGoroutines(),
+		},
+		FailedAuctions: s.mgr.FailedAuctions(),
+		LateBids:       s.mgr.LateBids(),
+		Participation:  s.mgr.Participation(),
+		Bidders:        s.mgr.BidderProfiles(),
+		Samples:        monitor.GetSamples(),
+		SamplesStart:   monitor.StartTime(),
+		Sequence:       s.mgr.Sequence(),
+		ClampedBids:    s.mgr.ClampedBids(),
+		SlowBidders:    s.mgr.SlowBidders(),
+		Attention:      s.mgr.Attention(),
+		Burst:          s.mgr.Burst(),
+		BidLimit:       s.mgr.BidLimit(),
+		Backpressure:   s.mgr.Backpressure(),
+		Ordering:       s.mgr.Ordering(),
+		StuckAuctions:  s.mgr.StuckAuctions(),
+	}, nil
+}
+
+// cpuQuota compares the configured CPU quota with what the monitor measured,
+// or returns nil if no quota is configured
+func (s *Simulation) cpuQuota(monitor *resource.Monitor) *models.CPUQuotaReport {
+	if s.config.Resources.CPUQuota <= 0 {
+		return nil
+	}
+	return &models.CPUQuotaReport{
+		Quota:         s.config.Resources.CPUQuota,
+		AchievedCores: monitor.GetAvgCPUCores(),
+		ThrottledMs:   float64(s.mgr.Throttled()) / float64(time.Millisecond),
+	}
+}
+
+// noAuctionsDetail is added to the run status when no auction completed
+const noAuctionsDetail = "no auctions completed"
+
+// runStatus classifies how a run ended from why its context ended, if it did
+// before the last auction finished
+func runStatus(cause error, config models.SimConfig) models.RunStatus {
+	switch {
+	case cause == nil:
+		return models.RunStatus{Outcome: models.RunCompleted}
+	case errors.Is(cause, errMaxDuration):
+		return models.RunStatus{
+			Outcome: models.RunTimedOut,
+			Detail:  fmt.Sprintf("stopped after max duration of %v", config.MaxDuration),
+		}
+	case errors.Is(cause, errMaxTotalBids):
+		return models.RunStatus{
+			Outcome: models.RunBidLimit,
+			Detail:  fmt.Sprintf("stopped after %d bids", config.MaxTotalBids),
+		}
+	case errors.Is(cause, context.DeadlineExceeded):
+		return models.RunStatus{Outcome: models.RunTimedOut, Detail: "caller's deadline exceeded"}
+	}
+	return models.RunStatus{Outcome: models.RunInterrupted, Detail: cause.Error()}
+}
+
+// RunSimulation creates and runs a simulation in one call
+func RunSimulation(ctx context.Context, config models.SimConfig) (*Result, error) {
+	sim, err := New(config)
+	if err != nil {
+		return nil, err
+	}
+	return sim.Run(ctx)
+}
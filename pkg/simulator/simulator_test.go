@@ -0,0 +1,444 @@
+package simulator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"auction-simulator/internal/manager"
+	"auction-simulator/pkg/models"
+)
+
+// runWinners runs a simulation and returns each auction's winning bidder and
+// bid count, by auction ID
+func runWinners(t *testing.T, config models.SimConfig) map[int][2]int {
+	t.Helper()
+	sim, err := New(config)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return simWinners(t, sim)
+}
+
+// simWinners runs a simulation created earlier and returns what runWinners does
+func simWinners(t *testing.T, sim *Simulation) map[int][2]int {
+	t.Helper()
+	result, err := sim.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	winners := make(map[int][2]int, len(result.Auctions))
+	for _, a := range result.Auctions {
+		winner := 0
+		if a.Winner != nil {
+			winner = a.Winner.BidderID
+		}
+		winners[a.ID] = [2]int{winner, a.TotalBids}
+	}
+	return winners
+}
+
+// TestSameSeedSameWinners runs the same concurrent simulation twice; every
+// bidder draws from its own per-auction stream, so scheduling must not change
+// any winner. Run it with -race to check the bid goroutines as well.
+func TestSameSeedSameWinners(t *testing.T) {
+	config := models.SimConfig{
+		Seed:           7,
+		NumAuctions:    10,
+		NumBidders:     30,
+		AuctionTimeout: time.Second, // Every bid is ready within 500ms
+	}
+
+	first := runWinners(t, config)
+	second := runWinners(t, config)
+	if len(first) != config.NumAuctions {
+		t.Fatalf("got %d auctions, want %d", len(first), config.NumAuctions)
+	}
+	for id, want := range first {
+		if got := second[id]; got != want {
+			t.Errorf("auction %d: second run has winner %d with %d bids, first had winner %d with %d bids",
+				id, got[0], got[1], want[0], want[1])
+		}
+	}
+}
+
+// TestGeneratorDeterminism runs the same serial auction twice with each
+// built-in generator and with a caller-supplied source; each must reproduce
+// its winner and bid count
+func TestGeneratorDeterminism(t *testing.T) {
+	tests := []struct {
+		name   string
+		config models.SimConfig
+	}{
+		{"math-rand", models.SimConfig{RNG: models.GeneratorMathRand}},
+		{"pcg", models.SimConfig{RNG: models.GeneratorPCG}},
+		{"custom", models.SimConfig{RNGSource: func(seed int64) rand.Source { return rand.NewSource(^seed) }}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			config.Seed, config.NumAuctions, config.NumBidders, config.Serial = 3, 1, 50, true
+
+			first, second := runWinners(t, config), runWinners(t, config)
+			if first[1][1] == 0 {
+				t.Fatal("the auction drew no bids")
+			}
+			if first[1] != second[1] {
+				t.Errorf("second run has winner %d with %d bids, first had winner %d with %d bids",
+					second[1][0], second[1][1], first[1][0], first[1][1])
+			}
+		})
+	}
+}
+
+// TestGeneratorPerSimulation creates two simulations with different
+// generators before running either; each must draw from its own generator,
+// not from whichever was created last
+func TestGeneratorPerSimulation(t *testing.T) {
+	config := models.SimConfig{Seed: 3, NumAuctions: 1, NumBidders: 50, Serial: true}
+	pcgConfig, mathRandConfig := config, config
+	pcgConfig.RNG, mathRandConfig.RNG = models.GeneratorPCG, models.GeneratorMathRand
+	wantPCG, wantMathRand := runWinners(t, pcgConfig), runWinners(t, mathRandConfig)
+
+	pcg, err := New(pcgConfig)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	mathRand, err := New(mathRandConfig)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if got := simWinners(t, pcg); got[1] != wantPCG[1] {
+		t.Errorf("pcg run has winner %d with %d bids, want winner %d with %d bids",
+			got[1][0], got[1][1], wantPCG[1][0], wantPCG[1][1])
+	}
+	if got := simWinners(t, mathRand); got[1] != wantMathRand[1] {
+		t.Errorf("math-rand run has winner %d with %d bids, want winner %d with %d bids",
+			got[1][0], got[1][1], wantMathRand[1][0], wantMathRand[1][1])
+	}
+}
+
+func TestAutoSerial(t *testing.T) {
+	tests := []struct {
+		name   string
+		config models.SimConfig
+		want   bool
+	}{
+		{"single small auction", models.SimConfig{NumAuctions: 1, NumBidders: 5}, true},
+		{"two auctions", models.SimConfig{NumAuctions: 2, NumBidders: 5}, false},
+		{"too many bidders", models.SimConfig{NumAuctions: 1, NumBidders: SerialMaxBidders + 1}, false},
+		{"control server", models.SimConfig{NumAuctions: 1, NumBidders: 5, ExternalBids: true}, false},
+		{"recording", models.SimConfig{NumAuctions: 1, NumBidders: 5, RecordSequence: true}, false},
+		{"attention limit", models.SimConfig{NumAuctions: 1, NumBidders: 5, MaxActiveAuctions: 1}, false},
+		{"win cooldown", models.SimConfig{NumAuctions: 1, NumBidders: 5, WinCooldown: time.Second}, false},
+		{"commit-reveal", models.SimConfig{NumAuctions: 1, NumBidders: 5, RevealWindow: time.Second}, false},
+		{"bursts", models.SimConfig{NumAuctions: 1, NumBidders: 5, BurstSize: 2, BurstCount: 1}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim, err := New(tt.config)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			if sim.config.Serial != tt.want {
+				t.Errorf("serial = %v, want %v", sim.config.Serial, tt.want)
+			}
+		})
+	}
+}
+
+// TestCancelMidRun cancels a run while its auctions are open; they must close
+// early with the bids collected so far and the run must be marked interrupted
+func TestCancelMidRun(t *testing.T) {
+	sim, err := New(models.SimConfig{Seed: 1, NumAuctions: 5, NumBidders: 20, AuctionTimeout: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	ctx, cancel := context.WithCancelCause(context.Background())
+	time.AfterFunc(600*time.Millisecond, func() { cancel(errors.New("test interrupt")) })
+
+	start := time.Now()
+	result, err := sim.Run(ctx)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("run took %v after cancellation, want well under the 10s timeout", elapsed)
+	}
+	if result.Status.Outcome != models.RunInterrupted {
+		t.Errorf("outcome = %q, want %q", result.Status.Outcome, models.RunInterrupted)
+	}
+	if len(result.Auctions) != 5 {
+		t.Fatalf("got %d auctions, want all 5 closed early", len(result.Auctions))
+	}
+	bids := 0
+	for _, a := range result.Auctions {
+		bids += a.TotalBids
+	}
+	if bids == 0 {
+		t.Error("no bids were kept from before the cancellation")
+	}
+}
+
+// TestRunStatus ends a run each way it can end and checks the outcome and
+// detail its result reports
+func TestRunStatus(t *testing.T) {
+	base := models.SimConfig{Seed: 1, NumAuctions: 3, NumBidders: 10, AuctionTimeout: 10 * time.Second}
+	tests := []struct {
+		name    string
+		config  func(models.SimConfig) models.SimConfig
+		ctx     func() (context.Context, context.CancelFunc)
+		outcome models.RunOutcome
+		detail  string
+	}{
+		{
+			name: "completed",
+			config: func(c models.SimConfig) models.SimConfig {
+				c.AuctionTimeout = 600 * time.Millisecond
+				return c
+			},
+			outcome: models.RunCompleted,
+		},
+		{
+			name: "max duration",
+			config: func(c models.SimConfig) models.SimConfig {
+				c.MaxDuration = 300 * time.Millisecond
+				return c
+			},
+			outcome: models.RunTimedOut,
+			detail:  "stopped after max duration of 300ms",
+		},
+		{
+			name: "bid limit",
+			config: func(c models.SimConfig) models.SimConfig {
+				c.MaxTotalBids = 5
+				return c
+			},
+			outcome: models.RunBidLimit,
+			detail:  "stopped after 5 bids",
+		},
+		{
+			name: "caller deadline",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 300*time.Millisecond)
+			},
+			outcome: models.RunTimedOut,
+			detail:  "caller's deadline exceeded",
+		},
+		{
+			name: "interrupted",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancelCause(context.Background())
+				time.AfterFunc(300*time.Millisecond, func() { cancel(errors.New("received interrupt")) })
+				return ctx, func() { cancel(nil) }
+			},
+			outcome: models.RunInterrupted,
+			detail:  "received interrupt",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := base
+			if tt.config != nil {
+				config = tt.config(config)
+			}
+			ctx, cancel := context.Background(), context.CancelFunc(func() {})
+			if tt.ctx != nil {
+				ctx, cancel = tt.ctx()
+			}
+			defer cancel()
+
+			result, err := RunSimulation(ctx, config)
+			if err != nil {
+				t.Fatalf("RunSimulation: %v", err)
+			}
+			if result.Status.Outcome != tt.outcome || result.Status.Detail != tt.detail {
+				t.Errorf("status = %q (%q), want %q (%q)", result.Status.Outcome, result.Status.Detail, tt.outcome, tt.detail)
+			}
+		})
+	}
+}
+
+// slowSink takes delay to receive each auction
+type slowSink time.Duration
+
+func (d slowSink) Send(*models.Auction) { time.Sleep(time.Duration(d)) }
+
+// TestLimitAfterLastAuction lets the max duration pass while results are
+// still being delivered, after every auction has finished. Nothing was cut
+// short, so the run must be reported completed.
+func TestLimitAfterLastAuction(t *testing.T) {
+	for _, serial := range []bool{false, true} {
+		config := models.SimConfig{
+			Seed:           1,
+			NumAuctions:    3,
+			NumBidders:     5,
+			AuctionTimeout: 100 * time.Millisecond,
+			MaxDuration:    300 * time.Millisecond,
+			Serial:         serial,
+		}
+		if serial {
+			config.NumAuctions = 1
+		}
+		sim, err := New(config)
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		sim.AddSink(slowSink(400 * time.Millisecond))
+		result, err := sim.Run(context.Background())
+		if err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		if result.Status.Outcome != models.RunCompleted || len(result.Auctions) != config.NumAuctions {
+			t.Errorf("serial %v: status %q (%q) with %d auctions, want %q with %d",
+				serial, result.Status.Outcome, result.Status.Detail, len(result.Auctions), models.RunCompleted, config.NumAuctions)
+		}
+	}
+}
+
+// TestDeadlineAware runs auctions shorter than most processing delays. Naive
+// bidders miss the deadline with many bids; deadline-aware bidders must get
+// every bid in.
+func TestDeadlineAware(t *testing.T) {
+	config := models.SimConfig{
+		Seed:           1,
+		NumAuctions:    5,
+		NumBidders:     40,
+		AuctionTimeout: 150 * time.Millisecond,
+		DrainTimeout:   time.Second, // Late bids are counted once their goroutines finish
+	}
+
+	run := func(aware bool) (bids, late int) {
+		config.DeadlineAware = aware
+		result, err := RunSimulation(context.Background(), config)
+		if err != nil {
+			t.Fatalf("RunSimulation: %v", err)
+		}
+		for _, a := range result.Auctions {
+			bids += a.TotalBids
+		}
+		return bids, result.LateBids
+	}
+
+	naiveBids, naiveLate := run(false)
+	awareBids, awareLate := run(true)
+	if naiveLate == 0 {
+		t.Fatalf("naive bidders missed no deadlines; the timeout is too long to compare")
+	}
+	if awareLate != 0 {
+		t.Errorf("deadline-aware bidders were late with %d bids, want 0", awareLate)
+	}
+	if awareBids <= naiveBids {
+		t.Errorf("deadline-aware bidders placed %d bids, naive ones %d; want more", awareBids, naiveBids)
+	}
+}
+
+// TestCancelledBeforeStart runs a simulation whose context is already
+// cancelled, so no auction completes. The status must say so, and the
+// summary must leave out the start and end times instead of writing zero
+// times and a nonsensical execution time.
+func TestCancelledBeforeStart(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(errors.New("test interrupt"))
+
+	result, err := RunSimulation(ctx, models.SimConfig{Seed: 1, NumAuctions: 5, NumBidders: 20})
+	if err != nil {
+		t.Fatalf("RunSimulation: %v", err)
+	}
+	if len(result.Auctions) != 0 {
+		t.Fatalf("got %d auctions, want none", len(result.Auctions))
+	}
+	want := models.RunStatus{Outcome: models.RunInterrupted, Detail: "test interrupt; " + noAuctionsDetail}
+	if result.Status != want {
+		t.Errorf("status = %+v, want %+v", result.Status, want)
+	}
+
+	dir := t.TempDir()
+	output := manager.NewOutputGenerator(dir)
+	if err := output.WriteSummary(result); err != nil {
+		t.Fatalf("WriteSummary: %v", err)
+	}
+	if err := output.WriteMarkdownReport(result); err != nil {
+		t.Fatalf("WriteMarkdownReport: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "execution_summary.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var summary map[string]any
+	if err := json.Unmarshal(data, &summary); err != nil {
+		t.Fatalf("parsing summary: %v", err)
+	}
+	for _, key := range []string{"first_auction_start", "last_auction_end"} {
+		if v, ok := summary[key]; ok {
+			t.Errorf("summary has %s %v, want it omitted", key, v)
+		}
+	}
+	if ms := summary["total_execution_time_ms"]; ms != 0.0 {
+		t.Errorf("total_execution_time_ms = %v, want 0", ms)
+	}
+	if n := summary["total_auctions"]; n != 0.0 {
+		t.Errorf("total_auctions = %v, want 0", n)
+	}
+
+	report, err := os.ReadFile(filepath.Join(dir, "report.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(report), "0001-01-01") {
+		t.Errorf("report has a zero time:\n%s", report)
+	}
+}
+
+// TestOrderedResults gives auctions different timeouts so they finish out of
+// ID order; with OrderedResults the completion hook and the result must still
+// see them in ascending ID order
+func TestOrderedResults(t *testing.T) {
+	sim, err := New(models.SimConfig{
+		Seed:              2,
+		NumAuctions:       8,
+		NumBidders:        5,
+		AuctionTimeout:    100 * time.Millisecond,
+		AuctionTimeoutMax: 600 * time.Millisecond,
+		OrderedResults:    true,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	var delivered []int
+	sim.OnAuctionComplete(func(a *models.Auction) { delivered = append(delivered, a.ID) })
+
+	result, err := sim.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	finished := slices.Clone(result.Auctions)
+	slices.SortFunc(finished, func(a, b *models.Auction) int { return a.EndTime.Compare(b.EndTime) })
+	if slices.IsSortedFunc(finished, func(a, b *models.Auction) int { return a.ID - b.ID }) {
+		t.Fatal("auctions finished in ID order; the test needs them out of order")
+	}
+	want := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	if !slices.Equal(delivered, want) {
+		t.Errorf("hook saw auctions %v, want %v", delivered, want)
+	}
+	var got []int
+	for _, a := range result.Auctions {
+		got = append(got, a.ID)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("result holds auctions %v, want %v", got, want)
+	}
+	if result.Ordering == nil || result.Ordering.PeakHeld == 0 {
+		t.Errorf("ordering report %+v, want results held back", result.Ordering)
+	}
+}
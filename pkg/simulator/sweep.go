@@ -0,0 +1,217 @@
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"auction-simulator/pkg/models"
+)
+
+// MaxSweepSeeds is the most seeds a seed range may cover
+const MaxSweepSeeds = 100000
+
+// ParseSeedRange parses a "start:end:step" specification into the list of seeds
+// it covers. The end seed is inclusive and step defaults to 1 when omitted.
+func ParseSeedRange(spec string) ([]int64, error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, fmt.Errorf("seed range %q must be start:end[:step]", spec)
+	}
+
+	values := []int64{0, 0, 1}
+	for i, part := range parts {
+		v, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("seed range %q: invalid number %q", spec, part)
+		}
+		values[i] = v
+	}
+
+	start, end, step := values[0], values[1], values[2]
+	if step <= 0 {
+		return nil, fmt.Errorf("seed range %q: step must be positive", spec)
+	}
+	if end < start {
+		return nil, fmt.Errorf("seed range %q: end must not be before start", spec)
+	}
+
+	// The span is computed unsigned so ranges reaching either end of int64
+	// cannot overflow
+	steps := (uint64(end) - uint64(start)) / uint64(step)
+	if steps >= MaxSweepSeeds {
+		return nil, fmt.Errorf("seed range %q covers more than the limit of %d seeds", spec, MaxSweepSeeds)
+	}
+	seeds := make([]int64, steps+1)
+	for i := range seeds {
+		seeds[i] = start + int64(i)*step
+	}
+	return seeds, nil
+}
+
+// ParseAggressiveness parses a "min:max" aggressiveness range, or a single
+// value that every bidder shares
+func ParseAggressiveness(spec string) (lo, hi float64, err error) {
+	first, second, isRange := strings.Cut(spec, ":")
+	if !isRange {
+		second = first
+	}
+	if lo, err = strconv.ParseFloat(strings.TrimSpace(first), 64); err != nil {
+		return 0, 0, fmt.Errorf("aggressiveness %q: invalid number %q", spec, first)
+	}
+	if hi, err = strconv.ParseFloat(strings.TrimSpace(second), 64); err != nil {
+		return 0, 0, fmt.Errorf("aggressiveness %q: invalid number %q", spec, second)
+	}
+	return lo, hi, nil
+}
+
+// ParseReserve parses a "min:max" range of reserve prices, or a single
+// reserve every auction shares
+func ParseReserve(spec string) (lo, hi float64, err error) {
+	first, second, isRange := strings.Cut(spec, ":")
+	if !isRange {
+		second = first
+	}
+	if lo, err = strconv.ParseFloat(strings.TrimSpace(first), 64); err != nil {
+		return 0, 0, fmt.Errorf("reserve %q: invalid amount %q", spec, first)
+	}
+	if hi, err = strconv.ParseFloat(strings.TrimSpace(second), 64); err != nil {
+		return 0, 0, fmt.Errorf("reserve %q: invalid amount %q", spec, second)
+	}
+	return lo, hi, nil
+}
+
+// ParseTimeout parses a "min:max" range of auction timeouts, or a single
+// timeout every auction shares, in which case max is zero
+func ParseTimeout(spec string) (lo, hi time.Duration, err error) {
+	first, second, isRange := strings.Cut(spec, ":")
+	if lo, err = time.ParseDuration(strings.TrimSpace(first)); err != nil {
+		return 0, 0, fmt.Errorf("timeout %q: invalid duration %q", spec, first)
+	}
+	if !isRange {
+		return lo, 0, nil
+	}
+	if hi, err = time.ParseDuration(strings.TrimSpace(second)); err != nil {
+		return 0, 0, fmt.Errorf("timeout %q: invalid duration %q", spec, second)
+	}
+	return lo, hi, nil
+}
+
+// ParseDutch parses a Dutch clock's "start:floor" prices
+func ParseDutch(spec string) (start, floor float64, err error) {
+	first, second, ok := strings.Cut(spec, ":")
+	if !ok {
+		return 0, 0, fmt.Errorf("dutch %q: want start:floor", spec)
+	}
+	if start, err = strconv.ParseFloat(strings.TrimSpace(first), 64); err != nil {
+		return 0, 0, fmt.Errorf("dutch %q: invalid price %q", spec, first)
+	}
+	if floor, err = strconv.ParseFloat(strings.TrimSpace(second), 64); err != nil {
+		return 0, 0, fmt.Errorf("dutch %q: invalid price %q", spec, second)
+	}
+	return start, floor, nil
+}
+
+// ParseStrategyMix parses a comma-separated "strategy:share" list, e.g.
+// default:2,aggressive:1. Shares are relative and need not sum to one.
+func ParseStrategyMix(spec string) ([]models.StrategyShare, error) {
+	var mix []models.StrategyShare
+	for _, part := range strings.Split(spec, ",") {
+		name, share, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("strategy mix %q: want strategy:share, got %q", spec, part)
+		}
+		s := models.StrategyShare{Strategy: strings.TrimSpace(name)}
+		var err error
+		if s.Share, err = strconv.ParseFloat(strings.TrimSpace(share), 64); err != nil {
+			return nil, fmt.Errorf("strategy mix %q: invalid share %q", spec, share)
+		}
+		mix = append(mix, s)
+	}
+	return mix, nil
+}
+
+// ParseVisibleAttributes parses a "min:max" range of visible attribute
+// counts, or a single count every bidder shares
+func ParseVisibleAttributes(spec string) (lo, hi int, err error) {
+	first, second, isRange := strings.Cut(spec, ":")
+	if !isRange {
+		second = first
+	}
+	if lo, err = strconv.Atoi(strings.TrimSpace(first)); err != nil {
+		return 0, 0, fmt.Errorf("visible attributes %q: invalid count %q", spec, first)
+	}
+	if hi, err = strconv.Atoi(strings.TrimSpace(second)); err != nil {
+		return 0, 0, fmt.Errorf("visible attributes %q: invalid count %q", spec, second)
+	}
+	return lo, hi, nil
+}
+
+// SweepSeeds runs the base configuration once per seed, varying only the seed,
+// and returns one row of key metrics per run. Once ctx is done it stops after
+// the interrupted run, returning the rows so far.
+func SweepSeeds(ctx context.Context, base models.SimConfig, seeds []int64) ([]models.SweepResult, error) {
+	rows := make([]models.SweepResult, 0, len(seeds))
+	for _, seed := range seeds {
+		config := base
+		config.Seed = seed
+
+		result, err := RunSimulation(ctx, config)
+		if err != nil {
+			return rows, fmt.Errorf("seed %d: %w", seed, err)
+		}
+		rows = append(rows, SummarizeRun(seed, result))
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return rows, nil
+}
+
+// SummarizeRun reduces a simulation result to the metrics compared across a sweep
+func SummarizeRun(seed int64, result *Result) models.SweepResult {
+	row := models.SweepResult{
+		Seed:            seed,
+		TotalAuctions:   len(result.Auctions),
+		ExecutionTimeMs: result.LastEnd.Sub(result.FirstStart).Milliseconds(),
+	}
+
+	wins := make(map[int]int)
+	noBids := 0
+	var revenueCents int64
+	for _, auction := range result.Auctions {
+		row.TotalBids += auction.TotalBids
+		if auction.TotalBids == 0 {
+			noBids++
+		}
+		row.TotalRevenue += auction.TotalPaid
+		revenueCents += auction.TotalPaidCents
+		if auction.Winner != nil {
+			wins[auction.Winner.BidderID]++
+		}
+	}
+
+	// Integer mode sums exact cents rather than accumulating float error
+	if result.Config.IntegerAmounts {
+		row.TotalRevenue = models.FromCents(revenueCents)
+	}
+
+	if row.TotalAuctions > 0 {
+		row.NoBidRate = float64(noBids) / float64(row.TotalAuctions)
+	}
+
+	// Win concentration is the Herfindahl index of each bidder's share of wins
+	totalWins := 0
+	for _, n := range wins {
+		totalWins += n
+	}
+	for _, n := range wins {
+		share := float64(n) / float64(totalWins)
+		row.WinConcentration += share * share
+	}
+	row.DistinctWinners = len(wins)
+
+	return row
+}
@@ -0,0 +1,41 @@
+package simulator
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestParseSeedRange(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []int64
+		wantErr bool
+	}{
+		{spec: "1:3", want: []int64{1, 2, 3}},
+		{spec: "0:10:4", want: []int64{0, 4, 8}},
+		{spec: "5:5", want: []int64{5}},
+		{spec: "9223372036854775800:9223372036854775807", want: []int64{
+			math.MaxInt64 - 7, math.MaxInt64 - 6, math.MaxInt64 - 5, math.MaxInt64 - 4,
+			math.MaxInt64 - 3, math.MaxInt64 - 2, math.MaxInt64 - 1, math.MaxInt64,
+		}},
+		{spec: "9223372036854775800:9223372036854775807:5", want: []int64{math.MaxInt64 - 7, math.MaxInt64 - 2}},
+		{spec: "-9223372036854775808:-9223372036854775807", want: []int64{math.MinInt64, math.MinInt64 + 1}},
+		{spec: "0:9223372036854775807", wantErr: true},
+		{spec: "-9223372036854775808:9223372036854775807", wantErr: true},
+		{spec: "3:1", wantErr: true},
+		{spec: "1:3:0", wantErr: true},
+		{spec: "1", wantErr: true},
+		{spec: "a:3", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSeedRange(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSeedRange(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ParseSeedRange(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,345 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: proto/auction.proto
+
+// Streaming interface for consumers that want auction results as they finish.
+// The Go messages and service stubs in proto/auctionv1 are generated from this
+// file. After changing it, regenerate them from the repository root with
+//
+//   protoc --go_out=. --go_opt=module=auction-simulator \
+//     --go-grpc_out=. --go-grpc_opt=module=auction-simulator proto/auction.proto
+
+package auctionv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type StreamAuctionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamAuctionsRequest) Reset() {
+	*x = StreamAuctionsRequest{}
+	mi := &file_proto_auction_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamAuctionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamAuctionsRequest) ProtoMessage() {}
+
+func (x *StreamAuctionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auction_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamAuctionsRequest.ProtoReflect.Descriptor instead.
+func (*StreamAuctionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auction_proto_rawDescGZIP(), []int{0}
+}
+
+type Bid struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	BidderId          int32                  `protobuf:"varint,1,opt,name=bidder_id,json=bidderId,proto3" json:"bidder_id,omitempty"`
+	Amount            float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	AmountCents       int64                  `protobuf:"varint,3,opt,name=amount_cents,json=amountCents,proto3" json:"amount_cents,omitempty"`
+	GroupId           int32                  `protobuf:"varint,4,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	TimestampUnixNano int64                  `protobuf:"varint,5,opt,name=timestamp_unix_nano,json=timestampUnixNano,proto3" json:"timestamp_unix_nano,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Bid) Reset() {
+	*x = Bid{}
+	mi := &file_proto_auction_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Bid) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Bid) ProtoMessage() {}
+
+func (x *Bid) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auction_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Bid.ProtoReflect.Descriptor instead.
+func (*Bid) Descriptor() ([]byte, []int) {
+	return file_proto_auction_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Bid) GetBidderId() int32 {
+	if x != nil {
+		return x.BidderId
+	}
+	return 0
+}
+
+func (x *Bid) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Bid) GetAmountCents() int64 {
+	if x != nil {
+		return x.AmountCents
+	}
+	return 0
+}
+
+func (x *Bid) GetGroupId() int32 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+func (x *Bid) GetTimestampUnixNano() int64 {
+	if x != nil {
+		return x.TimestampUnixNano
+	}
+	return 0
+}
+
+type Auction struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	AuctionId         int32                  `protobuf:"varint,1,opt,name=auction_id,json=auctionId,proto3" json:"auction_id,omitempty"`
+	Attributes        []float64              `protobuf:"fixed64,2,rep,packed,name=attributes,proto3" json:"attributes,omitempty"`
+	TimeoutMs         int64                  `protobuf:"varint,3,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`
+	StartTimeUnixNano int64                  `protobuf:"varint,4,opt,name=start_time_unix_nano,json=startTimeUnixNano,proto3" json:"start_time_unix_nano,omitempty"`
+	EndTimeUnixNano   int64                  `protobuf:"varint,5,opt,name=end_time_unix_nano,json=endTimeUnixNano,proto3" json:"end_time_unix_nano,omitempty"`
+	Bids              []*Bid                 `protobuf:"bytes,6,rep,name=bids,proto3" json:"bids,omitempty"`
+	Winner            *Bid                   `protobuf:"bytes,7,opt,name=winner,proto3" json:"winner,omitempty"`
+	TotalBids         int32                  `protobuf:"varint,8,opt,name=total_bids,json=totalBids,proto3" json:"total_bids,omitempty"`
+	Status            string                 `protobuf:"bytes,9,opt,name=status,proto3" json:"status,omitempty"`
+	TotalPaid         float64                `protobuf:"fixed64,10,opt,name=total_paid,json=totalPaid,proto3" json:"total_paid,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Auction) Reset() {
+	*x = Auction{}
+	mi := &file_proto_auction_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Auction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Auction) ProtoMessage() {}
+
+func (x *Auction) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auction_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Auction.ProtoReflect.Descriptor instead.
+func (*Auction) Descriptor() ([]byte, []int) {
+	return file_proto_auction_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Auction) GetAuctionId() int32 {
+	if x != nil {
+		return x.AuctionId
+	}
+	return 0
+}
+
+func (x *Auction) GetAttributes() []float64 {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *Auction) GetTimeoutMs() int64 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+func (x *Auction) GetStartTimeUnixNano() int64 {
+	if x != nil {
+		return x.StartTimeUnixNano
+	}
+	return 0
+}
+
+func (x *Auction) GetEndTimeUnixNano() int64 {
+	if x != nil {
+		return x.EndTimeUnixNano
+	}
+	return 0
+}
+
+func (x *Auction) GetBids() []*Bid {
+	if x != nil {
+		return x.Bids
+	}
+	return nil
+}
+
+func (x *Auction) GetWinner() *Bid {
+	if x != nil {
+		return x.Winner
+	}
+	return nil
+}
+
+func (x *Auction) GetTotalBids() int32 {
+	if x != nil {
+		return x.TotalBids
+	}
+	return 0
+}
+
+func (x *Auction) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Auction) GetTotalPaid() float64 {
+	if x != nil {
+		return x.TotalPaid
+	}
+	return 0
+}
+
+var File_proto_auction_proto protoreflect.FileDescriptor
+
+const file_proto_auction_proto_rawDesc = "" +
+	"\n" +
+	"\x13proto/auction.proto\x12\n" +
+	"auction.v1\"\x17\n" +
+	"\x15StreamAuctionsRequest\"\xa8\x01\n" +
+	"\x03Bid\x12\x1b\n" +
+	"\tbidder_id\x18\x01 \x01(\x05R\bbidderId\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x01R\x06amount\x12!\n" +
+	"\famount_cents\x18\x03 \x01(\x03R\vamountCents\x12\x19\n" +
+	"\bgroup_id\x18\x04 \x01(\x05R\agroupId\x12.\n" +
+	"\x13timestamp_unix_nano\x18\x05 \x01(\x03R\x11timestampUnixNano\"\xe9\x02\n" +
+	"\aAuction\x12\x1d\n" +
+	"\n" +
+	"auction_id\x18\x01 \x01(\x05R\tauctionId\x12\x1e\n" +
+	"\n" +
+	"attributes\x18\x02 \x03(\x01R\n" +
+	"attributes\x12\x1d\n" +
+	"\n" +
+	"timeout_ms\x18\x03 \x01(\x03R\ttimeoutMs\x12/\n" +
+	"\x14start_time_unix_nano\x18\x04 \x01(\x03R\x11startTimeUnixNano\x12+\n" +
+	"\x12end_time_unix_nano\x18\x05 \x01(\x03R\x0fendTimeUnixNano\x12#\n" +
+	"\x04bids\x18\x06 \x03(\v2\x0f.auction.v1.BidR\x04bids\x12'\n" +
+	"\x06winner\x18\a \x01(\v2\x0f.auction.v1.BidR\x06winner\x12\x1d\n" +
+	"\n" +
+	"total_bids\x18\b \x01(\x05R\ttotalBids\x12\x16\n" +
+	"\x06status\x18\t \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"total_paid\x18\n" +
+	" \x01(\x01R\ttotalPaid2[\n" +
+	"\rAuctionStream\x12J\n" +
+	"\x0eStreamAuctions\x12!.auction.v1.StreamAuctionsRequest\x1a\x13.auction.v1.Auction0\x01B#Z!auction-simulator/proto/auctionv1b\x06proto3"
+
+var (
+	file_proto_auction_proto_rawDescOnce sync.Once
+	file_proto_auction_proto_rawDescData []byte
+)
+
+func file_proto_auction_proto_rawDescGZIP() []byte {
+	file_proto_auction_proto_rawDescOnce.Do(func() {
+		file_proto_auction_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_auction_proto_rawDesc), len(file_proto_auction_proto_rawDesc)))
+	})
+	return file_proto_auction_proto_rawDescData
+}
+
+var file_proto_auction_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_proto_auction_proto_goTypes = []any{
+	(*StreamAuctionsRequest)(nil), // 0: auction.v1.StreamAuctionsRequest
+	(*Bid)(nil),                   // 1: auction.v1.Bid
+	(*Auction)(nil),               // 2: auction.v1.Auction
+}
+var file_proto_auction_proto_depIdxs = []int32{
+	1, // 0: auction.v1.Auction.bids:type_name -> auction.v1.Bid
+	1, // 1: auction.v1.Auction.winner:type_name -> auction.v1.Bid
+	0, // 2: auction.v1.AuctionStream.StreamAuctions:input_type -> auction.v1.StreamAuctionsRequest
+	2, // 3: auction.v1.AuctionStream.StreamAuctions:output_type -> auction.v1.Auction
+	3, // [3:4] is the sub-list for method output_type
+	2, // [2:3] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_proto_auction_proto_init() }
+func file_proto_auction_proto_init() {
+	if File_proto_auction_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_auction_proto_rawDesc), len(file_proto_auction_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_auction_proto_goTypes,
+		DependencyIndexes: file_proto_auction_proto_depIdxs,
+		MessageInfos:      file_proto_auction_proto_msgTypes,
+	}.Build()
+	File_proto_auction_proto = out.File
+	file_proto_auction_proto_goTypes = nil
+	file_proto_auction_proto_depIdxs = nil
+}
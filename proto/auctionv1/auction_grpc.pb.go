@@ -0,0 +1,135 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: proto/auction.proto
+
+// Streaming interface for consumers that want auction results as they finish.
+// The Go messages and service stubs in proto/auctionv1 are generated from this
+// file. After changing it, regenerate them from the repository root with
+//
+//   protoc --go_out=. --go_opt=module=auction-simulator \
+//     --go-grpc_out=. --go-grpc_opt=module=auction-simulator proto/auction.proto
+
+package auctionv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AuctionStream_StreamAuctions_FullMethodName = "/auction.v1.AuctionStream/StreamAuctions"
+)
+
+// AuctionStreamClient is the client API for AuctionStream service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AuctionStreamClient interface {
+	// StreamAuctions sends every auction completed so far, then each further
+	// auction as it completes, and ends when the run finishes.
+	StreamAuctions(ctx context.Context, in *StreamAuctionsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Auction], error)
+}
+
+type auctionStreamClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAuctionStreamClient(cc grpc.ClientConnInterface) AuctionStreamClient {
+	return &auctionStreamClient{cc}
+}
+
+func (c *auctionStreamClient) StreamAuctions(ctx context.Context, in *StreamAuctionsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Auction], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AuctionStream_ServiceDesc.Streams[0], AuctionStream_StreamAuctions_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamAuctionsRequest, Auction]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AuctionStream_StreamAuctionsClient = grpc.ServerStreamingClient[Auction]
+
+// AuctionStreamServer is the server API for AuctionStream service.
+// All implementations must embed UnimplementedAuctionStreamServer
+// for forward compatibility.
+type AuctionStreamServer interface {
+	// StreamAuctions sends every auction completed so far, then each further
+	// auction as it completes, and ends when the run finishes.
+	StreamAuctions(*StreamAuctionsRequest, grpc.ServerStreamingServer[Auction]) error
+	mustEmbedUnimplementedAuctionStreamServer()
+}
+
+// UnimplementedAuctionStreamServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAuctionStreamServer struct{}
+
+func (UnimplementedAuctionStreamServer) StreamAuctions(*StreamAuctionsRequest, grpc.ServerStreamingServer[Auction]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamAuctions not implemented")
+}
+func (UnimplementedAuctionStreamServer) mustEmbedUnimplementedAuctionStreamServer() {}
+func (UnimplementedAuctionStreamServer) testEmbeddedByValue()                       {}
+
+// UnsafeAuctionStreamServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AuctionStreamServer will
+// result in compilation errors.
+type UnsafeAuctionStreamServer interface {
+	mustEmbedUnimplementedAuctionStreamServer()
+}
+
+func RegisterAuctionStreamServer(s grpc.ServiceRegistrar, srv AuctionStreamServer) {
+	// If the following call pancis, it indicates UnimplementedAuctionStreamServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AuctionStream_ServiceDesc, srv)
+}
+
+func _AuctionStream_StreamAuctions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamAuctionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AuctionStreamServer).StreamAuctions(m, &grpc.GenericServerStream[StreamAuctionsRequest, Auction]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AuctionStream_StreamAuctionsServer = grpc.ServerStreamingServer[Auction]
+
+// AuctionStream_ServiceDesc is the grpc.ServiceDesc for AuctionStream service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AuctionStream_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "auction.v1.AuctionStream",
+	HandlerType: (*AuctionStreamServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamAuctions",
+			Handler:       _AuctionStream_StreamAuctions_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/auction.proto",
+}